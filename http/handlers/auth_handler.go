@@ -136,21 +136,27 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate role (only admin can create user or employee, not another admin)
-	if regEmpReq.Role != usermodel.RoleUser && regEmpReq.Role != usermodel.RoleEmployee {
-		response.Error(w, http.StatusBadRequest, "Only 'user' or 'employee' roles can be created by admin")
+	if regEmpReq.Role != usermodel.RoleUser && !usermodel.IsEmployeeRole(regEmpReq.Role) {
+		response.Error(w, http.StatusBadRequest, "Only 'user', 'employee' or 'manager' roles can be created by admin")
 		return
 	}
 
-	// If role is employee, validate employee-specific fields
-	if regEmpReq.Role == usermodel.RoleEmployee {
+	// If role is employee or manager, validate employee-specific fields
+	if usermodel.IsEmployeeRole(regEmpReq.Role) {
 		if regEmpReq.FirstName == "" || regEmpReq.LastName == "" || regEmpReq.Phone == "" || regEmpReq.Position == "" {
-			response.Error(w, http.StatusBadRequest, "For employee role, first_name, last_name, phone, and position are required")
+			response.Error(w, http.StatusBadRequest, "For employee and manager roles, first_name, last_name, phone, and position are required")
 			return
 		}
 		if regEmpReq.Salary < 0 {
 			response.Error(w, http.StatusBadRequest, "Salary cannot be negative")
 			return
 		}
+		if regEmpReq.ManagerID != nil && h.employeeRepo != nil {
+			if _, err := h.employeeRepo.GetEmployeeByID(*regEmpReq.ManagerID); err != nil {
+				response.Error(w, http.StatusBadRequest, "manager_id does not match an existing employee")
+				return
+			}
+		}
 	}
 
 	// Create basic user request
@@ -169,7 +175,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// If employee role and employee repo is available, create employee record
-	if regEmpReq.Role == usermodel.RoleEmployee && h.employeeRepo != nil {
+	if usermodel.IsEmployeeRole(regEmpReq.Role) && h.employeeRepo != nil {
 		hiredDate := regEmpReq.HiredDate
 		if hiredDate == nil {
 			now := time.Now()
@@ -186,7 +192,11 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 			Salary:        regEmpReq.Salary,
 			Gender:        regEmpReq.Gender,
 			MaritalStatus: regEmpReq.MaritalStatus,
+			Location:      regEmpReq.Location,
+			Department:    regEmpReq.Department,
+			Team:          regEmpReq.Team,
 			Hired:         *hiredDate,
+			ManagerID:     regEmpReq.ManagerID,
 		}
 
 		createdEmp, err := h.employeeRepo.CreateEmployee(emp)
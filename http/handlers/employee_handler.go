@@ -207,7 +207,7 @@ func (h *EmployeeHandler) UpdateEmployee(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Update employee
-	emp, err := h.service.UpdateEmployee(id, &req)
+	emp, err := h.service.UpdateEmployee(id, &req, claims.UserID)
 	if err != nil {
 		// Check if it's a validation error
 		if validationErr, ok := err.(*errors.ValidationError); ok {
@@ -229,6 +229,30 @@ func (h *EmployeeHandler) UpdateEmployee(w http.ResponseWriter, r *http.Request)
 	response.Success(w, http.StatusOK, emp, "Employee updated successfully")
 }
 
+// GetCompensationHistory handles GET /employees/{id}/history
+// Returns the employee's salary and position timeline, including scheduled changes
+func (h *EmployeeHandler) GetCompensationHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	claims, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	timeline, err := h.service.GetCompensationHistory(id, claims.UserID, claims.Role == "admin")
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve compensation history")
+		return
+	}
+
+	response.Success(w, http.StatusOK, timeline, "Compensation history retrieved successfully")
+}
+
 // DeleteEmployee handles DELETE /employees/{id}
 func (h *EmployeeHandler) DeleteEmployee(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+
+	"employee-service/errors"
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/leave"
+	"employee-service/models/user"
+)
+
+// requireAdmin writes an error response and returns false unless the caller is an admin
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return false
+	}
+
+	if userCtx.Role != user.RoleAdmin {
+		response.Error(w, http.StatusForbidden, "admin access required")
+		return false
+	}
+
+	return true
+}
+
+// writeServiceError maps service errors to HTTP responses
+func writeServiceError(w http.ResponseWriter, err error, message string) {
+	errors.LogError(message, err)
+
+	if overlapErr, ok := err.(*leave.OverlapError); ok {
+		writeOverlapError(w, overlapErr)
+		return
+	}
+
+	if validationErr, ok := err.(*errors.ValidationError); ok {
+		response.ErrorWithFields(w, http.StatusBadRequest, "Validation failed", validationErr.Fields)
+		return
+	}
+
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.Error(w, appErr.Code, appErr.Message)
+		return
+	}
+
+	if _, ok := err.(*errors.ForbiddenError); ok {
+		response.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if _, ok := err.(*errors.NotFoundErrorType); ok {
+		response.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.Error(w, http.StatusInternalServerError, message+": "+err.Error())
+}
+
+// writeOverlapError reports the leave requests a request conflicts with as a list of IDs
+func writeOverlapError(w http.ResponseWriter, err *leave.OverlapError) {
+	response.ErrorWithDetails(w, http.StatusBadRequest, "Validation failed", err.Fields(), map[string]interface{}{
+		"conflicting_request_ids": err.ConflictingRequestIDs,
+	})
+}
+
+// clientIP returns the address of the client that sent the request, for the audit log
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
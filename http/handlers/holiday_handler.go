@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"employee-service/errors"
+	"employee-service/http/response"
+	"employee-service/models/holiday"
+	holidayService "employee-service/services/holiday"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HolidayHandler handles HTTP requests for the holiday calendar
+type HolidayHandler struct {
+	service *holidayService.Service
+}
+
+// NewHolidayHandler creates a new holiday handler
+func NewHolidayHandler(service *holidayService.Service) *HolidayHandler {
+	return &HolidayHandler{service: service}
+}
+
+// ListHolidays handles GET /admin/holidays?year=2025&location=Bangalore
+func (h *HolidayHandler) ListHolidays(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	year := 0
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid year")
+			return
+		}
+		year = y
+	}
+	location := r.URL.Query().Get("location")
+
+	holidays, err := h.service.ListHolidays(year, location)
+	if err != nil {
+		errors.LogError("ListHolidays failed", err)
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve holidays")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":    len(holidays),
+		"holidays": holidays,
+	}, "Holidays retrieved successfully")
+}
+
+// GetHoliday handles GET /admin/holidays/{id}
+func (h *HolidayHandler) GetHoliday(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	result, err := h.service.GetHoliday(id)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve holiday")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result, "Holiday retrieved successfully")
+}
+
+// CreateHoliday handles POST /admin/holidays
+func (h *HolidayHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req holiday.CreateHolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.CreateHoliday(&req)
+	if err != nil {
+		writeServiceError(w, err, "failed to create holiday")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, result, "Holiday created successfully")
+}
+
+// UpdateHoliday handles PUT /admin/holidays/{id}
+func (h *HolidayHandler) UpdateHoliday(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	var req holiday.UpdateHolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.service.UpdateHoliday(id, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to update holiday")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result, "Holiday updated successfully")
+}
+
+// DeleteHoliday handles DELETE /admin/holidays/{id}
+func (h *HolidayHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid holiday ID")
+		return
+	}
+
+	if err := h.service.DeleteHoliday(id); err != nil {
+		writeServiceError(w, err, "failed to delete holiday")
+		return
+	}
+
+	response.SuccessNoData(w, http.StatusOK, "Holiday deleted successfully")
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"employee-service/http/response"
+	leaveService "employee-service/services/leave"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LeaveAccrualHandler handles HTTP requests for leave accruals and the year-end rollover
+type LeaveAccrualHandler struct {
+	service *leaveService.Service
+}
+
+// NewLeaveAccrualHandler creates a new leave accrual handler
+func NewLeaveAccrualHandler(service *leaveService.Service) *LeaveAccrualHandler {
+	return &LeaveAccrualHandler{service: service}
+}
+
+// RunAccrual handles POST /admin/leave-accruals/run?as_of=2025-10-01
+func (h *LeaveAccrualHandler) RunAccrual(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	asOf := time.Now()
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		parsed, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+			return
+		}
+		asOf = parsed
+	}
+
+	result, err := h.service.RunAccrual(asOf)
+	if err != nil {
+		writeServiceError(w, err, "failed to run leave accrual")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result, "Leave accrual completed")
+}
+
+// GetEmployeeAccruals handles GET /admin/leave-accruals/{employeeId}
+func (h *LeaveAccrualHandler) GetEmployeeAccruals(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	accruals, err := h.service.GetEmployeeAccruals(employeeID)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave accruals")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":          len(accruals),
+		"leave_accruals": accruals,
+	}, "Leave accruals retrieved successfully")
+}
+
+// RunYearEnd handles POST /admin/leave-year-end/{year}?dry_run=true
+func (h *LeaveAccrualHandler) RunYearEnd(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid year")
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dry_run"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid dry_run value")
+			return
+		}
+	}
+
+	report, err := h.service.RunYearEnd(year, dryRun)
+	if err != nil {
+		writeServiceError(w, err, "failed to run leave year-end")
+		return
+	}
+
+	message := "Leave year-end completed"
+	if dryRun {
+		message = "Leave year-end preview generated"
+	}
+	response.Success(w, http.StatusOK, report, message)
+}
+
+// GetYearEndRollovers handles GET /admin/leave-year-end/{year}
+func (h *LeaveAccrualHandler) GetYearEndRollovers(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid year")
+		return
+	}
+
+	rollovers, err := h.service.GetYearEndRollovers(year)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave rollovers")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":           len(rollovers),
+		"leave_rollovers": rollovers,
+	}, "Leave rollovers retrieved successfully")
+}
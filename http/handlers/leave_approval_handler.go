@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/leave"
+	leaveService "employee-service/services/leave"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LeaveApprovalHandler handles HTTP requests for approval chain configuration and approval delegation
+type LeaveApprovalHandler struct {
+	service *leaveService.Service
+}
+
+// NewLeaveApprovalHandler creates a new leave approval handler
+func NewLeaveApprovalHandler(service *leaveService.Service) *LeaveApprovalHandler {
+	return &LeaveApprovalHandler{service: service}
+}
+
+// ListRules handles GET /admin/leave-approval-rules
+func (h *LeaveApprovalHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	rules, err := h.service.ListApprovalRules()
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve approval rules")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":          len(rules),
+		"approval_rules": rules,
+	}, "Approval rules retrieved successfully")
+}
+
+// CreateRule handles POST /admin/leave-approval-rules
+func (h *LeaveApprovalHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req leave.ApprovalRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.service.CreateApprovalRule(&req)
+	if err != nil {
+		writeServiceError(w, err, "failed to create approval rule")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, rule, "Approval rule created successfully")
+}
+
+// UpdateRule handles PUT /admin/leave-approval-rules/{id}
+func (h *LeaveApprovalHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid approval rule ID")
+		return
+	}
+
+	var req leave.ApprovalRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.service.UpdateApprovalRule(id, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to update approval rule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, rule, "Approval rule updated successfully")
+}
+
+// DeleteRule handles DELETE /admin/leave-approval-rules/{id}
+func (h *LeaveApprovalHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid approval rule ID")
+		return
+	}
+
+	if err := h.service.DeleteApprovalRule(id); err != nil {
+		writeServiceError(w, err, "failed to delete approval rule")
+		return
+	}
+
+	response.SuccessNoData(w, http.StatusOK, "Approval rule deleted successfully")
+}
+
+// ListDelegations handles GET /leave/delegations
+func (h *LeaveApprovalHandler) ListDelegations(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	delegations, err := h.service.ListDelegations(userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve approval delegations")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":       len(delegations),
+		"delegations": delegations,
+	}, "Approval delegations retrieved successfully")
+}
+
+// CreateDelegation handles POST /leave/delegations (admin or manager)
+func (h *LeaveApprovalHandler) CreateDelegation(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req leave.CreateDelegationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	delegation, err := h.service.CreateDelegation(userCtx.UserID, userCtx.Role, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to create approval delegation")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, delegation, "Approval delegation created successfully")
+}
+
+// RevokeDelegation handles DELETE /leave/delegations/{id}
+func (h *LeaveApprovalHandler) RevokeDelegation(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid delegation ID")
+		return
+	}
+
+	if err := h.service.RevokeDelegation(id, userCtx.UserID, userCtx.Role); err != nil {
+		writeServiceError(w, err, "failed to revoke approval delegation")
+		return
+	}
+
+	response.SuccessNoData(w, http.StatusOK, "Approval delegation revoked successfully")
+}
+
+// SetDefaultDelegate handles PUT /leave/delegations/default (admin or manager)
+func (h *LeaveApprovalHandler) SetDefaultDelegate(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req leave.DefaultDelegateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.SetDefaultDelegate(userCtx.UserID, userCtx.Role, &req); err != nil {
+		writeServiceError(w, err, "failed to set default delegate")
+		return
+	}
+
+	response.Success(w, http.StatusOK, req, "Default delegate updated successfully")
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"employee-service/errors"
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/leave"
+	"employee-service/models/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// multipartOverhead is room for form fields and part headers on top of the files themselves
+const multipartOverhead = 1 << 20
+
+// AddAttachments handles POST /leave/{id}/attachments
+// Uploads supporting documents as multipart "attachments" files to a pending or approved request
+func (h *LeaveHandler) AddAttachments(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !user.IsEmployeeRole(userCtx.Role) {
+		response.Error(w, http.StatusForbidden, "only employees can add attachments to leave requests")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	uploads, err := h.readAttachmentUploads(w, r)
+	if err != nil {
+		writeServiceError(w, err, "failed to read attachments")
+		return
+	}
+
+	attachments, err := h.service.AddAttachments(id, userCtx.UserID, uploads)
+	if err != nil {
+		writeServiceError(w, err, "failed to add attachments")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, attachments, "Attachments uploaded successfully")
+}
+
+// GetAttachments handles GET /leave/{id}/attachments
+func (h *LeaveHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(id, userCtx.UserID, userCtx.Role)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve attachments")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"leave_request_id": id,
+		"count":            len(attachments),
+		"attachments":      attachments,
+	}, "Attachments retrieved successfully")
+}
+
+// DownloadAttachment handles GET /leave/{id}/attachments/{attachmentId}
+func (h *LeaveHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+	attachmentID, err := strconv.Atoi(chi.URLParam(r, "attachmentId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid attachment ID")
+		return
+	}
+
+	attachment, content, err := h.service.OpenAttachment(id, attachmentID, userCtx.UserID, userCtx.Role)
+	if err != nil {
+		writeServiceError(w, err, "failed to download attachment")
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	response.File(w, attachment.ContentType, attachment.FileName, content)
+}
+
+// decodeApplyLeave reads a leave application sent either as JSON or as a multipart form with the
+// JSON in a "request" field and supporting documents as "attachments" files
+func (h *LeaveHandler) decodeApplyLeave(w http.ResponseWriter, r *http.Request) (*leave.ApplyLeaveRequest, []leave.AttachmentUpload, error) {
+	var req leave.ApplyLeaveRequest
+	uploads, err := h.decodeLeaveApplication(w, r, &req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &req, uploads, nil
+}
+
+// decodeLeaveApplication decodes a leave application into req, from a JSON body or the "request"
+// field of a multipart form, and returns any uploaded supporting documents
+func (h *LeaveHandler) decodeLeaveApplication(w http.ResponseWriter, r *http.Request, req interface{}) ([]leave.AttachmentUpload, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, errors.BadRequestError("Invalid request body")
+		}
+		return nil, nil
+	}
+
+	uploads, err := h.readAttachmentUploads(w, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(r.FormValue("request")), req); err != nil {
+		return nil, errors.BadRequestError(`Invalid "request" form field`)
+	}
+	return uploads, nil
+}
+
+// readAttachmentUploads reads the "attachments" files of a multipart request. The body is capped
+// at what the attachment limits allow, and content types are detected from the file content.
+func (h *LeaveHandler) readAttachmentUploads(w http.ResponseWriter, r *http.Request) ([]leave.AttachmentUpload, error) {
+	limits := h.service.AttachmentLimits()
+	r.Body = http.MaxBytesReader(w, r.Body, limits.MaxSize*int64(limits.MaxFiles)+multipartOverhead)
+
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		return nil, errors.BadRequestError(fmt.Sprintf("invalid multipart form; attachments are limited to %d files", limits.MaxFiles))
+	}
+
+	files := r.MultipartForm.File["attachments"]
+	uploads := make([]leave.AttachmentUpload, 0, len(files))
+	for _, header := range files {
+		upload := leave.AttachmentUpload{FileName: leave.CleanFileName(header.Filename)}
+
+		file, err := header.Open()
+		if err != nil {
+			return nil, errors.BadRequestError(fmt.Sprintf("failed to read %s", upload.FileName))
+		}
+		// Read one byte past the limit so oversized files fail the size check
+		upload.Content, err = io.ReadAll(io.LimitReader(file, limits.MaxSize+1))
+		file.Close()
+		if err != nil {
+			return nil, errors.BadRequestError(fmt.Sprintf("failed to read %s", upload.FileName))
+		}
+
+		upload.ContentType = http.DetectContentType(upload.Content)
+		uploads = append(uploads, upload)
+	}
+	return uploads, nil
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/leave"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTeamCalendar handles GET /leave/calendar?from=YYYY-MM-DD&to=YYYY-MM-DD (admins and managers)
+// Optional group_by (EMPLOYEE, TEAM or DEPARTMENT), team and department query parameters
+func (h *LeaveHandler) GetTeamCalendar(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	query := &leave.CalendarQuery{
+		From:       r.URL.Query().Get("from"),
+		To:         r.URL.Query().Get("to"),
+		GroupBy:    leave.CalendarGrouping(r.URL.Query().Get("group_by")),
+		Team:       r.URL.Query().Get("team"),
+		Department: r.URL.Query().Get("department"),
+	}
+
+	groups, err := h.service.GetTeamCalendar(userCtx.UserID, userCtx.Role, query)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve team calendar")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"from":     query.From,
+		"to":       query.To,
+		"group_by": query.GroupBy,
+		"count":    len(groups),
+		"groups":   groups,
+	}, "Team calendar retrieved successfully")
+}
+
+// ListCoverageRules handles GET /admin/leave-coverage-rules
+func (h *LeaveHandler) ListCoverageRules(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	rules, err := h.service.ListCoverageRules()
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve coverage rules")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":          len(rules),
+		"coverage_rules": rules,
+	}, "Coverage rules retrieved successfully")
+}
+
+// CreateCoverageRule handles POST /admin/leave-coverage-rules
+func (h *LeaveHandler) CreateCoverageRule(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req leave.CoverageRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.service.CreateCoverageRule(&req)
+	if err != nil {
+		writeServiceError(w, err, "failed to create coverage rule")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, rule, "Coverage rule created successfully")
+}
+
+// UpdateCoverageRule handles PUT /admin/leave-coverage-rules/{id}
+func (h *LeaveHandler) UpdateCoverageRule(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid coverage rule ID")
+		return
+	}
+
+	var req leave.CoverageRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.service.UpdateCoverageRule(id, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to update coverage rule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, rule, "Coverage rule updated successfully")
+}
+
+// DeleteCoverageRule handles DELETE /admin/leave-coverage-rules/{id}
+func (h *LeaveHandler) DeleteCoverageRule(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid coverage rule ID")
+		return
+	}
+
+	if err := h.service.DeleteCoverageRule(id); err != nil {
+		writeServiceError(w, err, "failed to delete coverage rule")
+		return
+	}
+
+	response.SuccessNoData(w, http.StatusOK, "Coverage rule deleted successfully")
+}
+
+// GetCalendarFeed handles GET /leave/calendar-feed: the user's calendar feed link
+func (h *LeaveHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	feed, err := h.service.GetCalendarFeed(userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve calendar feed")
+		return
+	}
+	feed.URL = calendarFeedURL(r, feed.Token)
+
+	response.Success(w, http.StatusOK, feed, "Calendar feed retrieved successfully")
+}
+
+// CreateCalendarFeed handles POST /leave/calendar-feed: a new calendar feed link, replacing the
+// user's current one
+func (h *LeaveHandler) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	feed, err := h.service.CreateCalendarFeed(userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to create calendar feed")
+		return
+	}
+	feed.URL = calendarFeedURL(r, feed.Token)
+
+	response.Success(w, http.StatusCreated, feed, "Calendar feed created successfully")
+}
+
+// RevokeCalendarFeed handles DELETE /leave/calendar-feed
+func (h *LeaveHandler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.service.RevokeCalendarFeed(userCtx.UserID); err != nil {
+		writeServiceError(w, err, "failed to revoke calendar feed")
+		return
+	}
+
+	response.SuccessNoData(w, http.StatusOK, "Calendar feed revoked successfully")
+}
+
+// GetCalendarFeedICS handles GET /calendar-feeds/{token}.ics (no JWT; the signed token is the credential)
+func (h *LeaveHandler) GetCalendarFeedICS(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(chi.URLParam(r, "token"), ".ics")
+
+	data, err := h.service.RenderCalendarFeed(token)
+	if err != nil {
+		writeServiceError(w, err, "failed to render calendar feed")
+		return
+	}
+
+	response.File(w, "text/calendar; charset=utf-8", "leave.ics", data)
+}
+
+// calendarFeedURL builds the address calendar apps subscribe to, on the host the request came to
+func calendarFeedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + "/calendar-feeds/" + token + ".ics"
+}
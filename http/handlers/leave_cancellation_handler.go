@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/leave"
+	"employee-service/models/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RequestCancellation handles POST /leave/{id}/cancellation
+// Asks for approved leave to be cancelled in full, or from from_date when returning early
+func (h *LeaveHandler) RequestCancellation(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !user.IsEmployeeRole(userCtx.Role) {
+		response.Error(w, http.StatusForbidden, "only employees can cancel leave requests")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	var req leave.CancelApprovedLeaveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cancellation, err := h.service.RequestCancellation(id, userCtx.UserID, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to request leave cancellation")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, cancellation, "Leave cancellation requested; awaiting approval")
+}
+
+// ListCancellations handles GET /leave/cancellations?status=PENDING (approvers and their delegates)
+func (h *LeaveHandler) ListCancellations(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	status := strings.ToUpper(r.URL.Query().Get("status"))
+
+	cancellations, err := h.service.ListCancellations(userCtx.UserID, userCtx.Role, status)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave cancellations")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":         len(cancellations),
+		"cancellations": cancellations,
+	}, "Leave cancellations retrieved successfully")
+}
+
+// ApproveCancellation handles POST /leave/cancellations/{id}/approve (approvers and their delegates)
+func (h *LeaveHandler) ApproveCancellation(w http.ResponseWriter, r *http.Request) {
+	h.decideCancellation(w, r, true)
+}
+
+// RejectCancellation handles POST /leave/cancellations/{id}/reject (approvers and their delegates)
+func (h *LeaveHandler) RejectCancellation(w http.ResponseWriter, r *http.Request) {
+	h.decideCancellation(w, r, false)
+}
+
+// decideCancellation approves or rejects a cancellation request with an optional comment
+func (h *LeaveHandler) decideCancellation(w http.ResponseWriter, r *http.Request, approve bool) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid cancellation ID")
+		return
+	}
+
+	// The comment is optional, so an empty body is fine
+	var req leave.DecideCancellationRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	if approve {
+		cancellation, err := h.service.ApproveCancellation(id, userCtx.UserID, userCtx.Role, req.Comment)
+		if err != nil {
+			writeServiceError(w, err, "failed to approve leave cancellation")
+			return
+		}
+		response.Success(w, http.StatusOK, cancellation, "Leave cancellation approved successfully")
+		return
+	}
+
+	cancellation, err := h.service.RejectCancellation(id, userCtx.UserID, userCtx.Role, req.Comment)
+	if err != nil {
+		writeServiceError(w, err, "failed to reject leave cancellation")
+		return
+	}
+	response.Success(w, http.StatusOK, cancellation, "Leave cancellation rejected")
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/leave"
+	"employee-service/models/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RequestCompOff handles POST /leave/comp-off
+// Asks for comp-off to be credited for a weekend or holiday worked
+func (h *LeaveHandler) RequestCompOff(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !user.IsEmployeeRole(userCtx.Role) {
+		response.Error(w, http.StatusForbidden, "only employees can request comp-off")
+		return
+	}
+
+	var req leave.RequestCompOffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	credit, err := h.service.RequestCompOff(userCtx.UserID, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to request comp-off")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, credit, "Comp-off requested; awaiting approval")
+}
+
+// GetMyCompOffCredits handles GET /leave/comp-off/mine
+func (h *LeaveHandler) GetMyCompOffCredits(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	credits, err := h.service.GetMyCompOffCredits(userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve comp-off credits")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":            len(credits),
+		"comp_off_credits": credits,
+	}, "Comp-off credits retrieved successfully")
+}
+
+// ListCompOffCredits handles GET /leave/comp-off?status=PENDING (approvers and their delegates)
+func (h *LeaveHandler) ListCompOffCredits(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	status := strings.ToUpper(r.URL.Query().Get("status"))
+
+	credits, err := h.service.ListCompOffCredits(userCtx.UserID, userCtx.Role, status)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve comp-off credits")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":            len(credits),
+		"comp_off_credits": credits,
+	}, "Comp-off credits retrieved successfully")
+}
+
+// ApproveCompOff handles POST /leave/comp-off/{id}/approve (approvers and their delegates)
+func (h *LeaveHandler) ApproveCompOff(w http.ResponseWriter, r *http.Request) {
+	h.decideCompOff(w, r, true)
+}
+
+// RejectCompOff handles POST /leave/comp-off/{id}/reject (approvers and their delegates)
+func (h *LeaveHandler) RejectCompOff(w http.ResponseWriter, r *http.Request) {
+	h.decideCompOff(w, r, false)
+}
+
+// decideCompOff approves or rejects a comp-off request with an optional comment
+func (h *LeaveHandler) decideCompOff(w http.ResponseWriter, r *http.Request, approve bool) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid comp-off ID")
+		return
+	}
+
+	// The comment is optional, so an empty body is fine
+	var req leave.DecideCompOffRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	if approve {
+		credit, err := h.service.ApproveCompOff(id, userCtx.UserID, userCtx.Role, req.Comment)
+		if err != nil {
+			writeServiceError(w, err, "failed to approve comp-off")
+			return
+		}
+		response.Success(w, http.StatusOK, credit, "Comp-off approved and credited")
+		return
+	}
+
+	credit, err := h.service.RejectCompOff(id, userCtx.UserID, userCtx.Role, req.Comment)
+	if err != nil {
+		writeServiceError(w, err, "failed to reject comp-off")
+		return
+	}
+	response.Success(w, http.StatusOK, credit, "Comp-off rejected")
+}
+
+// RunCompOffExpiry handles POST /admin/leave-comp-off/expire?as_of=2026-06-01
+// Lapses expired comp-off now instead of waiting for the scheduled run
+func (h *LeaveHandler) RunCompOffExpiry(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	asOf := time.Now()
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		parsed, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid as_of date, expected YYYY-MM-DD")
+			return
+		}
+		asOf = parsed
+	}
+
+	result, err := h.service.RunCompOffExpiry(asOf)
+	if err != nil {
+		writeServiceError(w, err, "failed to run comp-off expiry")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result, "Comp-off expiry completed")
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"employee-service/errors"
 	"employee-service/http/middlewares"
@@ -26,6 +28,7 @@ func NewLeaveHandler(service *leaveService.Service) *LeaveHandler {
 }
 
 // ApplyLeave handles POST /leave/apply
+// Accepts JSON, or a multipart form with the JSON in "request" and files in "attachments"
 func (h *LeaveHandler) ApplyLeave(w http.ResponseWriter, r *http.Request) {
 	// Get user from context
 	userCtx, err := middlewares.GetUserFromContext(r)
@@ -35,25 +38,30 @@ func (h *LeaveHandler) ApplyLeave(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ✔ Only employees can apply for leave
-	if userCtx.Role != user.RoleEmployee {
+	if !user.IsEmployeeRole(userCtx.Role) {
 		response.Error(w, http.StatusForbidden, "only employees can apply for leave")
 		return
 	}
 
-	var req leave.ApplyLeaveRequest
-
-	// Decode request body
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	// Decode request body, with any supporting documents when sent as a multipart form
+	req, uploads, err := h.decodeApplyLeave(w, r)
+	if err != nil {
+		writeServiceError(w, err, "failed to read leave application")
 		return
 	}
 
 	// Apply leave - Use UserID which represents the employee for this system
-	leaveRequest, err := h.service.ApplyLeave(userCtx.UserID, &req)
+	leaveRequest, err := h.service.ApplyLeave(userCtx.UserID, req, uploads)
 	if err != nil {
 		// Log the actual error for debugging
 		errors.LogError("ApplyLeave failed", err)
-		
+
+		// Name the requests the new one overlaps
+		if overlapErr, ok := err.(*leave.OverlapError); ok {
+			writeOverlapError(w, overlapErr)
+			return
+		}
+
 		// Check if it's a validation error
 		if validationErr, ok := err.(*errors.ValidationError); ok {
 			response.ErrorWithFields(w, http.StatusBadRequest, "Validation failed", validationErr.Fields)
@@ -68,13 +76,132 @@ func (h *LeaveHandler) ApplyLeave(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		response.Error(w, http.StatusInternalServerError, "failed to apply leave: " + err.Error())
+		response.Error(w, http.StatusInternalServerError, "failed to apply leave: "+err.Error())
 		return
 	}
 
 	response.Success(w, http.StatusCreated, leaveRequest, "Leave request submitted successfully")
 }
 
+// UpdateLeave handles PUT /leave/{id}
+// Edits a pending leave request; the body has the same fields as an application
+func (h *LeaveHandler) UpdateLeave(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !user.IsEmployeeRole(userCtx.Role) {
+		response.Error(w, http.StatusForbidden, "only employees can edit leave requests")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	var req leave.ApplyLeaveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	leaveRequest, err := h.service.UpdateLeave(id, userCtx.UserID, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to update leave request")
+		return
+	}
+
+	response.Success(w, http.StatusOK, leaveRequest, "Leave request updated successfully")
+}
+
+// GetLeaveRevisions handles GET /leave/{id}/revisions
+func (h *LeaveHandler) GetLeaveRevisions(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	revisions, err := h.service.GetLeaveRevisions(id, userCtx.UserID, userCtx.Role)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave request revisions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"leave_request_id": id,
+		"count":            len(revisions),
+		"revisions":        revisions,
+	}, "Leave request revisions retrieved successfully")
+}
+
+// AddComment handles POST /leave/{id}/comments
+// Approvers can set request_info to ask the employee for more information before deciding
+func (h *LeaveHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	var req leave.AddCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comment, err := h.service.AddComment(id, userCtx.UserID, userCtx.Role, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to add comment")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, comment, "Comment added successfully")
+}
+
+// GetComments handles GET /leave/{id}/comments
+func (h *LeaveHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	comments, err := h.service.GetComments(id, userCtx.UserID, userCtx.Role)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve comments")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"leave_request_id": id,
+		"count":            len(comments),
+		"comments":         comments,
+	}, "Comments retrieved successfully")
+}
+
 // GetMyLeaveRequests handles GET /leave/my-requests
 func (h *LeaveHandler) GetMyLeaveRequests(w http.ResponseWriter, r *http.Request) {
 	// Get user from context
@@ -85,7 +212,7 @@ func (h *LeaveHandler) GetMyLeaveRequests(w http.ResponseWriter, r *http.Request
 	}
 
 	// Only employees can view their own leave requests
-	if userCtx.Role != user.RoleEmployee {
+	if !user.IsEmployeeRole(userCtx.Role) {
 		response.Error(w, http.StatusForbidden, "only employees can view leave requests")
 		return
 	}
@@ -133,7 +260,7 @@ func (h *LeaveHandler) CancelLeave(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Only employees can cancel leave requests
-	if userCtx.Role != user.RoleEmployee {
+	if !user.IsEmployeeRole(userCtx.Role) {
 		response.Error(w, http.StatusForbidden, "only employees can cancel leave requests")
 		return
 	}
@@ -173,42 +300,22 @@ func (h *LeaveHandler) CancelLeave(w http.ResponseWriter, r *http.Request) {
 	response.SuccessNoData(w, http.StatusOK, "Leave request cancelled successfully")
 }
 
-// GetAllLeaveRequests handles GET /leave/all (admin only)
+// GetAllLeaveRequests handles GET /leave/all (approvers and their delegates)
 func (h *LeaveHandler) GetAllLeaveRequests(w http.ResponseWriter, r *http.Request) {
-	// Get user from context and verify admin role
+	// Get user from context; approval rights, own or delegated, are checked by the service
 	userCtx, err := middlewares.GetUserFromContext(r)
 	if err != nil {
 		response.Error(w, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	if userCtx.Role != user.RoleAdmin {
-		response.Error(w, http.StatusForbidden, "admin access required")
-		return
-	}
-
 	// Get query parameters for filtering
 	statusFilter := r.URL.Query().Get("status")
 
 	// Get all leave requests
-	leaveRequests, err := h.service.GetAllLeaveRequests(statusFilter)
+	leaveRequests, err := h.service.GetAllLeaveRequests(userCtx.UserID, userCtx.Role, statusFilter)
 	if err != nil {
-		// Log the actual error for debugging
-		errors.LogError("GetAllLeaveRequests failed", err)
-		
-		if validationErr, ok := err.(*errors.ValidationError); ok {
-			response.ErrorWithFields(w, http.StatusBadRequest, "Validation failed", validationErr.Fields)
-			return
-		}
-
-		if appErr, ok := err.(*errors.AppError); ok {
-			if appErr.Code == 404 {
-				response.Error(w, http.StatusNotFound, appErr.Message)
-				return
-			}
-		}
-
-		response.Error(w, http.StatusInternalServerError, "failed to retrieve leave requests: " + err.Error())
+		writeServiceError(w, err, "failed to retrieve leave requests")
 		return
 	}
 
@@ -218,20 +325,15 @@ func (h *LeaveHandler) GetAllLeaveRequests(w http.ResponseWriter, r *http.Reques
 	}, "Leave requests retrieved successfully")
 }
 
-// ApproveLeave handles POST /leave/approve/:id (admin only)
+// ApproveLeave handles POST /leave/approve/:id (approvers and their delegates)
 func (h *LeaveHandler) ApproveLeave(w http.ResponseWriter, r *http.Request) {
-	// Get user from context and verify admin role
+	// Get user from context; approval rights, own or delegated, are checked by the service
 	userCtx, err := middlewares.GetUserFromContext(r)
 	if err != nil {
 		response.Error(w, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	if userCtx.Role != user.RoleAdmin {
-		response.Error(w, http.StatusForbidden, "admin access required")
-		return
-	}
-
 	// Get leave request ID from URL
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -250,50 +352,30 @@ func (h *LeaveHandler) ApproveLeave(w http.ResponseWriter, r *http.Request) {
 		notes = req.Notes
 	}
 
-	// Approve leave - Use UserID which represents the admin user
-	err = h.service.ApproveLeave(id, userCtx.UserID, notes)
+	// Approve leave - Use UserID which represents the approving user
+	leaveRequest, err := h.service.ApproveLeave(id, userCtx.UserID, userCtx.Role, notes)
 	if err != nil {
-		// Log the actual error for debugging
-		errors.LogError("ApproveLeave failed", err)
-		
-		if validationErr, ok := err.(*errors.ValidationError); ok {
-			response.ErrorWithFields(w, http.StatusBadRequest, "Validation failed", validationErr.Fields)
-			return
-		}
-
-		if appErr, ok := err.(*errors.AppError); ok {
-			if appErr.Code == 404 {
-				response.Error(w, http.StatusNotFound, appErr.Message)
-				return
-			}
-		}
-
-		if _, ok := err.(*errors.NotFoundErrorType); ok {
-			response.Error(w, http.StatusNotFound, err.Error())
-			return
-		}
+		writeServiceError(w, err, "failed to approve leave request")
+		return
+	}
 
-		response.Error(w, http.StatusInternalServerError, "failed to approve leave request: " + err.Error())
+	if leaveRequest.Status != leave.StatusApproved {
+		response.Success(w, http.StatusOK, leaveRequest, "Approval step recorded; awaiting the next approver")
 		return
 	}
 
-	response.SuccessNoData(w, http.StatusOK, "Leave request approved successfully")
+	response.Success(w, http.StatusOK, leaveRequest, "Leave request approved successfully")
 }
 
-// RejectLeave handles POST /leave/reject/:id (admin only)
+// RejectLeave handles POST /leave/reject/:id (approvers and their delegates)
 func (h *LeaveHandler) RejectLeave(w http.ResponseWriter, r *http.Request) {
-	// Get user from context and verify admin role
+	// Get user from context; approval rights, own or delegated, are checked by the service
 	userCtx, err := middlewares.GetUserFromContext(r)
 	if err != nil {
 		response.Error(w, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	if userCtx.Role != user.RoleAdmin {
-		response.Error(w, http.StatusForbidden, "admin access required")
-		return
-	}
-
 	// Get leave request ID from URL
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
@@ -309,34 +391,38 @@ func (h *LeaveHandler) RejectLeave(w http.ResponseWriter, r *http.Request) {
 		req.Reason = ""
 	}
 
-	// Reject leave - Use UserID which represents the admin user
-	err = h.service.RejectLeave(id, userCtx.UserID, req.Reason)
+	// Reject leave - Use UserID which represents the approving user
+	err = h.service.RejectLeave(id, userCtx.UserID, userCtx.Role, req.Reason)
 	if err != nil {
-		// Log the actual error for debugging
-		errors.LogError("RejectLeave failed", err)
-		
-		if validationErr, ok := err.(*errors.ValidationError); ok {
-			response.ErrorWithFields(w, http.StatusBadRequest, "Validation failed", validationErr.Fields)
-			return
-		}
+		writeServiceError(w, err, "failed to reject leave request")
+		return
+	}
 
-		if appErr, ok := err.(*errors.AppError); ok {
-			if appErr.Code == 404 {
-				response.Error(w, http.StatusNotFound, appErr.Message)
-				return
-			}
-		}
+	response.SuccessNoData(w, http.StatusOK, "Leave request rejected successfully")
+}
 
-		if _, ok := err.(*errors.NotFoundErrorType); ok {
-			response.Error(w, http.StatusNotFound, err.Error())
-			return
-		}
+// BulkDecide handles POST /leave/decisions (approvers and their delegates)
+// Approves or rejects a list of leave requests with a shared note and reports the outcome of each
+func (h *LeaveHandler) BulkDecide(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
 
-		response.Error(w, http.StatusInternalServerError, "failed to reject leave request: " + err.Error())
+	var req leave.BulkDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	response.SuccessNoData(w, http.StatusOK, "Leave request rejected successfully")
+	result, err := h.service.BulkDecide(userCtx.UserID, userCtx.Role, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to decide leave requests")
+		return
+	}
+
+	response.Success(w, http.StatusOK, result, fmt.Sprintf("%d leave request(s) decided, %d failed", result.Succeeded, result.Failed))
 }
 
 // ... rest of the code remains the same ...
@@ -349,7 +435,7 @@ func (h *LeaveHandler) GetMyLeaveBalance(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Only employees can view their own leave balance
-	if userCtx.Role != user.RoleEmployee {
+	if !user.IsEmployeeRole(userCtx.Role) {
 		response.Error(w, http.StatusForbidden, "only employees can view leave balance")
 		return
 	}
@@ -362,7 +448,7 @@ func (h *LeaveHandler) GetMyLeaveBalance(w http.ResponseWriter, r *http.Request)
 	}
 
 	response.Success(w, http.StatusOK, map[string]interface{}{
-		"count":           len(balances),
+		"count":          len(balances),
 		"leave_balances": balances,
 	}, "Leave balance retrieved successfully")
 }
@@ -377,7 +463,7 @@ func (h *LeaveHandler) GetMyLeaveBalanceByType(w http.ResponseWriter, r *http.Re
 	}
 
 	// Only employees can view their own leave balance
-	if userCtx.Role != user.RoleEmployee {
+	if !user.IsEmployeeRole(userCtx.Role) {
 		response.Error(w, http.StatusForbidden, "only employees can view leave balance")
 		return
 	}
@@ -403,40 +489,130 @@ func (h *LeaveHandler) GetMyLeaveBalanceByType(w http.ResponseWriter, r *http.Re
 	response.Success(w, http.StatusOK, balance, "Leave balance retrieved successfully")
 }
 
-// ReviewLeaveRequests handles GET /leave/review (admin only)
-// Returns pending leave requests for review
-func (h *LeaveHandler) ReviewLeaveRequests(w http.ResponseWriter, r *http.Request) {
-	// Get user from context and verify admin role
+// GetMyLeaveBalanceHistory handles GET /leave/balance/{type}/history
+func (h *LeaveHandler) GetMyLeaveBalanceHistory(w http.ResponseWriter, r *http.Request) {
 	userCtx, err := middlewares.GetUserFromContext(r)
 	if err != nil {
 		response.Error(w, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	if userCtx.Role != user.RoleAdmin {
-		response.Error(w, http.StatusForbidden, "admin access required")
+	// Only employees can view their own leave balance
+	if !user.IsEmployeeRole(userCtx.Role) {
+		response.Error(w, http.StatusForbidden, "only employees can view leave balance")
 		return
 	}
 
-	// Get pending leave requests only (for review)
-	leaveRequests, err := h.service.GetAllLeaveRequests(string(leave.StatusPending))
+	leaveType := leave.LeaveType(strings.ToUpper(chi.URLParam(r, "type")))
+
+	entries, err := h.service.GetLeaveBalanceHistory(userCtx.UserID, leaveType)
 	if err != nil {
-		// Log the actual error for debugging
-		errors.LogError("ReviewLeaveRequests failed", err)
-		
-		if validationErr, ok := err.(*errors.ValidationError); ok {
-			response.ErrorWithFields(w, http.StatusBadRequest, "Validation failed", validationErr.Fields)
-			return
-		}
+		writeServiceError(w, err, "failed to retrieve leave balance history")
+		return
+	}
 
-		if appErr, ok := err.(*errors.AppError); ok {
-			if appErr.Code == 404 {
-				response.Error(w, http.StatusNotFound, appErr.Message)
-				return
-			}
-		}
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"leave_type": leaveType,
+		"count":      len(entries),
+		"entries":    entries,
+	}, "Leave balance history retrieved successfully")
+}
+
+// GetEmployeeLeaveBalanceHistory handles GET /admin/leave-balances/{employeeId}/{type}/history
+func (h *LeaveHandler) GetEmployeeLeaveBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+	leaveType := leave.LeaveType(strings.ToUpper(chi.URLParam(r, "type")))
+
+	entries, err := h.service.GetEmployeeLeaveBalanceHistory(employeeID, leaveType)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave balance history")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"employee_id": employeeID,
+		"leave_type":  leaveType,
+		"count":       len(entries),
+		"entries":     entries,
+	}, "Leave balance history retrieved successfully")
+}
+
+// AdjustLeaveBalance handles POST /admin/leave-balances/{employeeId}/adjust
+func (h *LeaveHandler) AdjustLeaveBalance(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	userCtx, _ := middlewares.GetUserFromContext(r)
+
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	var req leave.AdjustLeaveBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	balance, err := h.service.AdjustLeaveBalance(employeeID, userCtx.UserID, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to adjust leave balance")
+		return
+	}
+
+	response.Success(w, http.StatusOK, balance, "Leave balance adjusted successfully")
+}
+
+// ApplyLeaveForEmployee handles POST /admin/leave-requests
+// Records leave on behalf of an employee, optionally approving it and overriding the leave policy
+func (h *LeaveHandler) ApplyLeaveForEmployee(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	userCtx, _ := middlewares.GetUserFromContext(r)
+
+	// Decode request body, with any supporting documents when sent as a multipart form
+	var req leave.AdminApplyLeaveRequest
+	uploads, err := h.decodeLeaveApplication(w, r, &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to read leave application")
+		return
+	}
+
+	leaveRequest, err := h.service.ApplyLeaveForEmployee(userCtx.UserID, userCtx.Role, &req, uploads, clientIP(r))
+	if err != nil {
+		errors.LogError("ApplyLeaveForEmployee failed", err)
+		writeServiceError(w, err, "failed to enter leave request")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, leaveRequest, "Leave request entered successfully")
+}
+
+// ReviewLeaveRequests handles GET /leave/review (approvers and their delegates)
+// Returns pending leave requests for review
+func (h *LeaveHandler) ReviewLeaveRequests(w http.ResponseWriter, r *http.Request) {
+	// Get user from context; approval rights, own or delegated, are checked by the service
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
 
-		response.Error(w, http.StatusInternalServerError, "failed to retrieve pending leave requests: " + err.Error())
+	// Get pending leave requests only (for review)
+	leaveRequests, err := h.service.GetAllLeaveRequests(userCtx.UserID, userCtx.Role, string(leave.StatusPending))
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve pending leave requests")
 		return
 	}
 
@@ -444,4 +620,31 @@ func (h *LeaveHandler) ReviewLeaveRequests(w http.ResponseWriter, r *http.Reques
 		"count":          len(leaveRequests),
 		"leave_requests": leaveRequests,
 	}, "Pending leave requests retrieved successfully")
-}
\ No newline at end of file
+}
+
+// GetLeaveApprovals handles GET /leave/{id}/approvals
+func (h *LeaveHandler) GetLeaveApprovals(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid leave request ID")
+		return
+	}
+
+	approvals, err := h.service.GetLeaveApprovals(id, userCtx.UserID, userCtx.Role)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave approvals")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"leave_request_id": id,
+		"count":            len(approvals),
+		"approvals":        approvals,
+	}, "Leave approvals retrieved successfully")
+}
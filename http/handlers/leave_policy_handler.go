@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"employee-service/http/response"
+	"employee-service/models/leave"
+	leaveService "employee-service/services/leave"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LeavePolicyHandler handles HTTP requests for leave policy management
+type LeavePolicyHandler struct {
+	service *leaveService.Service
+}
+
+// NewLeavePolicyHandler creates a new leave policy handler
+func NewLeavePolicyHandler(service *leaveService.Service) *LeavePolicyHandler {
+	return &LeavePolicyHandler{service: service}
+}
+
+// ListPolicies handles GET /admin/leave-policies
+func (h *LeavePolicyHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	policies, err := h.service.ListLeavePolicies()
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave policies")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":          len(policies),
+		"leave_policies": policies,
+	}, "Leave policies retrieved successfully")
+}
+
+// GetPolicy handles GET /admin/leave-policies/{type}
+func (h *LeavePolicyHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	policy, err := h.service.GetLeavePolicy(leaveTypeParam(r))
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve leave policy")
+		return
+	}
+
+	response.Success(w, http.StatusOK, policy, "Leave policy retrieved successfully")
+}
+
+// CreatePolicy handles POST /admin/leave-policies
+func (h *LeavePolicyHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req leave.CreateLeavePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	policy, err := h.service.CreateLeavePolicy(&req)
+	if err != nil {
+		writeServiceError(w, err, "failed to create leave policy")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, policy, "Leave policy created successfully")
+}
+
+// UpdatePolicy handles PUT /admin/leave-policies/{type}
+func (h *LeavePolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req leave.UpdateLeavePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	policy, err := h.service.UpdateLeavePolicy(leaveTypeParam(r), &req)
+	if err != nil {
+		writeServiceError(w, err, "failed to update leave policy")
+		return
+	}
+
+	response.Success(w, http.StatusOK, policy, "Leave policy updated successfully")
+}
+
+// DeletePolicy handles DELETE /admin/leave-policies/{type}
+func (h *LeavePolicyHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	if err := h.service.DeleteLeavePolicy(leaveTypeParam(r)); err != nil {
+		writeServiceError(w, err, "failed to delete leave policy")
+		return
+	}
+
+	response.SuccessNoData(w, http.StatusOK, "Leave policy deleted successfully")
+}
+
+// leaveTypeParam reads the {type} URL parameter as an upper-case leave type
+func leaveTypeParam(r *http.Request) leave.LeaveType {
+	return leave.LeaveType(strings.ToUpper(chi.URLParam(r, "type")))
+}
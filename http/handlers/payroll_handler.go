@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"employee-service/http/middlewares"
+	"employee-service/http/response"
+	"employee-service/models/payroll"
+	payrollService "employee-service/services/payroll"
+	"employee-service/utils/pdf"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PayrollHandler handles HTTP requests for payroll
+type PayrollHandler struct {
+	service *payrollService.Service
+}
+
+// NewPayrollHandler creates a new payroll handler
+func NewPayrollHandler(service *payrollService.Service) *PayrollHandler {
+	return &PayrollHandler{service: service}
+}
+
+// GetDeductions handles GET /admin/payroll/deductions?period=2026-03
+// Lists the leave deductions due in a pay period; defaults to the current month
+func (h *PayrollHandler) GetDeductions(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = payroll.PeriodOf(time.Now())
+	}
+
+	report, err := h.service.GetDeductions(period)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve payroll deductions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, report, "Payroll deductions retrieved successfully")
+}
+
+// RunPayroll handles POST /admin/payroll/runs/{period}
+// Computes the payslips of the pay period and locks it
+func (h *PayrollHandler) RunPayroll(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	userCtx, _ := middlewares.GetUserFromContext(r)
+
+	run, err := h.service.RunPayroll(chi.URLParam(r, "period"), userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to run payroll")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, run, "Payroll run completed and pay period locked")
+}
+
+// ListRuns handles GET /admin/payroll/runs
+func (h *PayrollHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	runs, err := h.service.ListRuns()
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve payroll runs")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":        len(runs),
+		"payroll_runs": runs,
+	}, "Payroll runs retrieved successfully")
+}
+
+// GetPayslips handles GET /admin/payroll/runs/{period}/payslips?format=csv
+func (h *PayrollHandler) GetPayslips(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	period := chi.URLParam(r, "period")
+	payslips, err := h.service.GetPayslips(period)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve payslips")
+		return
+	}
+
+	switch payslipFormat(r) {
+	case "csv":
+		writePayslipsCSV(w, fmt.Sprintf("payslips-%s.csv", period), payslips)
+	case "json":
+		response.Success(w, http.StatusOK, map[string]interface{}{
+			"count":    len(payslips),
+			"payslips": payslips,
+		}, "Payslips retrieved successfully")
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid format, expected json or csv")
+	}
+}
+
+// GetPayslip handles GET /admin/payroll/runs/{period}/payslips/{employeeId}?format=pdf
+func (h *PayrollHandler) GetPayslip(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	employeeID, err := strconv.Atoi(chi.URLParam(r, "employeeId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid employee ID")
+		return
+	}
+
+	payslip, err := h.service.GetPayslip(chi.URLParam(r, "period"), employeeID)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve payslip")
+		return
+	}
+
+	writePayslip(w, r, payslip)
+}
+
+// CreateCorrection handles POST /admin/payroll/adjustments
+// Records a correction to an employee's pay, paid by the next payroll run if its period is locked
+func (h *PayrollHandler) CreateCorrection(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	userCtx, _ := middlewares.GetUserFromContext(r)
+
+	var req payroll.CreateCorrectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	adjustment, err := h.service.CreateCorrection(&req, userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to record payroll correction")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, adjustment, "Payroll correction recorded successfully")
+}
+
+// GetMyPayslips handles GET /employee/payslips
+func (h *PayrollHandler) GetMyPayslips(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	payslips, err := h.service.GetMyPayslips(userCtx.UserID)
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve payslips")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"count":    len(payslips),
+		"payslips": payslips,
+	}, "Payslips retrieved successfully")
+}
+
+// GetMyPayslip handles GET /employee/payslips/{period}?format=pdf
+func (h *PayrollHandler) GetMyPayslip(w http.ResponseWriter, r *http.Request) {
+	userCtx, err := middlewares.GetUserFromContext(r)
+	if err != nil {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	payslip, err := h.service.GetMyPayslip(userCtx.UserID, chi.URLParam(r, "period"))
+	if err != nil {
+		writeServiceError(w, err, "failed to retrieve payslip")
+		return
+	}
+
+	writePayslip(w, r, payslip)
+}
+
+// payslipFormat reads the requested payslip format, json by default
+func payslipFormat(r *http.Request) string {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		return "json"
+	}
+	return format
+}
+
+// writePayslip sends a payslip as JSON, CSV or PDF
+func writePayslip(w http.ResponseWriter, r *http.Request, payslip *payroll.Payslip) {
+	filename := fmt.Sprintf("payslip-%s-%d", payslip.PayPeriod, payslip.EmployeeID)
+
+	switch payslipFormat(r) {
+	case "pdf":
+		title := fmt.Sprintf("Payslip %s - %s", payslip.PayPeriod, payslip.EmployeeName)
+		response.File(w, "application/pdf", filename+".pdf", pdf.TextDocument(title, payslip.Lines()))
+	case "csv":
+		writePayslipsCSV(w, filename+".csv", []payroll.Payslip{*payslip})
+	case "json":
+		response.Success(w, http.StatusOK, payslip, "Payslip retrieved successfully")
+	default:
+		response.Error(w, http.StatusBadRequest, "Invalid format, expected json, csv or pdf")
+	}
+}
+
+// writePayslipsCSV sends payslips as a CSV download
+func writePayslipsCSV(w http.ResponseWriter, filename string, payslips []payroll.Payslip) {
+	var buf bytes.Buffer
+	if err := payroll.WritePayslipsCSV(&buf, payslips); err != nil {
+		writeServiceError(w, err, "failed to export payslips")
+		return
+	}
+	response.File(w, "text/csv", filename, buf.Bytes())
+}
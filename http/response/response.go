@@ -2,7 +2,9 @@ package response
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 )
 
 // Response is a generic response wrapper
@@ -17,6 +19,7 @@ type ErrorResponse struct {
 	Success bool              `json:"success"`
 	Message string            `json:"message"`
 	Errors  map[string]string `json:"errors,omitempty"`
+	Details interface{}       `json:"details,omitempty"`
 }
 
 // Success sends a successful response
@@ -65,6 +68,21 @@ func ErrorWithFields(w http.ResponseWriter, statusCode int, message string, fiel
 	json.NewEncoder(w).Encode(response)
 }
 
+// ErrorWithDetails sends an error response with field-level errors and structured details
+func ErrorWithDetails(w http.ResponseWriter, statusCode int, message string, fields map[string]string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := ErrorResponse{
+		Success: false,
+		Message: message,
+		Errors:  fields,
+		Details: details,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
 // JSONResponse encodes data as JSON and sends it as response
 func JSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -72,3 +90,13 @@ func JSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 
 	json.NewEncoder(w).Encode(data)
 }
+
+// File sends data as a file download with the given content type
+func File(w http.ResponseWriter, contentType string, filename string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+
+	w.Write(data)
+}
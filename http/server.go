@@ -16,12 +16,20 @@ import (
 	"employee-service/errors"
 	"employee-service/http/handlers"
 	"employee-service/http/middlewares"
+	"employee-service/models/leave"
+	"employee-service/models/payroll"
+	"employee-service/repositories"
 	"employee-service/repositories/postgres"
 	emailService "employee-service/services/email"
 	employeeService "employee-service/services/employee"
+	holidayService "employee-service/services/holiday"
 	leaveService "employee-service/services/leave"
+	payrollService "employee-service/services/payroll"
 	userService "employee-service/services/user"
 	"employee-service/utils/jwt"
+	"employee-service/utils/logger"
+	"employee-service/utils/scheduler"
+	"employee-service/utils/storage"
 )
 
 // Server holds all the dependencies for the HTTP server
@@ -33,6 +41,7 @@ type Server struct {
 	SQLiteDB   *sql.DB
 	httpServer *http.Server
 	emailQueue *emailService.EmailQueue
+	scheduler  *scheduler.Scheduler // Background jobs such as leave accrual
 }
 
 // NewServer creates a new HTTP server
@@ -83,6 +92,12 @@ func (s *Server) Setup() {
 	userRepo := postgres.NewUserRepository(s.db)
 	leaveRepo := postgres.NewLeaveRepository(s.db)
 	notificationRepo := postgres.NewNotificationRepository(s.db)
+	holidayRepo := postgres.NewHolidayRepository(s.db)
+	leavePolicyRepo := postgres.NewLeavePolicyRepository(s.db)
+	leaveAccrualRepo := postgres.NewLeaveAccrualRepository(s.db)
+	leaveRolloverRepo := postgres.NewLeaveRolloverRepository(s.db)
+	leaveApprovalRepo := postgres.NewLeaveApprovalRepository(s.db)
+	payrollRepo := postgres.NewPayrollRepository(s.db)
 
 	// Initialize SMTP email service with environment variables
 	smtpHost := os.Getenv("SMTP_HOST")
@@ -134,22 +149,125 @@ func (s *Server) Setup() {
 	}
 
 	// Initialize services
-	leaveServiceInstance := leaveService.NewService(leaveRepo, employeeRepo, userRepo, notificationRepo, s.emailQueue)
+	txManager := repositories.NewTransactionManager(s.db, logger.Get())
+	// Daily rate used to price leave deductions and encashment, e.g. PAYROLL_DAILY_RATE_FORMULA=FIXED_DIVISOR and PAYROLL_DAILY_RATE_DIVISOR=30
+	ratePolicy := payroll.DefaultRatePolicy
+	if formula := os.Getenv("PAYROLL_DAILY_RATE_FORMULA"); formula != "" {
+		ratePolicy.Formula = payroll.ParseRateFormula(formula)
+	}
+	if divisorStr := os.Getenv("PAYROLL_DAILY_RATE_DIVISOR"); divisorStr != "" {
+		if d, err := strconv.ParseFloat(divisorStr, 64); err == nil {
+			ratePolicy.Divisor = d
+		}
+	}
+	if err := ratePolicy.Validate(); err != nil {
+		errors.LogError("Invalid payroll daily rate settings; dividing salaries by calendar days instead", err)
+		ratePolicy = payroll.DefaultRatePolicy
+	}
+
+	// Leave attachments are kept on the local filesystem, e.g. LEAVE_ATTACHMENT_DIR=./uploads and LEAVE_ATTACHMENT_MAX_MB=5
+	attachmentDir := os.Getenv("LEAVE_ATTACHMENT_DIR")
+	if attachmentDir == "" {
+		attachmentDir = "./uploads"
+	}
+	var attachmentStorage storage.Storage
+	if localStorage, err := storage.NewLocalStorage(attachmentDir); err != nil {
+		errors.LogError("Failed to set up leave attachment storage; uploads will fail", err)
+	} else {
+		attachmentStorage = localStorage
+	}
+	attachmentLimits := leave.DefaultAttachmentLimits
+	if maxStr := os.Getenv("LEAVE_ATTACHMENT_MAX_MB"); maxStr != "" {
+		if mb, err := strconv.Atoi(maxStr); err == nil && mb > 0 {
+			attachmentLimits.MaxSize = int64(mb) << 20
+		}
+	}
+
+	// Calendar feed links are signed with CALENDAR_FEED_SECRET, or the JWT secret when it is not set
+	feedSecret := os.Getenv("CALENDAR_FEED_SECRET")
+	if feedSecret == "" {
+		feedSecret = s.config.JWT.Secret
+	}
+
+	// Comp-off must be claimed within COMP_OFF_CLAIM_WINDOW_DAYS and used within COMP_OFF_EXPIRY_DAYS of the day worked
+	compOffPolicy := leave.DefaultCompOffPolicy
+	if days, err := strconv.Atoi(os.Getenv("COMP_OFF_EXPIRY_DAYS")); err == nil {
+		compOffPolicy.ExpiryDays = days
+	}
+	if days, err := strconv.Atoi(os.Getenv("COMP_OFF_CLAIM_WINDOW_DAYS")); err == nil {
+		compOffPolicy.ClaimWindowDays = days
+	}
+	if err := compOffPolicy.Validate(); err != nil {
+		errors.LogError("Invalid comp-off configuration; using the defaults", err)
+		compOffPolicy = leave.DefaultCompOffPolicy
+	}
+
+	leaveServiceInstance := leaveService.NewService(leaveRepo, employeeRepo, userRepo, notificationRepo, holidayRepo, leavePolicyRepo, leaveAccrualRepo, leaveRolloverRepo, leaveApprovalRepo, payrollRepo, txManager, s.emailQueue, ratePolicy, attachmentStorage, attachmentLimits, repositories.NewAuditLogger(s.db, logger.Get()), feedSecret, compOffPolicy)
+	payrollServiceInstance := payrollService.NewService(payrollRepo, employeeRepo, txManager, ratePolicy)
+	holidayServiceInstance := holidayService.NewService(holidayRepo)
+
+	s.scheduler = scheduler.New()
+
+	// Schedule the leave accrual job
+	accrualInterval := 24 * time.Hour
+	if intervalStr := os.Getenv("LEAVE_ACCRUAL_INTERVAL"); intervalStr != "" {
+		if d, err := time.ParseDuration(intervalStr); err == nil && d > 0 {
+			accrualInterval = d
+		}
+	}
+	if err := leaveServiceInstance.ScheduleAccrual(s.scheduler, accrualInterval); err != nil {
+		errors.LogError("Failed to schedule leave accrual", err)
+	}
+
+	// Schedule the approval reminder job
+	reminderPolicy := leave.ReminderPolicy{
+		RemindAfter:   durationFromEnv("LEAVE_REMINDER_AFTER", 48*time.Hour),
+		EscalateAfter: durationFromEnv("LEAVE_ESCALATE_AFTER", 96*time.Hour),
+	}
+	if reminderPolicy.EscalateAfter <= reminderPolicy.RemindAfter {
+		errors.LogInfo("LEAVE_ESCALATE_AFTER must be longer than LEAVE_REMINDER_AFTER; approval escalation disabled")
+		reminderPolicy.EscalateAfter = 0
+	}
+	if err := leaveServiceInstance.ScheduleApprovalReminders(s.scheduler, durationFromEnv("LEAVE_REMINDER_INTERVAL", time.Hour), reminderPolicy); err != nil {
+		errors.LogError("Failed to schedule approval reminders", err)
+	}
+
+	// Schedule the job that lapses comp-off left unused when it expires
+	if err := leaveServiceInstance.ScheduleCompOffExpiry(s.scheduler, durationFromEnv("COMP_OFF_EXPIRY_INTERVAL", 24*time.Hour)); err != nil {
+		errors.LogError("Failed to schedule comp-off expiry", err)
+	}
 	userServiceInstance := userService.NewUserService(userRepo)
 
 	// Initialize employee service with user service for creating login credentials
-	employeeServiceInstance := employeeService.NewServiceWithUser(employeeRepo, userRepo, userServiceInstance)
+	employeeServiceInstance := employeeService.NewServiceWithUser(employeeRepo, userRepo, userServiceInstance, txManager)
+
+	// Schedule the job that applies salary and position changes when they take effect
+	if err := employeeServiceInstance.ScheduleCompensation(s.scheduler, durationFromEnv("COMPENSATION_APPLY_INTERVAL", time.Hour)); err != nil {
+		errors.LogError("Failed to schedule compensation changes", err)
+	}
+
+	if err := s.scheduler.Start(); err != nil {
+		errors.LogError("Failed to start scheduler", err)
+	}
 
 	// Initialize handlers
 	employeeHandler := handlers.NewEmployeeHandlerWithLeave(employeeServiceInstance, leaveServiceInstance)
 	leaveHandler := handlers.NewLeaveHandler(leaveServiceInstance)
 	authHandler := handlers.NewAuthHandlerWithServices(userServiceInstance, jwtManager, employeeRepo, leaveServiceInstance)
 	dashboardHandler := handlers.NewDashboardHandler(employeeServiceInstance, userServiceInstance)
+	holidayHandler := handlers.NewHolidayHandler(holidayServiceInstance)
+	leavePolicyHandler := handlers.NewLeavePolicyHandler(leaveServiceInstance)
+	leaveAccrualHandler := handlers.NewLeaveAccrualHandler(leaveServiceInstance)
+	leaveApprovalHandler := handlers.NewLeaveApprovalHandler(leaveServiceInstance)
+	payrollHandler := handlers.NewPayrollHandler(payrollServiceInstance)
 
 	// Health check endpoints (no auth required)
 	s.router.Get("/health", s.healthCheck)
 	s.router.Get("/readiness", s.healthCheck)
 
+	// Calendar feed of approved leave (no auth required; the link carries a signed token)
+	s.router.Get("/calendar-feeds/{token}", leaveHandler.GetCalendarFeedICS)
+
 	// Auth routes (no auth required)
 	s.router.Post("/auth/login", authHandler.Login)
 	
@@ -166,6 +284,8 @@ func (s *Server) Setup() {
 		r.Use(middlewares.JWTMiddleware(jwtManager))
 		r.Get("/records", dashboardHandler.GetUserRecords)
 		r.Get("/overview", dashboardHandler.GetUserOverview)
+		r.Get("/payslips", payrollHandler.GetMyPayslips)
+		r.Get("/payslips/{period}", payrollHandler.GetMyPayslip)
 	})
 
 	s.router.Route("/admin", func(r chi.Router) {
@@ -173,6 +293,58 @@ func (s *Server) Setup() {
 		r.Get("/records", dashboardHandler.GetAdminRecords)
 		r.Get("/overview", dashboardHandler.GetAdminOverview)
 		r.Get("/logs", dashboardHandler.GetAdminLogs)
+
+		// Holiday calendar management
+		r.Get("/holidays", holidayHandler.ListHolidays)
+		r.Post("/holidays", holidayHandler.CreateHoliday)
+		r.Get("/holidays/{id}", holidayHandler.GetHoliday)
+		r.Put("/holidays/{id}", holidayHandler.UpdateHoliday)
+		r.Delete("/holidays/{id}", holidayHandler.DeleteHoliday)
+
+		// Leave policy management
+		r.Get("/leave-policies", leavePolicyHandler.ListPolicies)
+		r.Post("/leave-policies", leavePolicyHandler.CreatePolicy)
+		r.Get("/leave-policies/{type}", leavePolicyHandler.GetPolicy)
+		r.Put("/leave-policies/{type}", leavePolicyHandler.UpdatePolicy)
+		r.Delete("/leave-policies/{type}", leavePolicyHandler.DeletePolicy)
+
+		// Leave accruals
+		r.Post("/leave-accruals/run", leaveAccrualHandler.RunAccrual)
+		r.Get("/leave-accruals/{employeeId}", leaveAccrualHandler.GetEmployeeAccruals)
+
+		// Leave year-end rollover
+		r.Post("/leave-year-end/{year}", leaveAccrualHandler.RunYearEnd)
+		r.Get("/leave-year-end/{year}", leaveAccrualHandler.GetYearEndRollovers)
+
+		// Comp-off expiry
+		r.Post("/leave-comp-off/expire", leaveHandler.RunCompOffExpiry)
+
+		// Leave balance ledger
+		r.Post("/leave-balances/{employeeId}/adjust", leaveHandler.AdjustLeaveBalance)
+		r.Get("/leave-balances/{employeeId}/{type}/history", leaveHandler.GetEmployeeLeaveBalanceHistory)
+
+		// Leave entered on behalf of employees
+		r.Post("/leave-requests", leaveHandler.ApplyLeaveForEmployee)
+
+		// Leave approval chains
+		r.Get("/leave-approval-rules", leaveApprovalHandler.ListRules)
+		r.Post("/leave-approval-rules", leaveApprovalHandler.CreateRule)
+		r.Put("/leave-approval-rules/{id}", leaveApprovalHandler.UpdateRule)
+		r.Delete("/leave-approval-rules/{id}", leaveApprovalHandler.DeleteRule)
+
+		// Leave coverage rules
+		r.Get("/leave-coverage-rules", leaveHandler.ListCoverageRules)
+		r.Post("/leave-coverage-rules", leaveHandler.CreateCoverageRule)
+		r.Put("/leave-coverage-rules/{id}", leaveHandler.UpdateCoverageRule)
+		r.Delete("/leave-coverage-rules/{id}", leaveHandler.DeleteCoverageRule)
+
+		// Payroll
+		r.Get("/payroll/deductions", payrollHandler.GetDeductions)
+		r.Post("/payroll/adjustments", payrollHandler.CreateCorrection)
+		r.Get("/payroll/runs", payrollHandler.ListRuns)
+		r.Post("/payroll/runs/{period}", payrollHandler.RunPayroll)
+		r.Get("/payroll/runs/{period}/payslips", payrollHandler.GetPayslips)
+		r.Get("/payroll/runs/{period}/payslips/{employeeId}", payrollHandler.GetPayslip)
 	})
 
 	// Leave routes with JWT auth
@@ -184,12 +356,41 @@ func (s *Server) Setup() {
 		r.Delete("/cancel/{id}", leaveHandler.CancelLeave)
 		r.Get("/balance", leaveHandler.GetMyLeaveBalance)
 		r.Get("/balance/{type}", leaveHandler.GetMyLeaveBalanceByType)
+		r.Get("/balance/{type}/history", leaveHandler.GetMyLeaveBalanceHistory)
+		r.Get("/calendar-feed", leaveHandler.GetCalendarFeed)
+		r.Post("/calendar-feed", leaveHandler.CreateCalendarFeed)
+		r.Delete("/calendar-feed", leaveHandler.RevokeCalendarFeed)
+		r.Post("/comp-off", leaveHandler.RequestCompOff)
+		r.Get("/comp-off/mine", leaveHandler.GetMyCompOffCredits)
+		r.Put("/{id}", leaveHandler.UpdateLeave)
+		r.Get("/{id}/approvals", leaveHandler.GetLeaveApprovals)
+		r.Get("/{id}/revisions", leaveHandler.GetLeaveRevisions)
+		r.Post("/{id}/attachments", leaveHandler.AddAttachments)
+		r.Get("/{id}/attachments", leaveHandler.GetAttachments)
+		r.Get("/{id}/attachments/{attachmentId}", leaveHandler.DownloadAttachment)
+		r.Post("/{id}/comments", leaveHandler.AddComment)
+		r.Get("/{id}/comments", leaveHandler.GetComments)
+		r.Post("/{id}/cancellation", leaveHandler.RequestCancellation)
+
+		// Approval delegation
+		r.Get("/delegations", leaveApprovalHandler.ListDelegations)
+		r.Post("/delegations", leaveApprovalHandler.CreateDelegation)
+		r.Put("/delegations/default", leaveApprovalHandler.SetDefaultDelegate)
+		r.Delete("/delegations/{id}", leaveApprovalHandler.RevokeDelegation)
 		
-		// Admin routes
+		// Approver routes
 		r.Get("/review", leaveHandler.ReviewLeaveRequests)
+		r.Get("/calendar", leaveHandler.GetTeamCalendar)
 		r.Get("/all", leaveHandler.GetAllLeaveRequests)
 		r.Post("/approve/{id}", leaveHandler.ApproveLeave)
 		r.Post("/reject/{id}", leaveHandler.RejectLeave)
+		r.Post("/decisions", leaveHandler.BulkDecide)
+		r.Get("/cancellations", leaveHandler.ListCancellations)
+		r.Post("/cancellations/{id}/approve", leaveHandler.ApproveCancellation)
+		r.Post("/cancellations/{id}/reject", leaveHandler.RejectCancellation)
+		r.Get("/comp-off", leaveHandler.ListCompOffCredits)
+		r.Post("/comp-off/{id}/approve", leaveHandler.ApproveCompOff)
+		r.Post("/comp-off/{id}/reject", leaveHandler.RejectCompOff)
 	})
 
 // API routes with JWT auth
@@ -212,11 +413,24 @@ func (s *Server) Setup() {
 		// Update employee
 		r.Put("/{id}", employeeHandler.UpdateEmployee)
 
+		// Salary and position history
+		r.Get("/{id}/history", employeeHandler.GetCompensationHistory)
+
 		// Delete employee
 		r.Delete("/{id}", employeeHandler.DeleteEmployee)
 	})
 }
 
+// durationFromEnv reads a positive duration such as "48h" from an environment variable
+func durationFromEnv(key string, defaultVal time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultVal
+}
+
 // healthCheck handles health check endpoint
 func (s *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -307,7 +521,14 @@ func (s *Server) Start() error {
 func (s *Server) Shutdown(ctx context.Context) error {
 	errors.LogInfo("Shutting down server...")
 	
-	// Stop email queue first
+	// Stop the scheduler before the email queue so no job starts during shutdown
+	if s.scheduler != nil {
+		if err := s.scheduler.Stop(); err != nil {
+			errors.LogError("Error stopping scheduler", err)
+		}
+	}
+
+	// Stop email queue
 	if s.emailQueue != nil {
 		err := s.emailQueue.Stop()
 		if err != nil {
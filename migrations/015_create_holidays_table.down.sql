@@ -0,0 +1,4 @@
+-- Drop holiday calendar
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS skipped_days;
+ALTER TABLE employees DROP COLUMN IF EXISTS location;
+DROP TABLE IF EXISTS holidays;
@@ -0,0 +1,19 @@
+-- Create holidays table for the per-location holiday calendar
+CREATE TABLE IF NOT EXISTS holidays (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    holiday_date DATE NOT NULL,
+    year INTEGER NOT NULL,
+    location VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(holiday_date, location)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holidays_year_location ON holidays(year, location);
+
+-- Employees are matched to holidays by location ('' = all locations)
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS location VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Record which days of a leave request were not charged and why
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS skipped_days TEXT;
@@ -0,0 +1,2 @@
+-- Drop leave_policies table
+DROP TABLE IF EXISTS leave_policies;
@@ -0,0 +1,27 @@
+-- Create leave_policies table (replaces hardcoded leave limits)
+CREATE TABLE IF NOT EXISTS leave_policies (
+    id SERIAL PRIMARY KEY,
+    leave_type VARCHAR(50) NOT NULL UNIQUE,
+    annual_entitlement INTEGER NOT NULL DEFAULT 0,
+    is_paid BOOLEAN NOT NULL DEFAULT FALSE,
+    eligible_gender VARCHAR(10) NOT NULL DEFAULT '',
+    requires_married BOOLEAN NOT NULL DEFAULT FALSE,
+    min_tenure_days INTEGER NOT NULL DEFAULT 0,
+    max_consecutive_days INTEGER NOT NULL DEFAULT 0,
+    notice_days INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Seed the policies that were previously hardcoded
+INSERT INTO leave_policies (leave_type, annual_entitlement, is_paid, eligible_gender, requires_married)
+VALUES
+    ('ANNUAL', 10, TRUE, '', FALSE),
+    ('SICK', 15, TRUE, '', FALSE),
+    ('CASUAL', 10, FALSE, '', FALSE),
+    ('PERSONAL', 10, FALSE, '', FALSE),
+    ('UNPAID', 10, FALSE, '', FALSE),
+    ('MATERNITY', 90, FALSE, 'Female', TRUE),
+    ('PATERNITY', 7, FALSE, 'Male', TRUE)
+ON CONFLICT (leave_type) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Drop leave_accruals table and accrual frequency
+DROP INDEX IF EXISTS idx_leave_accruals_employee_id;
+DROP TABLE IF EXISTS leave_accruals;
+ALTER TABLE leave_policies DROP COLUMN IF EXISTS accrual_frequency;
@@ -0,0 +1,35 @@
+-- Add accrual frequency to leave policies
+ALTER TABLE leave_policies ADD COLUMN IF NOT EXISTS accrual_frequency VARCHAR(10) NOT NULL DEFAULT 'MONTHLY';
+
+-- Event-based leave types are granted once per year instead of accruing monthly
+UPDATE leave_policies SET accrual_frequency = 'ANNUAL' WHERE leave_type IN ('UNPAID', 'MATERNITY', 'PATERNITY');
+
+-- Create leave_accruals table (one ledger entry per employee, leave type and period)
+CREATE TABLE IF NOT EXISTS leave_accruals (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL,
+    leave_type VARCHAR(50) NOT NULL,
+    period VARCHAR(7) NOT NULL,
+    days NUMERIC(6, 2) NOT NULL,
+    credited_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    UNIQUE(employee_id, leave_type, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_accruals_employee_id ON leave_accruals(employee_id);
+
+-- Existing balances were granted the full year's entitlement up front. Mark this year's periods
+-- as accrued without crediting anything, so the accrual job does not grant them a second time.
+INSERT INTO leave_accruals (employee_id, leave_type, period, days, credited_days)
+SELECT lb.employee_id, lb.leave_type, TO_CHAR(m.month, 'YYYY-MM'), ROUND(lp.annual_entitlement / 12.0, 2), 0
+FROM leave_balances lb
+JOIN leave_policies lp ON lp.leave_type = lb.leave_type AND lp.accrual_frequency = 'MONTHLY'
+CROSS JOIN generate_series(DATE_TRUNC('year', CURRENT_DATE), DATE_TRUNC('year', CURRENT_DATE) + INTERVAL '11 months', INTERVAL '1 month') AS m(month)
+ON CONFLICT (employee_id, leave_type, period) DO NOTHING;
+
+INSERT INTO leave_accruals (employee_id, leave_type, period, days, credited_days)
+SELECT lb.employee_id, lb.leave_type, TO_CHAR(CURRENT_DATE, 'YYYY'), lp.annual_entitlement, 0
+FROM leave_balances lb
+JOIN leave_policies lp ON lp.leave_type = lb.leave_type AND lp.accrual_frequency = 'ANNUAL'
+ON CONFLICT (employee_id, leave_type, period) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Drop leave_rollovers table and leave year columns
+DROP INDEX IF EXISTS idx_leave_rollovers_year;
+DROP TABLE IF EXISTS leave_rollovers;
+ALTER TABLE leave_policies DROP COLUMN IF EXISTS encash_excess;
+ALTER TABLE leave_policies DROP COLUMN IF EXISTS carry_forward_max;
+ALTER TABLE leave_balances DROP COLUMN IF EXISTS leave_year;
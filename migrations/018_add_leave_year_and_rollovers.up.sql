@@ -0,0 +1,26 @@
+-- Track which leave year each balance belongs to
+ALTER TABLE leave_balances ADD COLUMN IF NOT EXISTS leave_year INTEGER NOT NULL DEFAULT 0;
+UPDATE leave_balances SET leave_year = EXTRACT(YEAR FROM CURRENT_DATE) WHERE leave_year = 0;
+
+-- Year-end carry-forward rules per leave type
+ALTER TABLE leave_policies ADD COLUMN IF NOT EXISTS carry_forward_max INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leave_policies ADD COLUMN IF NOT EXISTS encash_excess BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE leave_policies SET carry_forward_max = 5 WHERE leave_type = 'ANNUAL';
+
+-- Create leave_rollovers table (one record per employee, leave type and closed year)
+CREATE TABLE IF NOT EXISTS leave_rollovers (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL,
+    leave_type VARCHAR(50) NOT NULL,
+    year INTEGER NOT NULL,
+    closing_balance INTEGER NOT NULL,
+    carried_forward INTEGER NOT NULL DEFAULT 0,
+    lapsed INTEGER NOT NULL DEFAULT 0,
+    encashed INTEGER NOT NULL DEFAULT 0,
+    encash_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    UNIQUE(employee_id, leave_type, year)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_rollovers_year ON leave_rollovers(year);
@@ -0,0 +1,10 @@
+-- Restore the stored balance column from the ledger
+ALTER TABLE leave_balances ADD COLUMN IF NOT EXISTS balance INTEGER NOT NULL DEFAULT 0;
+
+UPDATE leave_balances lb
+SET balance = COALESCE((SELECT SUM(ll.days) FROM leave_ledger ll
+                        WHERE ll.employee_id = lb.employee_id AND ll.leave_type = lb.leave_type), 0);
+
+-- Drop leave_ledger table
+DROP INDEX IF EXISTS idx_leave_ledger_employee_type;
+DROP TABLE IF EXISTS leave_ledger;
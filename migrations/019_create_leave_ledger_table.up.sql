@@ -0,0 +1,26 @@
+-- Create leave_ledger table (append-only; balances are the sum of days)
+CREATE TABLE IF NOT EXISTS leave_ledger (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL,
+    leave_type VARCHAR(50) NOT NULL,
+    entry_type VARCHAR(20) NOT NULL,
+    days INTEGER NOT NULL,
+    leave_request_id INTEGER,
+    actor_user_id INTEGER,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+    FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_ledger_employee_type ON leave_ledger(employee_id, leave_type);
+
+-- Carry existing balances over as opening adjustments
+INSERT INTO leave_ledger (employee_id, leave_type, entry_type, days, reference, description)
+SELECT employee_id, leave_type, 'ADJUSTMENT', balance, 'migration', 'Opening balance'
+FROM leave_balances
+WHERE balance <> 0;
+
+-- The balance is now derived from the ledger
+ALTER TABLE leave_balances DROP COLUMN IF EXISTS balance;
@@ -0,0 +1,12 @@
+-- Revert to whole-day leave (fractional values are rounded)
+ALTER TABLE leave_rollovers ALTER COLUMN encashed TYPE INTEGER USING ROUND(encashed);
+ALTER TABLE leave_rollovers ALTER COLUMN lapsed TYPE INTEGER USING ROUND(lapsed);
+ALTER TABLE leave_rollovers ALTER COLUMN carried_forward TYPE INTEGER USING ROUND(carried_forward);
+ALTER TABLE leave_rollovers ALTER COLUMN closing_balance TYPE INTEGER USING ROUND(closing_balance);
+
+ALTER TABLE leave_accruals ALTER COLUMN credited_days TYPE INTEGER USING ROUND(credited_days);
+ALTER TABLE leave_ledger ALTER COLUMN days TYPE INTEGER USING ROUND(days);
+
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS hours;
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS day_part;
+ALTER TABLE leave_requests ALTER COLUMN days_count TYPE INTEGER USING CEIL(days_count);
@@ -0,0 +1,13 @@
+-- Allow half-day and hourly leave requests
+ALTER TABLE leave_requests ALTER COLUMN days_count TYPE DECIMAL(6, 3);
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS day_part VARCHAR(20) NOT NULL DEFAULT 'FULL';
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS hours DECIMAL(4, 2) NOT NULL DEFAULT 0;
+
+-- Keep fractional days in the ledger instead of rounding accruals to whole days
+ALTER TABLE leave_ledger ALTER COLUMN days TYPE DECIMAL(8, 3);
+ALTER TABLE leave_accruals ALTER COLUMN credited_days TYPE DECIMAL(6, 2);
+
+ALTER TABLE leave_rollovers ALTER COLUMN closing_balance TYPE DECIMAL(8, 3);
+ALTER TABLE leave_rollovers ALTER COLUMN carried_forward TYPE DECIMAL(8, 3);
+ALTER TABLE leave_rollovers ALTER COLUMN lapsed TYPE DECIMAL(8, 3);
+ALTER TABLE leave_rollovers ALTER COLUMN encashed TYPE DECIMAL(8, 3);
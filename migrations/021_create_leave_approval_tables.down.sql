@@ -0,0 +1,3 @@
+-- Drop approval chain tables
+DROP TABLE IF EXISTS leave_approvals;
+DROP TABLE IF EXISTS leave_approval_rules;
@@ -0,0 +1,41 @@
+-- Configurable approval chain steps
+CREATE TABLE IF NOT EXISTS leave_approval_rules (
+    id SERIAL PRIMARY KEY,
+    step_order INTEGER NOT NULL UNIQUE,
+    approver_role VARCHAR(20) NOT NULL,
+    over_days DECIMAL(6, 3) NOT NULL DEFAULT 0,  -- Applies to requests longer than this (0 = any length)
+    leave_types TEXT NOT NULL DEFAULT '',         -- Comma-separated leave types the step also applies to
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Line manager first, then HR for requests over 5 days or maternity leave
+INSERT INTO leave_approval_rules (step_order, approver_role, over_days, leave_types)
+VALUES
+    (1, 'MANAGER', 0, ''),
+    (2, 'HR', 5, 'MATERNITY')
+ON CONFLICT (step_order) DO NOTHING;
+
+-- Approval chain of each leave request, one row per step
+CREATE TABLE IF NOT EXISTS leave_approvals (
+    id SERIAL PRIMARY KEY,
+    leave_request_id INTEGER NOT NULL,
+    step INTEGER NOT NULL,
+    approver_role VARCHAR(20) NOT NULL,
+    approver_user_id INTEGER,
+    decision VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    comment TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (approver_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE(leave_request_id, step)
+);
+
+-- Requests pending before approval chains existed keep a single HR step
+INSERT INTO leave_approvals (leave_request_id, step, approver_role, decision, created_at)
+SELECT id, 1, 'HR', 'PENDING', created_at
+FROM leave_requests
+WHERE status = 'PENDING'
+ON CONFLICT (leave_request_id, step) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_employees_manager_id;
+
+ALTER TABLE employees DROP COLUMN IF EXISTS manager_id;
@@ -0,0 +1,4 @@
+-- Reporting line: each employee may report to one manager
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS manager_id INTEGER REFERENCES employees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_employees_manager_id ON employees(manager_id);
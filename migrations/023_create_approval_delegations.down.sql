@@ -0,0 +1,5 @@
+-- Drop approval delegation tables
+DROP TABLE IF EXISTS approval_delegate_defaults;
+DROP TABLE IF EXISTS approval_delegations;
+
+ALTER TABLE leave_approvals DROP COLUMN IF EXISTS on_behalf_of_user_id;
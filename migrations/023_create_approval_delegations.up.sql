@@ -0,0 +1,25 @@
+-- Approver standing in for another approver's decisions
+ALTER TABLE leave_approvals ADD COLUMN IF NOT EXISTS on_behalf_of_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+-- Approval rights handed to another user for a date range
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id SERIAL PRIMARY KEY,
+    delegator_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delegate_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    source VARCHAR(20) NOT NULL DEFAULT 'MANUAL',                          -- MANUAL or LEAVE (created on approval of the delegator's leave)
+    leave_request_id INTEGER REFERENCES leave_requests(id) ON DELETE CASCADE,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegate ON approval_delegations(delegate_user_id, start_date, end_date);
+
+-- Delegate used automatically while an approver's own leave is approved
+CREATE TABLE IF NOT EXISTS approval_delegate_defaults (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    delegate_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
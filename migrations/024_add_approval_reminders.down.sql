@@ -0,0 +1,2 @@
+ALTER TABLE leave_approvals DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE leave_approvals DROP COLUMN IF EXISTS reminded_at;
@@ -0,0 +1,3 @@
+-- Reminder and escalation of approval steps waiting past their SLA (each sent at most once)
+ALTER TABLE leave_approvals ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP;
+ALTER TABLE leave_approvals ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS low_balance_notices;
+
+ALTER TABLE leave_policies DROP COLUMN IF EXISTS low_balance_threshold;
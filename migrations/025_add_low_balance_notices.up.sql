@@ -0,0 +1,14 @@
+-- Low-balance warning threshold per leave type (0 = no warning)
+ALTER TABLE leave_policies ADD COLUMN IF NOT EXISTS low_balance_threshold DECIMAL(6, 3) NOT NULL DEFAULT 0;
+UPDATE leave_policies SET low_balance_threshold = 2 WHERE leave_type = 'ANNUAL';
+
+-- Create low_balance_notices table (one warning per employee, leave type and accrual period)
+CREATE TABLE IF NOT EXISTS low_balance_notices (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    leave_type VARCHAR(50) NOT NULL,
+    period VARCHAR(7) NOT NULL,          -- "2006-01" for monthly, "2006" for annual accrual policies
+    balance DECIMAL(6, 3) NOT NULL,      -- Balance that triggered the warning
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(employee_id, leave_type, period)
+);
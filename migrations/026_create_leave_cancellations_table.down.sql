@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_leave_cancellations_request;
+DROP TABLE IF EXISTS leave_cancellations;
@@ -0,0 +1,18 @@
+-- Create leave_cancellations table (requests to cancel all or the rest of approved leave)
+CREATE TABLE IF NOT EXISTS leave_cancellations (
+    id SERIAL PRIMARY KEY,
+    leave_request_id INTEGER NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    requested_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_date DATE NOT NULL,                        -- First day no longer taken
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',  -- PENDING, APPROVED or REJECTED
+    days_refunded DECIMAL(6, 3) NOT NULL DEFAULT 0,
+    salary_refunded DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    decided_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    decision_comment TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_cancellations_request ON leave_cancellations(leave_request_id, status);
@@ -0,0 +1,10 @@
+-- Create leave_request_revisions table (edits of pending leave requests, for approvers)
+CREATE TABLE IF NOT EXISTS leave_request_revisions (
+    id SERIAL PRIMARY KEY,
+    leave_request_id INTEGER NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,                -- 1 for the first edit
+    edited_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changes TEXT NOT NULL DEFAULT '[]',       -- JSON list of {field, from, to}
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(leave_request_id, revision)
+);
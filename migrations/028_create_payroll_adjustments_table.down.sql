@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_payroll_adjustments_leave_request;
+DROP INDEX IF EXISTS idx_payroll_adjustments_period;
+DROP TABLE IF EXISTS payroll_adjustments;
@@ -0,0 +1,18 @@
+-- Create payroll_adjustments table (amounts added to or taken from pay per pay period,
+-- replacing leave deductions written into employees.salary)
+CREATE TABLE IF NOT EXISTS payroll_adjustments (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    pay_period VARCHAR(7) NOT NULL,                 -- YYYY-MM
+    kind VARCHAR(30) NOT NULL,                      -- LEAVE_DEDUCTION or LEAVE_REFUND
+    days DECIMAL(6, 3) NOT NULL DEFAULT 0,
+    daily_rate DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10, 2) NOT NULL,                 -- Negative when taken from pay
+    leave_request_id INTEGER REFERENCES leave_requests(id) ON DELETE SET NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_period ON payroll_adjustments(pay_period, employee_id);
+CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_leave_request ON payroll_adjustments(leave_request_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_payroll_adjustments_run;
+DROP INDEX IF EXISTS idx_payslips_employee;
+ALTER TABLE payroll_adjustments DROP COLUMN IF EXISTS payroll_run_id;
+DROP TABLE IF EXISTS payslips;
+DROP TABLE IF EXISTS payroll_runs;
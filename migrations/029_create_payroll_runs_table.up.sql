@@ -0,0 +1,32 @@
+-- Create payroll_runs table (one per pay period; a run locks its period)
+CREATE TABLE IF NOT EXISTS payroll_runs (
+    id SERIAL PRIMARY KEY,
+    pay_period VARCHAR(7) NOT NULL UNIQUE,          -- YYYY-MM
+    run_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    employee_count INTEGER NOT NULL DEFAULT 0,
+    total_gross DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    total_adjustments DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    total_net DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create payslips table (an employee's pay in a payroll run)
+CREATE TABLE IF NOT EXISTS payslips (
+    id SERIAL PRIMARY KEY,
+    payroll_run_id INTEGER NOT NULL REFERENCES payroll_runs(id) ON DELETE CASCADE,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    employee_name VARCHAR(200) NOT NULL,            -- As at the run
+    position VARCHAR(100) NOT NULL DEFAULT '',
+    pay_period VARCHAR(7) NOT NULL,
+    gross_pay DECIMAL(10, 2) NOT NULL,
+    total_adjustments DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    net_pay DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(payroll_run_id, employee_id)
+);
+
+-- Adjustments are paid by the first run of their period or a later one
+ALTER TABLE payroll_adjustments ADD COLUMN IF NOT EXISTS payroll_run_id INTEGER REFERENCES payroll_runs(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payslips_employee ON payslips(employee_id, pay_period);
+CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_run ON payroll_adjustments(payroll_run_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_employee_compensation_pending;
+DROP INDEX IF EXISTS idx_employee_compensation_employee;
+DROP TABLE IF EXISTS employee_compensation;
@@ -0,0 +1,22 @@
+-- Create employee_compensation table (effective-dated salary and position changes;
+-- employees.salary and employees.position hold the values in effect today)
+CREATE TABLE IF NOT EXISTS employee_compensation (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    effective_date DATE NOT NULL,
+    salary DECIMAL(10, 2),                          -- NULL when unchanged
+    position VARCHAR(100),                          -- NULL when unchanged
+    reason TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    applied_at TIMESTAMP,                           -- NULL while scheduled
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_compensation_employee ON employee_compensation(employee_id, effective_date);
+CREATE INDEX IF NOT EXISTS idx_employee_compensation_pending ON employee_compensation(effective_date) WHERE applied_at IS NULL;
+
+-- Start every existing employee's history with the salary and position on record
+INSERT INTO employee_compensation (employee_id, effective_date, salary, position, reason, applied_at)
+SELECT e.id, e.hired_date, e.salary, e.position, 'Hired', CURRENT_TIMESTAMP
+FROM employees e
+WHERE NOT EXISTS (SELECT 1 FROM employee_compensation ec WHERE ec.employee_id = e.id);
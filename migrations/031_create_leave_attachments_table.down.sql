@@ -0,0 +1,3 @@
+ALTER TABLE leave_policies DROP COLUMN IF EXISTS attachment_required_after_days;
+DROP INDEX IF EXISTS idx_leave_attachments_request;
+DROP TABLE IF EXISTS leave_attachments;
@@ -0,0 +1,18 @@
+-- Create leave_attachments table (supporting documents such as medical certificates;
+-- the files themselves are kept in attachment storage under storage_key)
+CREATE TABLE IF NOT EXISTS leave_attachments (
+    id SERIAL PRIMARY KEY,
+    leave_request_id INTEGER NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    uploaded_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_attachments_request ON leave_attachments(leave_request_id);
+
+-- Leave longer than this many days needs a supporting document; 0 = never
+ALTER TABLE leave_policies ADD COLUMN IF NOT EXISTS attachment_required_after_days DECIMAL(6, 3) NOT NULL DEFAULT 0;
+UPDATE leave_policies SET attachment_required_after_days = 2 WHERE leave_type = 'SICK';
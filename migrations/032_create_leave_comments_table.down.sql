@@ -0,0 +1,4 @@
+ALTER TABLE leave_approvals DROP COLUMN IF EXISTS paused_seconds;
+ALTER TABLE leave_approvals DROP COLUMN IF EXISTS info_requested_at;
+DROP INDEX IF EXISTS idx_leave_comments_request;
+DROP TABLE IF EXISTS leave_comments;
@@ -0,0 +1,17 @@
+-- Create leave_comments table (comment thread between an employee and the approvers of a leave request)
+CREATE TABLE IF NOT EXISTS leave_comments (
+    id SERIAL PRIMARY KEY,
+    leave_request_id INTEGER NOT NULL REFERENCES leave_requests(id) ON DELETE CASCADE,
+    author_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    author_name VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    requests_info BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_leave_comments_request ON leave_comments(leave_request_id);
+
+-- An approval step waiting on the employee for more information is paused: no reminders are sent,
+-- and the time spent waiting does not count towards reminders once it resumes
+ALTER TABLE leave_approvals ADD COLUMN IF NOT EXISTS info_requested_at TIMESTAMP;
+ALTER TABLE leave_approvals ADD COLUMN IF NOT EXISTS paused_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_logs_user;
+DROP INDEX IF EXISTS idx_audit_logs_record;
+DROP TABLE IF EXISTS audit_logs;
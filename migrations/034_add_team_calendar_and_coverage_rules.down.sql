@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS leave_coverage_rules;
+
+ALTER TABLE employees DROP COLUMN IF EXISTS team;
+ALTER TABLE employees DROP COLUMN IF EXISTS department;
@@ -0,0 +1,17 @@
+-- Department and team of each employee, used by the team leave calendar and coverage rules
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS department VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS team VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Create leave_coverage_rules table (most members of a team or department that may be out at once)
+CREATE TABLE IF NOT EXISTS leave_coverage_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    scope VARCHAR(20) NOT NULL,
+    group_name VARCHAR(100) NOT NULL,
+    max_absent INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL DEFAULT 'WARN',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (scope, group_name)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE notifications DROP COLUMN IF EXISTS attachment_content;
+ALTER TABLE notifications DROP COLUMN IF EXISTS attachment_type;
+ALTER TABLE notifications DROP COLUMN IF EXISTS attachment_name;
+
+DROP TABLE IF EXISTS calendar_feeds;
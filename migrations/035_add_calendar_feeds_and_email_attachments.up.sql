@@ -0,0 +1,15 @@
+-- Create calendar_feeds table (signed links to a user's leave calendar; revoked links stop working)
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    nonce VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user ON calendar_feeds(user_id);
+
+-- File attached to a notification email, e.g. the calendar invite of approved leave
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS attachment_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS attachment_type VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS attachment_content TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DELETE FROM leave_policies WHERE leave_type = 'COMP_OFF';
+
+DROP TABLE IF EXISTS comp_off_credits;
@@ -0,0 +1,28 @@
+-- Create comp_off_credits table (comp-off earned by working on weekends and holidays; approved
+-- credits are added to the COMP_OFF balance and whatever is unused lapses on expires_on)
+CREATE TABLE IF NOT EXISTS comp_off_credits (
+    id SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    requested_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    worked_date DATE NOT NULL,
+    day_part VARCHAR(20) NOT NULL DEFAULT 'FULL',
+    days DECIMAL(6, 3) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    decided_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    decision_comment TEXT NOT NULL DEFAULT '',
+    decided_at TIMESTAMP,
+    expires_on DATE,
+    days_expired DECIMAL(6, 3) NOT NULL DEFAULT 0,
+    expired_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comp_off_credits_employee ON comp_off_credits(employee_id, worked_date);
+CREATE INDEX IF NOT EXISTS idx_comp_off_credits_expiry ON comp_off_credits(status, expires_on);
+
+-- COMP_OFF is only credited by approved comp-off requests, never accrued
+INSERT INTO leave_policies (leave_type, annual_entitlement, is_paid, accrual_frequency)
+VALUES ('COMP_OFF', 0, FALSE, 'ANNUAL')
+ON CONFLICT (leave_type) DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE leave_ledger DROP COLUMN IF EXISTS effective_date;
@@ -0,0 +1,27 @@
+-- Day each ledger entry counts towards, so year-end balances ignore movements that belong to
+-- the next year however early they were recorded
+ALTER TABLE leave_ledger ADD COLUMN IF NOT EXISTS effective_date DATE;
+
+UPDATE leave_ledger SET effective_date = CAST(created_at AS DATE) WHERE effective_date IS NULL;
+
+-- Approved leave counts from its first day
+UPDATE leave_ledger ll
+SET effective_date = lr.start_date
+FROM leave_requests lr
+WHERE lr.id = ll.leave_request_id AND ll.entry_type = 'DEBIT';
+
+-- Accruals count from the first day of their period ("2006-01" or "2006")
+UPDATE leave_ledger
+SET effective_date = TO_DATE(SUBSTRING(reference FROM 9), 'YYYY-MM')
+WHERE reference LIKE 'accrual:____-__';
+
+UPDATE leave_ledger
+SET effective_date = TO_DATE(SUBSTRING(reference FROM 9), 'YYYY')
+WHERE reference LIKE 'accrual:____';
+
+-- Year-end lapses and encashments count towards the year they close
+UPDATE leave_ledger
+SET effective_date = MAKE_DATE(CAST(SUBSTRING(reference FROM 10) AS INTEGER), 12, 31)
+WHERE reference LIKE 'rollover:____';
+
+ALTER TABLE leave_ledger ALTER COLUMN effective_date SET NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE leave_accruals ALTER COLUMN credited_days TYPE DECIMAL(6, 2);
+ALTER TABLE leave_accruals ALTER COLUMN days TYPE NUMERIC(6, 2);
@@ -0,0 +1,3 @@
+-- Store accrued days at the same precision as the leave ledger
+ALTER TABLE leave_accruals ALTER COLUMN days TYPE DECIMAL(6, 3);
+ALTER TABLE leave_accruals ALTER COLUMN credited_days TYPE DECIMAL(6, 3);
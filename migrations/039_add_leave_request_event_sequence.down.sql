@@ -0,0 +1 @@
+ALTER TABLE leave_requests DROP COLUMN IF EXISTS event_sequence;
@@ -0,0 +1,2 @@
+-- Count the changes sent to calendars for each leave request, so every invite replaces the last
+ALTER TABLE leave_requests ADD COLUMN IF NOT EXISTS event_sequence INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,175 @@
+package employee
+
+import (
+	"time"
+)
+
+// DateLayout is the format of compensation effective dates
+const DateLayout = "2006-01-02"
+
+// CompensationRecord is a change to an employee's salary or position that takes effect on a date.
+// Only the fields that change are set. A change dated in the future is scheduled and copied onto
+// the employee record when its date comes.
+type CompensationRecord struct {
+	ID            int        `json:"id"`
+	EmployeeID    int        `json:"employee_id"`
+	EffectiveDate time.Time  `json:"effective_date"`
+	Salary        *float64   `json:"salary,omitempty"`
+	Position      *string    `json:"position,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	CreatedBy     *int       `json:"created_by,omitempty"`
+	AppliedAt     *time.Time `json:"applied_at,omitempty"` // When the employee record took the change; nil while scheduled
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Compensation is the salary and position of an employee on a date
+type Compensation struct {
+	Salary   float64 `json:"salary"`
+	Position string  `json:"position"`
+}
+
+// CompensationHistory is the compensation records of one employee in effective date order
+type CompensationHistory []CompensationRecord
+
+// CompensationTimeline is an employee's compensation today and every change to it, past and
+// scheduled
+type CompensationTimeline struct {
+	EmployeeID int             `json:"employee_id"`
+	Current    Compensation    `json:"current"`
+	Entries    []TimelineEntry `json:"timeline"`
+}
+
+// TimelineEntry is one step of an employee's compensation timeline: what changed on a date and the
+// salary and position in effect from then on
+type TimelineEntry struct {
+	RecordID         int     `json:"record_id,omitempty"`
+	EffectiveDate    string  `json:"effective_date"`
+	Salary           float64 `json:"salary"`
+	Position         string  `json:"position"`
+	PreviousSalary   float64 `json:"previous_salary,omitempty"`   // Set when the salary changed
+	PreviousPosition string  `json:"previous_position,omitempty"` // Set when the position changed
+	Reason           string  `json:"reason,omitempty"`
+	Scheduled        bool    `json:"scheduled"` // Takes effect after today
+}
+
+// HireRecord is the first compensation record of an employee: the salary and position they were
+// hired on
+func HireRecord(emp *Employee) CompensationRecord {
+	salary, position := emp.Salary, emp.Position
+	return CompensationRecord{
+		EmployeeID:    emp.ID,
+		EffectiveDate: dateOnly(emp.Hired),
+		Salary:        &salary,
+		Position:      &position,
+		Reason:        "Hired",
+	}
+}
+
+// On returns the salary and position in effect on date. Whatever the history does not cover falls
+// back to the employee record.
+func (h CompensationHistory) On(emp *Employee, date time.Time) Compensation {
+	c := Compensation{Salary: emp.Salary, Position: emp.Position}
+	day := dateOnly(date)
+
+	for _, r := range h {
+		if dateOnly(r.EffectiveDate).After(day) {
+			break
+		}
+		if r.Salary != nil {
+			c.Salary = *r.Salary
+		}
+		if r.Position != nil {
+			c.Position = *r.Position
+		}
+	}
+	return c
+}
+
+// Add returns the history with a record inserted in effective date order, after any record
+// taking effect on the same date
+func (h CompensationHistory) Add(rec CompensationRecord) CompensationHistory {
+	i := len(h)
+	for i > 0 && dateOnly(h[i-1].EffectiveDate).After(dateOnly(rec.EffectiveDate)) {
+		i--
+	}
+
+	added := make(CompensationHistory, 0, len(h)+1)
+	added = append(added, h[:i]...)
+	added = append(added, rec)
+	return append(added, h[i:]...)
+}
+
+// Timeline lists the compensation changes of an employee in date order. An employee without
+// records has a single entry for their hire date taken from the employee record.
+func (h CompensationHistory) Timeline(emp *Employee, today time.Time) []TimelineEntry {
+	if len(h) == 0 {
+		h = CompensationHistory{HireRecord(emp)}
+	}
+
+	entries := make([]TimelineEntry, 0, len(h))
+	var current Compensation
+	for i, r := range h {
+		entry := TimelineEntry{
+			RecordID:      r.ID,
+			EffectiveDate: r.EffectiveDate.Format(DateLayout),
+			Salary:        current.Salary,
+			Position:      current.Position,
+			Reason:        r.Reason,
+			Scheduled:     dateOnly(r.EffectiveDate).After(dateOnly(today)),
+		}
+		if r.Salary != nil {
+			if i > 0 && *r.Salary != current.Salary {
+				entry.PreviousSalary = current.Salary
+			}
+			entry.Salary = *r.Salary
+		}
+		if r.Position != nil {
+			if i > 0 && *r.Position != current.Position {
+				entry.PreviousPosition = current.Position
+			}
+			entry.Position = *r.Position
+		}
+
+		current = Compensation{Salary: entry.Salary, Position: entry.Position}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// CompensationChange returns the salary and position change asked for by an update, or nil when it
+// changes neither. Values already in effect on the effective date are left out. Without an
+// effective date the change takes effect today.
+func (u *UpdateEmployeeRequest) CompensationChange(emp *Employee, history CompensationHistory, today time.Time) *CompensationRecord {
+	if u.Salary == nil && u.Position == nil {
+		return nil
+	}
+
+	effective := dateOnly(today)
+	if u.EffectiveDate != nil {
+		effective, _ = time.Parse(DateLayout, *u.EffectiveDate)
+	}
+
+	current := history.On(emp, effective)
+	record := &CompensationRecord{EmployeeID: emp.ID, EffectiveDate: effective}
+	if u.Salary != nil && *u.Salary != current.Salary {
+		salary := *u.Salary
+		record.Salary = &salary
+	}
+	if u.Position != nil && *u.Position != current.Position {
+		position := *u.Position
+		record.Position = &position
+	}
+	if record.Salary == nil && record.Position == nil {
+		return nil
+	}
+
+	if u.ChangeReason != nil {
+		record.Reason = *u.ChangeReason
+	}
+	return record
+}
+
+// dateOnly drops the time of day from a date
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,182 @@
+package employee_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	date, err := time.Parse(employee.DateLayout, value)
+	if err != nil {
+		t.Fatalf("Invalid date %q: %v", value, err)
+	}
+	return date
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+// sampleHistory is an engineer hired on 30000, raised in April and promoted in July
+func sampleHistory(t *testing.T) (*employee.Employee, employee.CompensationHistory) {
+	t.Helper()
+	emp := &employee.Employee{ID: 7, Position: "Senior Engineer", Salary: 36000, Hired: mustDate(t, "2025-01-06")}
+	history := employee.CompensationHistory{
+		{ID: 1, EmployeeID: 7, EffectiveDate: mustDate(t, "2025-01-06"), Salary: floatPtr(30000), Position: stringPtr("Engineer"), Reason: "Hired"},
+		{ID: 2, EmployeeID: 7, EffectiveDate: mustDate(t, "2026-04-01"), Salary: floatPtr(32000), Reason: "Annual raise"},
+		{ID: 3, EmployeeID: 7, EffectiveDate: mustDate(t, "2026-07-01"), Salary: floatPtr(36000), Position: stringPtr("Senior Engineer"), Reason: "Promotion"},
+	}
+	return emp, history
+}
+
+// TestCompensationHistoryOn tests the salary and position in effect on a date
+func TestCompensationHistoryOn(t *testing.T) {
+	emp, history := sampleHistory(t)
+
+	cases := []struct {
+		date     string
+		salary   float64
+		position string
+	}{
+		{"2025-01-06", 30000, "Engineer"},
+		{"2026-03-31", 30000, "Engineer"},
+		{"2026-04-01", 32000, "Engineer"},
+		{"2026-06-30", 32000, "Engineer"},
+		{"2026-07-01", 36000, "Senior Engineer"},
+		{"2024-12-31", 36000, "Senior Engineer"}, // Before the history, falls back to the employee record
+	}
+
+	for _, c := range cases {
+		t.Run(c.date, func(t *testing.T) {
+			got := history.On(emp, mustDate(t, c.date))
+			if got.Salary != c.salary || got.Position != c.position {
+				t.Errorf("Expected %v as %s, got %v as %s", c.salary, c.position, got.Salary, got.Position)
+			}
+		})
+	}
+
+	if got := employee.CompensationHistory(nil).On(emp, mustDate(t, "2026-01-01")); got.Salary != 36000 {
+		t.Errorf("Expected an empty history to fall back to the employee record, got %v", got.Salary)
+	}
+}
+
+// TestCompensationHistoryAdd tests inserting a backdated change into the history
+func TestCompensationHistoryAdd(t *testing.T) {
+	emp, history := sampleHistory(t)
+
+	added := history.Add(employee.CompensationRecord{ID: 4, EffectiveDate: mustDate(t, "2026-04-01"), Position: stringPtr("Engineer II")})
+	if len(added) != 4 || added[2].ID != 4 || len(history) != 3 {
+		t.Fatalf("Expected the change after the raise of the same date, got %+v", added)
+	}
+	if got := added.On(emp, mustDate(t, "2026-05-01")); got.Salary != 32000 || got.Position != "Engineer II" {
+		t.Errorf("Expected 32000 as Engineer II, got %v as %s", got.Salary, got.Position)
+	}
+}
+
+// TestCompensationTimeline tests the timeline of salary and position changes
+func TestCompensationTimeline(t *testing.T) {
+	emp, history := sampleHistory(t)
+
+	entries := history.Timeline(emp, mustDate(t, "2026-05-15"))
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 timeline entries, got %d", len(entries))
+	}
+
+	hired, raise, promotion := entries[0], entries[1], entries[2]
+	if hired.Salary != 30000 || hired.Position != "Engineer" || hired.PreviousSalary != 0 || hired.Scheduled {
+		t.Errorf("Unexpected hire entry: %+v", hired)
+	}
+	if raise.Salary != 32000 || raise.PreviousSalary != 30000 || raise.Position != "Engineer" || raise.PreviousPosition != "" {
+		t.Errorf("Unexpected raise entry: %+v", raise)
+	}
+	if promotion.PreviousPosition != "Engineer" || promotion.Position != "Senior Engineer" || !promotion.Scheduled {
+		t.Errorf("Unexpected scheduled promotion entry: %+v", promotion)
+	}
+
+	entries = employee.CompensationHistory{}.Timeline(emp, mustDate(t, "2026-05-15"))
+	if len(entries) != 1 || entries[0].EffectiveDate != "2025-01-06" || entries[0].Salary != 36000 {
+		t.Errorf("Expected a single entry from the employee record, got %+v", entries)
+	}
+}
+
+// TestCompensationChange tests turning an employee update into a compensation record
+func TestCompensationChange(t *testing.T) {
+	emp, history := sampleHistory(t)
+	today := mustDate(t, "2026-05-15")
+
+	cases := []struct {
+		name      string
+		request   employee.UpdateEmployeeRequest
+		effective string
+		salary    *float64
+		position  *string
+	}{
+		{"No compensation fields", employee.UpdateEmployeeRequest{Phone: stringPtr("555-0100")}, "", nil, nil},
+		{"Raise today", employee.UpdateEmployeeRequest{Salary: floatPtr(33000)}, "2026-05-15", floatPtr(33000), nil},
+		{"Unchanged salary", employee.UpdateEmployeeRequest{Salary: floatPtr(32000), Position: stringPtr("Engineer")}, "", nil, nil},
+		{"Scheduled raise", employee.UpdateEmployeeRequest{Salary: floatPtr(40000), EffectiveDate: stringPtr("2026-10-01")}, "2026-10-01", floatPtr(40000), nil},
+		{"Backdated promotion", employee.UpdateEmployeeRequest{Salary: floatPtr(32000), Position: stringPtr("Lead Engineer"), EffectiveDate: stringPtr("2026-05-01")}, "2026-05-01", nil, stringPtr("Lead Engineer")},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			record := c.request.CompensationChange(emp, history, today)
+			if c.effective == "" {
+				if record != nil {
+					t.Fatalf("Expected no change, got %+v", record)
+				}
+				return
+			}
+			if record == nil {
+				t.Fatal("Expected a compensation change")
+			}
+
+			if got := record.EffectiveDate.Format(employee.DateLayout); got != c.effective {
+				t.Errorf("Expected the change to take effect on %s, got %s", c.effective, got)
+			}
+			if (record.Salary == nil) != (c.salary == nil) || (c.salary != nil && *record.Salary != *c.salary) {
+				t.Errorf("Expected salary %v, got %v", c.salary, record.Salary)
+			}
+			if (record.Position == nil) != (c.position == nil) || (c.position != nil && *record.Position != *c.position) {
+				t.Errorf("Expected position %v, got %v", c.position, record.Position)
+			}
+		})
+	}
+}
+
+// TestUpdateEmployeeRequestEffectiveDate tests validation of the effective date of an update
+func TestUpdateEmployeeRequestEffectiveDate(t *testing.T) {
+	cases := []struct {
+		name    string
+		request employee.UpdateEmployeeRequest
+		invalid bool
+	}{
+		{"Dated raise", employee.UpdateEmployeeRequest{Salary: floatPtr(40000), EffectiveDate: stringPtr("2026-10-01")}, false},
+		{"Without a compensation change", employee.UpdateEmployeeRequest{Phone: stringPtr("555-0100"), EffectiveDate: stringPtr("2026-10-01")}, true},
+		{"Invalid date", employee.UpdateEmployeeRequest{Position: stringPtr("Lead"), EffectiveDate: stringPtr("01/10/2026")}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.request.Validate()
+			if !c.invalid {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			validationErr, ok := err.(*errors.ValidationError)
+			if !ok {
+				t.Fatalf("Expected a validation error, got %v", err)
+			}
+			if _, ok := validationErr.Fields["effective_date"]; !ok {
+				t.Errorf("Expected an effective_date error, got %v", validationErr.Fields)
+			}
+		})
+	}
+}
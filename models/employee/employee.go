@@ -10,6 +10,7 @@ import (
 type Employee struct {
 	ID             int       `json:"id"`
 	UserID         *int      `json:"user_id"`
+	ManagerID      *int      `json:"manager_id"` // Employee ID of the line manager; nil for the top of the hierarchy
 	FirstName      string    `json:"first_name"`
 	LastName       string    `json:"last_name"`
 	Email          string    `json:"email"`
@@ -18,6 +19,9 @@ type Employee struct {
 	Salary         float64   `json:"salary"`
 	Gender         string    `json:"gender"` // "Male" or "Female"
 	MaritalStatus  bool      `json:"marital_status"` // true = Married, false = Not Married
+	Location       string    `json:"location"` // Office location, used for the holiday calendar
+	Department     string    `json:"department"` // e.g. "Engineering"; used by the team leave calendar and coverage rules
+	Team           string    `json:"team"`       // e.g. "QA"; used by the team leave calendar and coverage rules
 	Hired          time.Time `json:"hired_date"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
@@ -39,7 +43,11 @@ type CreateEmployeeRequest struct {
 	Salary         float64    `json:"salary"`
 	Gender         string     `json:"gender"` // "Male" or "Female"
 	MaritalStatus  bool       `json:"marital_status"` // true = Married, false = Not Married
+	Location       string     `json:"location"`
+	Department     string     `json:"department"`
+	Team           string     `json:"team"`
 	HiredDate      *time.Time `json:"hired_date,omitempty"`
+	ManagerID      *int       `json:"manager_id,omitempty"`
 }
 
 // UpdateEmployeeRequest represents the request for updating an employee
@@ -52,6 +60,12 @@ type UpdateEmployeeRequest struct {
 	Salary         *float64 `json:"salary,omitempty"`
 	Gender         *string  `json:"gender,omitempty"` // "Male" or "Female"
 	MaritalStatus  *bool    `json:"marital_status,omitempty"` // true = Married, false = Not Married
+	Location       *string  `json:"location,omitempty"`
+	Department     *string  `json:"department,omitempty"`
+	Team           *string  `json:"team,omitempty"`
+	ManagerID      *int     `json:"manager_id,omitempty"` // 0 removes the manager
+	EffectiveDate  *string  `json:"effective_date,omitempty"` // "2006-01-02" the salary or position change takes effect; today by default
+	ChangeReason   *string  `json:"change_reason,omitempty"`  // Why the salary or position changes, e.g. "Promotion"
 }
 
 // Validate validates the create employee request
@@ -100,6 +114,10 @@ func (c *CreateEmployeeRequest) Validate() error {
 		validationErr.AddFieldError("gender", "Gender must be 'Male' or 'Female'")
 	}
 
+	if c.ManagerID != nil && *c.ManagerID <= 0 {
+		validationErr.AddFieldError("manager_id", "Manager ID must be a valid employee ID")
+	}
+
 	if c.MaritalStatus && (c.Gender == "Male" || c.Gender == "Female") {
 		// MaritalStatus is just a boolean, no special validation needed
 		// But we can add a note that it's required for maternity/paternity leave
@@ -142,6 +160,18 @@ func (u *UpdateEmployeeRequest) Validate() error {
 		validationErr.AddFieldError("gender", "Gender must be 'Male' or 'Female'")
 	}
 
+	if u.ManagerID != nil && *u.ManagerID < 0 {
+		validationErr.AddFieldError("manager_id", "Manager ID cannot be negative")
+	}
+
+	if u.EffectiveDate != nil {
+		if u.Salary == nil && u.Position == nil {
+			validationErr.AddFieldError("effective_date", "Effective date requires a salary or position change")
+		} else if _, err := time.Parse(DateLayout, *u.EffectiveDate); err != nil {
+			validationErr.AddFieldError("effective_date", "Effective date must be in YYYY-MM-DD format")
+		}
+	}
+
 	return validationErr.Validate()
 }
 
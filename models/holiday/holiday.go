@@ -0,0 +1,73 @@
+package holiday
+
+import (
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// Holiday represents a public holiday in the company calendar.
+// An empty Location means the holiday applies to every location.
+type Holiday struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Date      time.Time `json:"date"`
+	Year      int       `json:"year"`
+	Location  string    `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateHolidayRequest represents the request to add a holiday
+type CreateHolidayRequest struct {
+	Name     string `json:"name"`
+	Date     string `json:"date"` // format: YYYY-MM-DD
+	Location string `json:"location"`
+}
+
+// UpdateHolidayRequest represents the request to update a holiday
+type UpdateHolidayRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Date     *string `json:"date,omitempty"` // format: YYYY-MM-DD
+	Location *string `json:"location,omitempty"`
+}
+
+// Validate validates the CreateHolidayRequest, trimming the name
+func (r *CreateHolidayRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		validationErr.AddField("name", "name is required")
+	}
+
+	if r.Date == "" {
+		validationErr.AddField("date", "date is required")
+	} else if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+		validationErr.AddField("date", "invalid date format (use YYYY-MM-DD)")
+	}
+
+	return validationErr.Validate()
+}
+
+// Validate validates the UpdateHolidayRequest, trimming the name
+func (r *UpdateHolidayRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		r.Name = &name
+		if name == "" {
+			validationErr.AddField("name", "name cannot be empty")
+		}
+	}
+
+	if r.Date != nil {
+		if _, err := time.Parse("2006-01-02", *r.Date); err != nil {
+			validationErr.AddField("date", "invalid date format (use YYYY-MM-DD)")
+		}
+	}
+
+	return validationErr.Validate()
+}
@@ -0,0 +1,48 @@
+package holiday_test
+
+import (
+	"testing"
+
+	"employee-service/models/holiday"
+)
+
+// TestCreateHolidayRequestValidate tests holiday validation, including blank names
+func TestCreateHolidayRequestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   holiday.CreateHolidayRequest
+		valid bool
+	}{
+		{"Valid", holiday.CreateHolidayRequest{Name: "New Year", Date: "2026-01-01"}, true},
+		{"No name", holiday.CreateHolidayRequest{Date: "2026-01-01"}, false},
+		{"Blank name", holiday.CreateHolidayRequest{Name: "   ", Date: "2026-01-01"}, false},
+		{"Bad date", holiday.CreateHolidayRequest{Name: "New Year", Date: "01/01/2026"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.req.Validate(); (err == nil) != c.valid {
+				t.Errorf("Expected valid %v, got %v", c.valid, err)
+			}
+		})
+	}
+
+	req := holiday.CreateHolidayRequest{Name: "  New Year ", Date: "2026-01-01"}
+	if err := req.Validate(); err != nil || req.Name != "New Year" {
+		t.Errorf("Expected the name to be trimmed, got %q (%v)", req.Name, err)
+	}
+}
+
+// TestUpdateHolidayRequestValidate tests that a renamed holiday cannot be left blank
+func TestUpdateHolidayRequestValidate(t *testing.T) {
+	blank := "  "
+	if err := (&holiday.UpdateHolidayRequest{Name: &blank}).Validate(); err == nil {
+		t.Error("Expected a blank name to be rejected")
+	}
+
+	name := " Labour Day "
+	req := holiday.UpdateHolidayRequest{Name: &name}
+	if err := req.Validate(); err != nil || *req.Name != "Labour Day" {
+		t.Errorf("Expected the name to be trimmed, got %q (%v)", *req.Name, err)
+	}
+}
@@ -0,0 +1,147 @@
+package leave
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Accrual frequencies for leave policies
+const (
+	AccrualMonthly = "MONTHLY" // AnnualEntitlement/12 credited each month, pro-rated in the joining month
+	AccrualAnnual  = "ANNUAL"  // Full AnnualEntitlement credited once per calendar year
+)
+
+// IsValidAccrualFrequency checks if the accrual frequency is supported
+func IsValidAccrualFrequency(frequency string) bool {
+	switch strings.ToUpper(frequency) {
+	case AccrualMonthly, AccrualAnnual:
+		return true
+	}
+	return false
+}
+
+// LeaveAccrual is a ledger entry recording a single accrual credit.
+// Each (employee, leave type, period) is credited at most once.
+type LeaveAccrual struct {
+	ID           int       `json:"id"`
+	EmployeeID   int       `json:"employee_id"`
+	LeaveType    LeaveType `json:"leave_type"`
+	Period       string    `json:"period"`        // "2006-01" for monthly, "2006" for annual accruals
+	Days         float64   `json:"days"`          // Fractional days accrued for the period
+	CreditedDays float64   `json:"credited_days"` // Days added to the balance by this entry
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Year returns the calendar year of the accrual period
+func (a *LeaveAccrual) Year() int {
+	year, _ := strconv.Atoi(a.Period[:4])
+	return year
+}
+
+// PeriodStart returns the first day of the accrual period
+func (a *LeaveAccrual) PeriodStart() time.Time {
+	if len(a.Period) == len("2006-01") {
+		if start, err := time.Parse("2006-01", a.Period); err == nil {
+			return start
+		}
+	}
+	return time.Date(a.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// AccrualRunResult summarizes a run of the accrual job
+type AccrualRunResult struct {
+	AsOf               string  `json:"as_of"`
+	EmployeesProcessed int     `json:"employees_processed"`
+	EntriesCreated     int     `json:"entries_created"`
+	EntriesSkipped     int     `json:"entries_skipped"` // Periods that were already accrued
+	DaysAccrued        float64 `json:"days_accrued"`
+	LowBalanceNotices  int     `json:"low_balance_notices"` // Low-balance warnings queued after the run
+	Errors             int     `json:"errors"`
+}
+
+// PeriodOf returns the accrual period containing a date: "2006-01" for monthly, "2006" for annual accruals
+func (p *LeavePolicy) PeriodOf(date time.Time) string {
+	if strings.ToUpper(p.AccrualFrequency) == AccrualAnnual {
+		return date.Format("2006")
+	}
+	return date.Format("2006-01")
+}
+
+// AccrualPeriod describes one period an employee is due an accrual for
+type AccrualPeriod struct {
+	Period string
+	Days   float64
+}
+
+// DueAccruals returns the accrual periods from the start of asOf's year (or the
+// hire date, if later) up to and including the period containing asOf.
+// Monthly accruals are pro-rated by calendar days in the month the employee joined.
+// Annual accruals are credited in full regardless of the hire date.
+func (p *LeavePolicy) DueAccruals(hiredDate, asOf time.Time) []AccrualPeriod {
+	periods := []AccrualPeriod{}
+	if p.AnnualEntitlement <= 0 {
+		return periods
+	}
+
+	hired := dateOnly(hiredDate)
+	asOf = dateOnly(asOf)
+	if hired.After(asOf) {
+		return periods
+	}
+
+	if strings.ToUpper(p.AccrualFrequency) == AccrualAnnual {
+		return append(periods, AccrualPeriod{
+			Period: asOf.Format("2006"),
+			Days:   float64(p.AnnualEntitlement),
+		})
+	}
+
+	entitlement := float64(p.AnnualEntitlement)
+	month := time.Date(asOf.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	for !month.After(asOf) {
+		monthEnd := month.AddDate(0, 1, -1)
+		if !hired.After(monthEnd) {
+			// Credit the difference in cumulative entitlement so twelve months sum to exactly AnnualEntitlement
+			m := float64(month.Month())
+			days := RoundDays(entitlement*m/12) - RoundDays(entitlement*(m-1)/12)
+			if hired.After(month) {
+				daysInMonth := monthEnd.Day()
+				daysEmployed := daysInMonth - hired.Day() + 1
+				days = days * float64(daysEmployed) / float64(daysInMonth)
+			}
+			periods = append(periods, AccrualPeriod{
+				Period: month.Format("2006-01"),
+				Days:   RoundDays(days),
+			})
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+
+	return periods
+}
+
+// PendingAccruals returns the periods of DueAccruals that have no entry for the policy's leave type
+// in an employee's accrual ledger. Periods marked as accrued for balances granted up front, before
+// accrual was introduced, are never credited again.
+func (p *LeavePolicy) PendingAccruals(hiredDate, asOf time.Time, accrued []LeaveAccrual) []AccrualPeriod {
+	done := map[string]bool{}
+	for _, a := range accrued {
+		if a.LeaveType == p.LeaveType {
+			done[a.Period] = true
+		}
+	}
+
+	pending := []AccrualPeriod{}
+	for _, due := range p.DueAccruals(hiredDate, asOf) {
+		if !done[due.Period] {
+			pending = append(pending, due)
+		}
+	}
+	return pending
+}
+
+// dateOnly strips the time of day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
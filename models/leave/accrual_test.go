@@ -0,0 +1,148 @@
+package leave_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/leave"
+)
+
+// TestDueAccruals tests monthly and annual accrual periods and pro-rating
+func TestDueAccruals(t *testing.T) {
+	monthly := &leave.LeavePolicy{LeaveType: leave.TypeAnnual, AnnualEntitlement: 20, AccrualFrequency: leave.AccrualMonthly}
+
+	t.Run("Full year sums to the entitlement", func(t *testing.T) {
+		periods := monthly.DueAccruals(mustDate(t, "2020-06-01"), mustDate(t, "2025-12-31"))
+
+		if len(periods) != 12 {
+			t.Fatalf("Expected 12 periods, got %d", len(periods))
+		}
+		if periods[0].Period != "2025-01" || periods[0].Days != 1.667 {
+			t.Errorf("Unexpected first period: %+v", periods[0])
+		}
+
+		total := 0.0
+		for _, p := range periods {
+			total += p.Days
+		}
+		if total < 19.999 || total > 20.001 {
+			t.Errorf("Expected 20 days accrued over the year, got %.2f", total)
+		}
+	})
+
+	t.Run("Mid-month joiner is pro-rated", func(t *testing.T) {
+		// Joined 2025-04-16: 15 of 30 days in April
+		periods := monthly.DueAccruals(mustDate(t, "2025-04-16"), mustDate(t, "2025-05-01"))
+
+		if len(periods) != 2 {
+			t.Fatalf("Expected 2 periods, got %d", len(periods))
+		}
+		if periods[0].Period != "2025-04" || periods[0].Days != 0.833 {
+			t.Errorf("Expected 0.833 days for April, got %+v", periods[0])
+		}
+		if periods[1].Period != "2025-05" || periods[1].Days != 1.666 {
+			t.Errorf("Expected 1.666 days for May, got %+v", periods[1])
+		}
+	})
+
+	t.Run("Future hire accrues nothing", func(t *testing.T) {
+		periods := monthly.DueAccruals(mustDate(t, "2025-08-01"), mustDate(t, "2025-07-31"))
+		if len(periods) != 0 {
+			t.Errorf("Expected no periods, got %+v", periods)
+		}
+	})
+
+	t.Run("Annual policy credits the full entitlement once", func(t *testing.T) {
+		annual := &leave.LeavePolicy{LeaveType: leave.TypeMaternity, AnnualEntitlement: 90, AccrualFrequency: leave.AccrualAnnual}
+		periods := annual.DueAccruals(mustDate(t, "2025-10-20"), mustDate(t, "2025-11-01"))
+
+		if len(periods) != 1 || periods[0].Period != "2025" || periods[0].Days != 90 {
+			t.Errorf("Expected a single 90 day credit for 2025, got %+v", periods)
+		}
+	})
+}
+
+// TestPendingAccruals tests that periods already in the accrual ledger are not credited again
+func TestPendingAccruals(t *testing.T) {
+	monthly := &leave.LeavePolicy{LeaveType: leave.TypeAnnual, AnnualEntitlement: 12, AccrualFrequency: leave.AccrualMonthly}
+
+	t.Run("Existing employee with an up-front balance", func(t *testing.T) {
+		// The migration marks every period of the year the full entitlement was granted in
+		accrued := []leave.LeaveAccrual{}
+		for month := 1; month <= 12; month++ {
+			accrued = append(accrued, leave.LeaveAccrual{
+				LeaveType: leave.TypeAnnual,
+				Period:    time.Date(2026, time.Month(month), 1, 0, 0, 0, 0, time.UTC).Format("2006-01"),
+				Days:      1,
+			})
+		}
+		hired := mustDate(t, "2019-03-11")
+
+		if pending := monthly.PendingAccruals(hired, mustDate(t, "2026-10-16"), accrued); len(pending) != 0 {
+			t.Errorf("Expected no accruals on top of the up-front balance, got %+v", pending)
+		}
+
+		pending := monthly.PendingAccruals(hired, mustDate(t, "2027-01-05"), accrued)
+		if len(pending) != 1 || pending[0].Period != "2027-01" || pending[0].Days != 1 {
+			t.Errorf("Expected accrual to resume in 2027-01, got %+v", pending)
+		}
+	})
+
+	t.Run("Only missing periods of the same leave type", func(t *testing.T) {
+		accrued := []leave.LeaveAccrual{
+			{LeaveType: leave.TypeAnnual, Period: "2026-01", Days: 1},
+			{LeaveType: leave.TypeSick, Period: "2026-02", Days: 1},
+		}
+
+		pending := monthly.PendingAccruals(mustDate(t, "2025-06-01"), mustDate(t, "2026-02-10"), accrued)
+		if len(pending) != 1 || pending[0].Period != "2026-02" {
+			t.Errorf("Expected only 2026-02 to be pending, got %+v", pending)
+		}
+	})
+}
+
+// TestLowBalance tests the low-balance threshold and the period a warning is rate-limited to
+func TestLowBalance(t *testing.T) {
+	policy := &leave.LeavePolicy{LeaveType: leave.TypeAnnual, AccrualFrequency: leave.AccrualMonthly, LowBalanceThreshold: 2}
+
+	if !policy.IsLowBalance(2) || !policy.IsLowBalance(0.5) {
+		t.Error("Expected balances at or below the threshold to be low")
+	}
+	if policy.IsLowBalance(2.5) {
+		t.Error("Expected a balance above the threshold not to be low")
+	}
+
+	disabled := &leave.LeavePolicy{LeaveType: leave.TypeSick}
+	if disabled.IsLowBalance(0) {
+		t.Error("Expected a zero threshold to disable low-balance warnings")
+	}
+
+	date := time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC)
+	if got := policy.PeriodOf(date); got != "2026-03" {
+		t.Errorf("Monthly period = %q, expected 2026-03", got)
+	}
+	policy.AccrualFrequency = leave.AccrualAnnual
+	if got := policy.PeriodOf(date); got != "2026" {
+		t.Errorf("Annual period = %q, expected 2026", got)
+	}
+}
+
+// TestAccrualPeriodStart tests the day an accrual counts towards the balance from
+func TestAccrualPeriodStart(t *testing.T) {
+	cases := []struct {
+		period   string
+		expected string
+	}{
+		{"2026-03", "2026-03-01"},
+		{"2027", "2027-01-01"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.period, func(t *testing.T) {
+			accrual := leave.LeaveAccrual{Period: c.period}
+			if got := accrual.PeriodStart(); !got.Equal(mustDate(t, c.expected)) {
+				t.Errorf("Expected %s, got %s", c.expected, got.Format("2006-01-02"))
+			}
+		})
+	}
+}
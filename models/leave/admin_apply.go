@@ -0,0 +1,61 @@
+package leave
+
+import (
+	"strings"
+
+	"employee-service/errors"
+)
+
+// AdminApplyLeaveRequest represents the request HR makes to record leave on behalf of an employee,
+// e.g. one who is in hospital or has no login. The leave goes through the same checks as an
+// employee's own application unless OverridePolicy is set, which waives the policy's eligibility,
+// notice and document rules and accepts dates in the past. Balance and overlap checks always apply.
+type AdminApplyLeaveRequest struct {
+	ApplyLeaveRequest
+	EmployeeID     int    `json:"employee_id"`
+	AutoApprove    bool   `json:"auto_approve"`    // Approve the leave straight away instead of sending it through the approval chain
+	OverridePolicy bool   `json:"override_policy"` // Waive the leave policy's rules
+	OverrideReason string `json:"override_reason"` // Why the policy was waived; required with OverridePolicy
+}
+
+// Validate validates the AdminApplyLeaveRequest
+func (r *AdminApplyLeaveRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+	if err := r.ApplyLeaveRequest.validate(r.OverridePolicy); err != nil {
+		validationErr = err.(*errors.ValidationError)
+	}
+
+	if r.EmployeeID <= 0 {
+		validationErr.AddField("employee_id", "employee_id is required")
+	}
+
+	r.OverrideReason = strings.TrimSpace(r.OverrideReason)
+	if r.OverridePolicy && r.OverrideReason == "" {
+		validationErr.AddField("override_reason", "override_reason is required when overriding the leave policy")
+	} else if !r.OverridePolicy && r.OverrideReason != "" {
+		validationErr.AddField("override_reason", "override_reason can only be set when overriding the leave policy")
+	}
+
+	return validationErr.Validate()
+}
+
+// AuditValues describes leave entered on behalf of an employee for the audit log, including who
+// entered it and whether the policy was waived
+func (r *AdminApplyLeaveRequest) AuditValues(lr *LeaveRequest, enteredBy int) map[string]interface{} {
+	values := map[string]interface{}{
+		"employee_id":     lr.EmployeeID,
+		"leave_type":      lr.LeaveType,
+		"start_date":      lr.StartDate.Format("2006-01-02"),
+		"end_date":        lr.EndDate.Format("2006-01-02"),
+		"days_count":      lr.DaysCount,
+		"status":          lr.Status,
+		"entered_by":      enteredBy,
+		"on_behalf":       true,
+		"auto_approved":   r.AutoApprove && lr.Status == StatusApproved,
+		"policy_override": r.OverridePolicy,
+	}
+	if r.OverridePolicy {
+		values["override_reason"] = r.OverrideReason
+	}
+	return values
+}
@@ -0,0 +1,74 @@
+package leave_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/leave"
+)
+
+// TestAdminApplyLeaveRequestValidate tests validation of leave entered on behalf of an employee
+func TestAdminApplyLeaveRequestValidate(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	upcoming := leave.ApplyLeaveRequest{LeaveType: leave.TypeSick, StartDate: tomorrow, EndDate: tomorrow, Reason: "surgery"}
+	past := leave.ApplyLeaveRequest{LeaveType: leave.TypeSick, StartDate: lastWeek, EndDate: lastWeek, Reason: "in hospital"}
+
+	cases := []struct {
+		name  string
+		req   leave.AdminApplyLeaveRequest
+		field string
+	}{
+		{"Upcoming leave", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: upcoming, EmployeeID: 7}, ""},
+		{"Auto-approved", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: upcoming, EmployeeID: 7, AutoApprove: true}, ""},
+		{"No employee", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: upcoming}, "employee_id"},
+		{"Past dates without override", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: past, EmployeeID: 7}, "start_date"},
+		{"Past dates with override", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: past, EmployeeID: 7, OverridePolicy: true, OverrideReason: "Admitted to hospital"}, ""},
+		{"Override without reason", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: upcoming, EmployeeID: 7, OverridePolicy: true, OverrideReason: "  "}, "override_reason"},
+		{"Reason without override", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: upcoming, EmployeeID: 7, OverrideReason: "Admitted to hospital"}, "override_reason"},
+		{"Override keeps other checks", leave.AdminApplyLeaveRequest{ApplyLeaveRequest: leave.ApplyLeaveRequest{StartDate: tomorrow, EndDate: tomorrow, Reason: "surgery"}, EmployeeID: 7, OverridePolicy: true, OverrideReason: "Agreed by HR"}, "leave_type"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.req.Validate(), c.field)
+		})
+	}
+}
+
+// TestAdminApplyLeaveRequestAuditValues tests what the audit log records about entered leave
+func TestAdminApplyLeaveRequestAuditValues(t *testing.T) {
+	lr := &leave.LeaveRequest{ID: 12, EmployeeID: 7, LeaveType: leave.TypeSick, StartDate: mustDate(t, "2026-03-02"),
+		EndDate: mustDate(t, "2026-03-04"), DaysCount: 3, Status: leave.StatusApproved}
+
+	req := leave.AdminApplyLeaveRequest{EmployeeID: 7, AutoApprove: true, OverridePolicy: true, OverrideReason: "Admitted to hospital"}
+	values := req.AuditValues(lr, 1)
+	expected := map[string]interface{}{
+		"employee_id":     7,
+		"leave_type":      leave.TypeSick,
+		"start_date":      "2026-03-02",
+		"end_date":        "2026-03-04",
+		"status":          leave.StatusApproved,
+		"entered_by":      1,
+		"on_behalf":       true,
+		"auto_approved":   true,
+		"policy_override": true,
+		"override_reason": "Admitted to hospital",
+	}
+	for key, want := range expected {
+		if values[key] != want {
+			t.Errorf("Expected %s to be %v, got %v", key, want, values[key])
+		}
+	}
+
+	// Leave left pending, e.g. after a failed approval, is not recorded as approved
+	lr.Status = leave.StatusPending
+	req = leave.AdminApplyLeaveRequest{EmployeeID: 7, AutoApprove: true}
+	values = req.AuditValues(lr, 1)
+	if values["auto_approved"] != false || values["policy_override"] != false {
+		t.Errorf("Expected neither an approval nor an override, got %v", values)
+	}
+	if _, ok := values["override_reason"]; ok {
+		t.Errorf("Expected no override reason without an override, got %v", values["override_reason"])
+	}
+}
@@ -0,0 +1,215 @@
+package leave
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// ApproverRole identifies who decides a step of an approval chain
+type ApproverRole string
+
+const (
+	ApproverManager ApproverRole = "MANAGER" // The employee's line manager
+	ApproverHR      ApproverRole = "HR"      // HR administrators
+)
+
+// IsValidApproverRole checks if the approver role is supported
+func IsValidApproverRole(role ApproverRole) bool {
+	return role == ApproverManager || role == ApproverHR
+}
+
+// ApprovalDecision is the outcome of an approval step
+type ApprovalDecision string
+
+const (
+	DecisionPending  ApprovalDecision = "PENDING"
+	DecisionApproved ApprovalDecision = "APPROVED"
+	DecisionRejected ApprovalDecision = "REJECTED"
+	DecisionSkipped  ApprovalDecision = "SKIPPED" // Never reached because the request was rejected or cancelled first
+)
+
+// ApprovalRule adds a step to the approval chain of matching leave requests.
+// A rule without conditions applies to every request. Otherwise it applies when the
+// request is longer than OverDays or its leave type is one of LeaveTypes.
+type ApprovalRule struct {
+	ID           int          `json:"id"`
+	StepOrder    int          `json:"step_order"`
+	ApproverRole ApproverRole `json:"approver_role"`
+	OverDays     float64      `json:"over_days"`   // 0 = no duration condition
+	LeaveTypes   []LeaveType  `json:"leave_types"` // Empty = no leave type condition
+	IsActive     bool         `json:"is_active"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// Matches reports whether the rule applies to a leave request
+func (r *ApprovalRule) Matches(lr *LeaveRequest) bool {
+	if r.OverDays <= 0 && len(r.LeaveTypes) == 0 {
+		return true
+	}
+	if r.OverDays > 0 && lr.DaysCount > r.OverDays {
+		return true
+	}
+	for _, leaveType := range r.LeaveTypes {
+		if leaveType == lr.LeaveType {
+			return true
+		}
+	}
+	return false
+}
+
+// LeaveApproval is one step of the approval chain of a leave request
+type LeaveApproval struct {
+	ID               int              `json:"id"`
+	LeaveRequestID   int              `json:"leave_request_id"`
+	Step             int              `json:"step"` // 1-based position in the chain
+	ApproverRole     ApproverRole     `json:"approver_role"`
+	ApproverUserID   *int             `json:"approver_user_id"`     // User who decided the step
+	OnBehalfOfUserID *int             `json:"on_behalf_of_user_id"` // Delegator, when the step was decided by their delegate
+	Decision         ApprovalDecision `json:"decision"`
+	Comment          string           `json:"comment"`
+	DecidedAt        *time.Time       `json:"decided_at"`
+	RemindedAt       *time.Time       `json:"reminded_at"`       // When the approver was reminded of the waiting step
+	EscalatedAt      *time.Time       `json:"escalated_at"`      // When the waiting step was escalated
+	InfoRequestedAt  *time.Time       `json:"info_requested_at"` // When an approver asked the employee for more information; nil once answered
+	PausedSeconds    int64            `json:"paused_seconds"`    // Time spent waiting for information, not counted towards reminders
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// Waiting returns how long a step has waited for a decision since pendingSince, leaving out the
+// time it was paused waiting for more information from the employee
+func (a *LeaveApproval) Waiting(pendingSince, now time.Time) time.Duration {
+	waiting := now.Sub(pendingSince) - time.Duration(a.PausedSeconds)*time.Second
+	if a.InfoRequestedAt != nil {
+		waiting -= now.Sub(*a.InfoRequestedAt)
+	}
+	if waiting < 0 {
+		return 0
+	}
+	return waiting
+}
+
+// AwaitingInfo reports whether the step awaiting a decision is paused until the employee answers
+// a request for more information
+func AwaitingInfo(approvals []LeaveApproval) bool {
+	step := CurrentApproval(approvals)
+	return step != nil && step.InfoRequestedAt != nil
+}
+
+// BuildApprovalChain returns the approval steps for a leave request from the active rules,
+// ordered by StepOrder. A request that matches no rule is approved by HR in a single step.
+func BuildApprovalChain(rules []ApprovalRule, lr *LeaveRequest) []LeaveApproval {
+	sorted := make([]ApprovalRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StepOrder < sorted[j].StepOrder })
+
+	chain := []LeaveApproval{}
+	for i := range sorted {
+		rule := &sorted[i]
+		if !rule.IsActive || !rule.Matches(lr) {
+			continue
+		}
+		chain = append(chain, LeaveApproval{
+			Step:         len(chain) + 1,
+			ApproverRole: rule.ApproverRole,
+			Decision:     DecisionPending,
+		})
+	}
+
+	if len(chain) == 0 {
+		chain = append(chain, LeaveApproval{Step: 1, ApproverRole: ApproverHR, Decision: DecisionPending})
+	}
+	return chain
+}
+
+// CurrentApproval returns the first undecided step of an approval chain, or nil if
+// every step has been decided
+func CurrentApproval(approvals []LeaveApproval) *LeaveApproval {
+	for i := range approvals {
+		if approvals[i].Decision == DecisionPending {
+			return &approvals[i]
+		}
+	}
+	return nil
+}
+
+// IsFinalApproval reports whether a step is the last one of its chain
+func IsFinalApproval(approvals []LeaveApproval, step *LeaveApproval) bool {
+	for _, a := range approvals {
+		if a.Step > step.Step {
+			return false
+		}
+	}
+	return true
+}
+
+// DecidedEarlierStep reports whether a user decided a step before the given one, either
+// themselves or through a delegate acting on their behalf
+func DecidedEarlierStep(approvals []LeaveApproval, step *LeaveApproval, userID int) bool {
+	for _, a := range approvals {
+		if a.Step >= step.Step || a.Decision == DecisionPending || a.Decision == DecisionSkipped {
+			continue
+		}
+		if (a.ApproverUserID != nil && *a.ApproverUserID == userID) || (a.OnBehalfOfUserID != nil && *a.OnBehalfOfUserID == userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckNotOwnRequest refuses a decision on a request by the employee it belongs to. No role is
+// exempt, so an admin who is also an employee cannot approve their own leave and its salary
+// deduction. deciderEmployeeID is 0 when the decider has no employee record.
+func CheckNotOwnRequest(requestEmployeeID, deciderEmployeeID int, request string) error {
+	if deciderEmployeeID != 0 && deciderEmployeeID == requestEmployeeID {
+		return errors.NewForbiddenError("you cannot decide your own " + request)
+	}
+	return nil
+}
+
+// PendingSince returns when a step started waiting for a decision: when the previous step was
+// decided, or when the request was submitted for the first step
+func PendingSince(approvals []LeaveApproval, step *LeaveApproval, requestedAt time.Time) time.Time {
+	since := requestedAt
+	for _, a := range approvals {
+		if a.Step < step.Step && a.DecidedAt != nil && a.DecidedAt.After(since) {
+			since = *a.DecidedAt
+		}
+	}
+	return since
+}
+
+// ApprovalRuleRequest represents the request to create or replace an approval rule
+type ApprovalRuleRequest struct {
+	StepOrder    int          `json:"step_order"`
+	ApproverRole ApproverRole `json:"approver_role"`
+	OverDays     float64      `json:"over_days"`
+	LeaveTypes   []LeaveType  `json:"leave_types"`
+	IsActive     *bool        `json:"is_active,omitempty"` // defaults to true
+}
+
+// Validate validates the ApprovalRuleRequest
+func (r *ApprovalRuleRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.StepOrder < 1 {
+		validationErr.AddField("step_order", "step_order must be at least 1")
+	}
+	if !IsValidApproverRole(ApproverRole(strings.ToUpper(string(r.ApproverRole)))) {
+		validationErr.AddField("approver_role", "approver_role must be MANAGER or HR")
+	}
+	if r.OverDays < 0 {
+		validationErr.AddField("over_days", "over_days cannot be negative")
+	}
+	for _, leaveType := range r.LeaveTypes {
+		if strings.TrimSpace(string(leaveType)) == "" {
+			validationErr.AddField("leave_types", "leave_types cannot contain empty values")
+			break
+		}
+	}
+
+	return validationErr.Validate()
+}
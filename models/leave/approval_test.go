@@ -0,0 +1,131 @@
+package leave_test
+
+import (
+	"testing"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// TestBuildApprovalChain tests which approval steps apply to a leave request
+func TestBuildApprovalChain(t *testing.T) {
+	rules := []leave.ApprovalRule{
+		{StepOrder: 2, ApproverRole: leave.ApproverHR, OverDays: 5, LeaveTypes: []leave.LeaveType{leave.TypeMaternity}, IsActive: true},
+		{StepOrder: 1, ApproverRole: leave.ApproverManager, IsActive: true},
+	}
+
+	cases := []struct {
+		name     string
+		request  leave.LeaveRequest
+		expected []leave.ApproverRole
+	}{
+		{"Short leave", leave.LeaveRequest{LeaveType: leave.TypeAnnual, DaysCount: 3}, []leave.ApproverRole{leave.ApproverManager}},
+		{"Exactly the threshold", leave.LeaveRequest{LeaveType: leave.TypeAnnual, DaysCount: 5}, []leave.ApproverRole{leave.ApproverManager}},
+		{"Long leave", leave.LeaveRequest{LeaveType: leave.TypeAnnual, DaysCount: 5.5}, []leave.ApproverRole{leave.ApproverManager, leave.ApproverHR}},
+		{"Maternity", leave.LeaveRequest{LeaveType: leave.TypeMaternity, DaysCount: 2}, []leave.ApproverRole{leave.ApproverManager, leave.ApproverHR}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			chain := leave.BuildApprovalChain(rules, &c.request)
+			if len(chain) != len(c.expected) {
+				t.Fatalf("Expected %d steps, got %d", len(c.expected), len(chain))
+			}
+			for i, step := range chain {
+				if step.Step != i+1 || step.ApproverRole != c.expected[i] || step.Decision != leave.DecisionPending {
+					t.Errorf("Unexpected step %d: %+v", i+1, step)
+				}
+			}
+		})
+	}
+
+	t.Run("No matching rule", func(t *testing.T) {
+		inactive := []leave.ApprovalRule{{StepOrder: 1, ApproverRole: leave.ApproverManager, IsActive: false}}
+		chain := leave.BuildApprovalChain(inactive, &leave.LeaveRequest{LeaveType: leave.TypeAnnual, DaysCount: 1})
+		if len(chain) != 1 || chain[0].ApproverRole != leave.ApproverHR {
+			t.Errorf("Expected a single HR step, got %+v", chain)
+		}
+	})
+}
+
+// TestCurrentApproval tests finding the step awaiting a decision
+func TestCurrentApproval(t *testing.T) {
+	approvals := []leave.LeaveApproval{
+		{Step: 1, Decision: leave.DecisionApproved},
+		{Step: 2, Decision: leave.DecisionPending},
+	}
+
+	current := leave.CurrentApproval(approvals)
+	if current == nil || current.Step != 2 {
+		t.Fatalf("Expected step 2 to be current, got %+v", current)
+	}
+	if !leave.IsFinalApproval(approvals, current) {
+		t.Error("Expected step 2 to be the final step")
+	}
+	if leave.IsFinalApproval(approvals, &approvals[0]) {
+		t.Error("Expected step 1 not to be the final step")
+	}
+
+	approvals[1].Decision = leave.DecisionApproved
+	if leave.CurrentApproval(approvals) != nil {
+		t.Error("Expected no current step once every step is decided")
+	}
+}
+
+// TestDecidedEarlierStep tests that users who decided an earlier step are recognised
+func TestDecidedEarlierStep(t *testing.T) {
+	manager, delegate, delegator := 7, 8, 9
+	approvals := []leave.LeaveApproval{
+		{Step: 1, ApproverRole: leave.ApproverManager, ApproverUserID: &manager, Decision: leave.DecisionApproved},
+		{Step: 2, ApproverRole: leave.ApproverHR, ApproverUserID: &delegate, OnBehalfOfUserID: &delegator, Decision: leave.DecisionApproved},
+		{Step: 3, ApproverRole: leave.ApproverHR, Decision: leave.DecisionPending},
+	}
+
+	cases := []struct {
+		name     string
+		step     int
+		userID   int
+		expected bool
+	}{
+		{"Approver of the first step", 3, manager, true},
+		{"Delegate of the second step", 3, delegate, true},
+		{"Delegator of the second step", 3, delegator, true},
+		{"Someone else", 3, 10, false},
+		{"Later steps are ignored", 2, delegate, false},
+		{"First step has no earlier steps", 1, manager, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := leave.DecidedEarlierStep(approvals, &approvals[c.step-1], c.userID); got != c.expected {
+				t.Errorf("Expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestCheckNotOwnRequest tests that nobody, admins included, decides their own request
+func TestCheckNotOwnRequest(t *testing.T) {
+	cases := []struct {
+		name     string
+		decider  int // Employee ID of the approver; 0 without an employee record
+		employee int
+		allowed  bool
+	}{
+		{"Admin approving their own leave", 7, 7, false},
+		{"Manager approving a report's leave", 3, 7, true},
+		{"Admin without an employee record", 0, 7, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := leave.CheckNotOwnRequest(c.employee, c.decider, "leave request")
+			if (err == nil) != c.allowed {
+				t.Fatalf("Expected allowed %v, got %v", c.allowed, err)
+			}
+			if _, ok := err.(*errors.ForbiddenError); err != nil && !ok {
+				t.Errorf("Expected a forbidden error, got %T", err)
+			}
+		})
+	}
+}
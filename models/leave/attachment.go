@@ -0,0 +1,122 @@
+package leave
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"employee-service/errors"
+)
+
+// LeaveAttachment is a file supporting a leave request, such as a medical certificate
+type LeaveAttachment struct {
+	ID             int       `json:"id"`
+	LeaveRequestID int       `json:"leave_request_id"`
+	FileName       string    `json:"file_name"`
+	ContentType    string    `json:"content_type"`
+	Size           int64     `json:"size"` // Bytes
+	StorageKey     string    `json:"-"`    // Where the file is kept in attachment storage
+	UploadedBy     int       `json:"uploaded_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AttachmentUpload is a file uploaded for a leave request before it is stored. ContentType is
+// detected from the content rather than taken from the client.
+type AttachmentUpload struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// AttachmentLimits restricts the files that can be attached to a leave request
+type AttachmentLimits struct {
+	MaxSize      int64    `json:"max_size"`  // Bytes per file
+	MaxFiles     int      `json:"max_files"` // Files per leave request
+	AllowedTypes []string `json:"allowed_types"`
+}
+
+// DefaultAttachmentLimits accepts up to 5 PDF or image files of 5 MB each
+var DefaultAttachmentLimits = AttachmentLimits{
+	MaxSize:      5 << 20,
+	MaxFiles:     5,
+	AllowedTypes: []string{"application/pdf", "image/jpeg", "image/png"},
+}
+
+// Check validates uploads against the limits, given the number of files the leave request
+// already has
+func (l AttachmentLimits) Check(uploads []AttachmentUpload, existing int) error {
+	validationErr := errors.NewValidationError()
+
+	if existing+len(uploads) > l.MaxFiles {
+		validationErr.AddField("attachments", fmt.Sprintf("a leave request can have at most %d attachments", l.MaxFiles))
+	}
+
+	for _, u := range uploads {
+		switch {
+		case len(u.Content) == 0:
+			validationErr.AddField("attachments", fmt.Sprintf("%s is empty", u.FileName))
+		case int64(len(u.Content)) > l.MaxSize:
+			validationErr.AddField("attachments", fmt.Sprintf("%s is larger than %s", u.FileName, formatSize(l.MaxSize)))
+		case !l.allows(u.ContentType):
+			validationErr.AddField("attachments", fmt.Sprintf("%s is not an accepted file type (%s)", u.FileName, strings.Join(l.AllowedTypes, ", ")))
+		}
+	}
+
+	return validationErr.Validate()
+}
+
+// allows reports whether a content type is accepted, ignoring parameters such as the charset
+func (l AttachmentLimits) allows(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	for _, allowed := range l.AllowedTypes {
+		if strings.EqualFold(mediaType, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanFileName reduces an uploaded file name to a safe base name for storing and downloading
+func CleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
+
+// CheckAttachment validates that a leave request has the attachment the policy asks for, e.g. a
+// medical certificate for sick leave over 2 days
+func (p *LeavePolicy) CheckAttachment(daysCount float64, attachments int) error {
+	if attachments > 0 || !p.RequiresAttachment(daysCount) {
+		return nil
+	}
+	return errors.NewValidationError().AddField("attachments", fmt.Sprintf("%s leave over %s days requires a supporting document such as a medical certificate",
+		strings.ToLower(string(p.LeaveType)), FormatDays(p.AttachmentAfterDays)))
+}
+
+// RequiresAttachment reports whether leave of this type and length needs a supporting document
+func (p *LeavePolicy) RequiresAttachment(daysCount float64) bool {
+	return p.AttachmentAfterDays > 0 && daysCount > p.AttachmentAfterDays
+}
+
+// formatSize formats a number of bytes in KB or MB
+func formatSize(bytes int64) string {
+	if bytes >= 1<<20 && bytes%(1<<20) == 0 {
+		return fmt.Sprintf("%d MB", bytes>>20)
+	}
+	return fmt.Sprintf("%d KB", bytes>>10)
+}
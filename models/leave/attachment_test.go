@@ -0,0 +1,88 @@
+package leave_test
+
+import (
+	"bytes"
+	"testing"
+
+	"employee-service/models/leave"
+)
+
+// TestAttachmentLimitsCheck tests the file count, size and type limits of attachments
+func TestAttachmentLimitsCheck(t *testing.T) {
+	limits := leave.AttachmentLimits{MaxSize: 1024, MaxFiles: 2, AllowedTypes: []string{"application/pdf", "image/png"}}
+	pdf := leave.AttachmentUpload{FileName: "certificate.pdf", ContentType: "application/pdf", Content: []byte("%PDF-1.4")}
+
+	cases := []struct {
+		name     string
+		uploads  []leave.AttachmentUpload
+		existing int
+		field    string // Field expected to fail; empty when the uploads are accepted
+	}{
+		{"No uploads", nil, 0, ""},
+		{"Accepted", []leave.AttachmentUpload{pdf}, 0, ""},
+		{"Content type parameters ignored", []leave.AttachmentUpload{{FileName: "scan.png", ContentType: "IMAGE/PNG; charset=binary", Content: []byte{1}}}, 0, ""},
+		{"Up to the file limit", []leave.AttachmentUpload{pdf}, 1, ""},
+		{"Over the file limit", []leave.AttachmentUpload{pdf, pdf}, 1, "attachments"},
+		{"Empty file", []leave.AttachmentUpload{{FileName: "empty.pdf", ContentType: "application/pdf"}}, 0, "attachments"},
+		{"Exactly the size limit", []leave.AttachmentUpload{{FileName: "max.pdf", ContentType: "application/pdf", Content: bytes.Repeat([]byte{1}, 1024)}}, 0, ""},
+		{"Too large", []leave.AttachmentUpload{{FileName: "big.pdf", ContentType: "application/pdf", Content: bytes.Repeat([]byte{1}, 1025)}}, 0, "attachments"},
+		{"Type not accepted", []leave.AttachmentUpload{{FileName: "notes.txt", ContentType: "text/plain; charset=utf-8", Content: []byte("hi")}}, 0, "attachments"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, limits.Check(c.uploads, c.existing), c.field)
+		})
+	}
+}
+
+// TestCleanFileName tests that uploaded file names are reduced to safe base names
+func TestCleanFileName(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Plain name", "certificate.pdf", "certificate.pdf"},
+		{"Unix path", "../../etc/passwd", "passwd"},
+		{"Windows path", `C:\Users\me\scan.png`, "scan.png"},
+		{"Quotes and control characters", "med\"ical\r\n.pdf", "medical.pdf"},
+		{"Surrounding spaces", "  note.pdf  ", "note.pdf"},
+		{"Empty", "", "attachment"},
+		{"Only a directory", "uploads/", "uploads"},
+		{"Dot", ".", "attachment"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := leave.CleanFileName(c.input); got != c.expected {
+				t.Errorf("Expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestCheckAttachment tests that policies require a supporting document for longer leave
+func TestCheckAttachment(t *testing.T) {
+	sick := leave.LeavePolicy{LeaveType: leave.TypeSick, AttachmentAfterDays: 2, IsActive: true}
+	annual := leave.LeavePolicy{LeaveType: leave.TypeAnnual, IsActive: true}
+
+	cases := []struct {
+		name        string
+		policy      leave.LeavePolicy
+		days        float64
+		attachments int
+		field       string
+	}{
+		{"Short sick leave", sick, 2, 0, ""},
+		{"Long sick leave without certificate", sick, 2.5, 0, "attachments"},
+		{"Long sick leave with certificate", sick, 3, 1, ""},
+		{"No requirement", annual, 10, 0, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.policy.CheckAttachment(c.days, c.attachments), c.field)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package leave
+
+import (
+	"fmt"
+	"strings"
+
+	"employee-service/errors"
+)
+
+// MaxBulkDecisions is the most leave requests one bulk decision can cover
+const MaxBulkDecisions = 100
+
+// BulkAction is the decision applied to every request of a bulk decision
+type BulkAction string
+
+const (
+	BulkApprove BulkAction = "APPROVE"
+	BulkReject  BulkAction = "REJECT"
+)
+
+// BulkDecisionRequest represents the request to approve or reject several leave requests at once
+// with a shared note: approval notes when approving, the rejection reason when rejecting
+type BulkDecisionRequest struct {
+	Action BulkAction `json:"action"`
+	IDs    []int      `json:"ids"`
+	Note   string     `json:"note"`
+}
+
+// Validate validates the BulkDecisionRequest
+func (r *BulkDecisionRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.Action != BulkApprove && r.Action != BulkReject {
+		validationErr.AddField("action", "action must be APPROVE or REJECT")
+	}
+
+	switch {
+	case len(r.IDs) == 0:
+		validationErr.AddField("ids", "at least one leave request ID is required")
+	case len(r.IDs) > MaxBulkDecisions:
+		validationErr.AddField("ids", fmt.Sprintf("at most %d leave requests can be decided at once", MaxBulkDecisions))
+	default:
+		seen := map[int]bool{}
+		for _, id := range r.IDs {
+			if id <= 0 {
+				validationErr.AddField("ids", fmt.Sprintf("invalid leave request ID: %d", id))
+				break
+			}
+			if seen[id] {
+				validationErr.AddField("ids", fmt.Sprintf("leave request %d is listed more than once", id))
+				break
+			}
+			seen[id] = true
+		}
+	}
+
+	return validationErr.Validate()
+}
+
+// BulkDecisionItem is the outcome of a bulk decision for one leave request
+type BulkDecisionItem struct {
+	LeaveRequestID int               `json:"leave_request_id"`
+	Success        bool              `json:"success"`
+	Status         LeaveStatus       `json:"status,omitempty"` // Status after the decision; PENDING when an intermediate step was approved
+	Error          string            `json:"error,omitempty"`
+	Fields         map[string]string `json:"fields,omitempty"` // Validation failures, e.g. an insufficient balance
+}
+
+// BulkDecisionResult reports the outcome of a bulk decision per leave request, in request order
+type BulkDecisionResult struct {
+	Action    BulkAction         `json:"action"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []BulkDecisionItem `json:"results"`
+}
+
+// Add records the outcome of deciding a leave request: its status on success, or why it failed
+func (r *BulkDecisionResult) Add(id int, status LeaveStatus, err error) {
+	item := BulkDecisionItem{LeaveRequestID: id}
+
+	if err == nil {
+		item.Success = true
+		item.Status = status
+		r.Succeeded++
+	} else {
+		item.Error = err.Error()
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			item.Error = "validation failed"
+			item.Fields = validationErr.Fields
+		}
+		r.Failed++
+	}
+
+	r.Results = append(r.Results, item)
+}
+
+// DecisionSummary lists the outcome of decided leave requests, one line each, for the email that
+// tells an employee about several decisions at once
+func DecisionSummary(requests []*LeaveRequest) string {
+	var b strings.Builder
+	for _, lr := range requests {
+		fmt.Fprintf(&b, "- %s leave, %s to %s (%s days): %s", lr.LeaveType, lr.StartDate.Format("2006-01-02"),
+			lr.EndDate.Format("2006-01-02"), FormatDays(lr.DaysCount), lr.Status)
+		if lr.Status == StatusApproved && lr.SalaryDeduction > 0 {
+			fmt.Fprintf(&b, ", %.2f deducted from your pay", lr.SalaryDeduction)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package leave_test
+
+import (
+	"fmt"
+	"testing"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// TestBulkDecisionRequestValidate tests bulk decision validation
+func TestBulkDecisionRequestValidate(t *testing.T) {
+	tooMany := make([]int, leave.MaxBulkDecisions+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+
+	cases := []struct {
+		name  string
+		req   leave.BulkDecisionRequest
+		field string
+	}{
+		{"Approve", leave.BulkDecisionRequest{Action: leave.BulkApprove, IDs: []int{1, 2, 3}}, ""},
+		{"Reject with note", leave.BulkDecisionRequest{Action: leave.BulkReject, IDs: []int{4}, Note: "Team at capacity"}, ""},
+		{"Unknown action", leave.BulkDecisionRequest{Action: "DEFER", IDs: []int{1}}, "action"},
+		{"No IDs", leave.BulkDecisionRequest{Action: leave.BulkApprove}, "ids"},
+		{"Too many IDs", leave.BulkDecisionRequest{Action: leave.BulkApprove, IDs: tooMany}, "ids"},
+		{"Invalid ID", leave.BulkDecisionRequest{Action: leave.BulkApprove, IDs: []int{1, 0}}, "ids"},
+		{"Duplicate ID", leave.BulkDecisionRequest{Action: leave.BulkReject, IDs: []int{7, 8, 7}}, "ids"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.req.Validate(), c.field)
+		})
+	}
+}
+
+// TestBulkDecisionResultAdd tests that each outcome is reported and counted
+func TestBulkDecisionResultAdd(t *testing.T) {
+	result := leave.BulkDecisionResult{Action: leave.BulkApprove}
+	result.Add(1, leave.StatusApproved, nil)
+	result.Add(2, "", errors.NewValidationError().AddField("leave_balance", "insufficient leave balance"))
+	result.Add(3, leave.StatusPending, nil)
+	result.Add(4, "", errors.NewForbiddenError("you don't have permission to approve this leave request"))
+
+	if result.Succeeded != 2 || result.Failed != 2 {
+		t.Fatalf("Expected 2 succeeded and 2 failed, got %d and %d", result.Succeeded, result.Failed)
+	}
+	if len(result.Results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(result.Results))
+	}
+
+	for i, id := range []int{1, 2, 3, 4} {
+		if result.Results[i].LeaveRequestID != id {
+			t.Errorf("Result %d is for leave request %d, expected %d", i, result.Results[i].LeaveRequestID, id)
+		}
+	}
+	if r := result.Results[0]; !r.Success || r.Status != leave.StatusApproved || r.Error != "" {
+		t.Errorf("Unexpected result for an approval: %+v", r)
+	}
+	if r := result.Results[1]; r.Success || r.Fields["leave_balance"] == "" {
+		t.Errorf("Expected the balance failure in the fields, got %+v", r)
+	}
+	if r := result.Results[2]; !r.Success || r.Status != leave.StatusPending {
+		t.Errorf("Expected an intermediate approval to stay pending, got %+v", r)
+	}
+	if r := result.Results[3]; r.Success || r.Error == "" || r.Fields != nil {
+		t.Errorf("Expected the permission failure as an error message, got %+v", r)
+	}
+}
+
+// TestDecisionSummary tests the summary emailed for several decided requests
+func TestDecisionSummary(t *testing.T) {
+	requests := []*leave.LeaveRequest{
+		{LeaveType: leave.TypeAnnual, StartDate: mustDate(t, "2026-03-02"), EndDate: mustDate(t, "2026-03-04"), DaysCount: 3, Status: leave.StatusApproved, SalaryDeduction: 1500},
+		{LeaveType: leave.TypeCasual, StartDate: mustDate(t, "2026-03-10"), EndDate: mustDate(t, "2026-03-10"), DaysCount: 0.5, Status: leave.StatusApproved},
+		{LeaveType: leave.TypeSick, StartDate: mustDate(t, "2026-04-01"), EndDate: mustDate(t, "2026-04-02"), DaysCount: 2, Status: leave.StatusRejected},
+	}
+
+	expected := fmt.Sprint(
+		"- ANNUAL leave, 2026-03-02 to 2026-03-04 (3 days): APPROVED, 1500.00 deducted from your pay\n",
+		"- CASUAL leave, 2026-03-10 to 2026-03-10 (0.5 days): APPROVED\n",
+		"- SICK leave, 2026-04-01 to 2026-04-02 (2 days): REJECTED\n",
+	)
+	if got := leave.DecisionSummary(requests); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,152 @@
+package leave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// MaxCalendarDays is the longest date range the team calendar returns at once
+const MaxCalendarDays = 92
+
+// CalendarGrouping is how the team calendar groups absences
+type CalendarGrouping string
+
+const (
+	GroupByEmployee   CalendarGrouping = "EMPLOYEE"
+	GroupByTeam       CalendarGrouping = "TEAM"
+	GroupByDepartment CalendarGrouping = "DEPARTMENT"
+)
+
+// CalendarQuery represents the request for the team calendar: absences between From and To,
+// optionally only those of one team or department
+type CalendarQuery struct {
+	From       string           `json:"from"` // format: YYYY-MM-DD
+	To         string           `json:"to"`   // format: YYYY-MM-DD
+	GroupBy    CalendarGrouping `json:"group_by"`
+	Team       string           `json:"team"`
+	Department string           `json:"department"`
+}
+
+// Validate validates the CalendarQuery. Grouping defaults to EMPLOYEE.
+func (q *CalendarQuery) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	from, fromErr := time.Parse("2006-01-02", q.From)
+	if fromErr != nil {
+		validationErr.AddField("from", "from is required (use YYYY-MM-DD)")
+	}
+	to, toErr := time.Parse("2006-01-02", q.To)
+	if toErr != nil {
+		validationErr.AddField("to", "to is required (use YYYY-MM-DD)")
+	}
+	if fromErr == nil && toErr == nil {
+		if to.Before(from) {
+			validationErr.AddField("to", "to must be on or after from")
+		} else if days := int(to.Sub(from).Hours()/24) + 1; days > MaxCalendarDays {
+			validationErr.AddField("to", fmt.Sprintf("the calendar covers at most %d days at once", MaxCalendarDays))
+		}
+	}
+
+	q.GroupBy = CalendarGrouping(strings.ToUpper(strings.TrimSpace(string(q.GroupBy))))
+	if q.GroupBy == "" {
+		q.GroupBy = GroupByEmployee
+	}
+	if q.GroupBy != GroupByEmployee && q.GroupBy != GroupByTeam && q.GroupBy != GroupByDepartment {
+		validationErr.AddField("group_by", "group_by must be EMPLOYEE, TEAM or DEPARTMENT")
+	}
+
+	q.Team = strings.TrimSpace(q.Team)
+	q.Department = strings.TrimSpace(q.Department)
+
+	return validationErr.Validate()
+}
+
+// Dates returns the date range of a validated query
+func (q *CalendarQuery) Dates() (time.Time, time.Time) {
+	from, _ := time.Parse("2006-01-02", q.From)
+	to, _ := time.Parse("2006-01-02", q.To)
+	return from, to
+}
+
+// CalendarAbsence is an approved or pending leave request shown on the team calendar
+type CalendarAbsence struct {
+	LeaveRequestID int         `json:"leave_request_id"`
+	EmployeeID     int         `json:"employee_id"`
+	EmployeeName   string      `json:"employee_name"`
+	Team           string      `json:"team"`
+	Department     string      `json:"department"`
+	LeaveType      LeaveType   `json:"leave_type"`
+	Status         LeaveStatus `json:"status"`
+	StartDate      time.Time   `json:"start_date"`
+	EndDate        time.Time   `json:"end_date"`
+	DayPart        DayPart     `json:"day_part"`
+	Hours          float64     `json:"hours,omitempty"`
+	DaysCount      float64     `json:"days_count"`
+	EventSequence  int         `json:"-"` // Sequence of the request's last calendar invite
+}
+
+// Covers reports whether the absence includes a date
+func (a *CalendarAbsence) Covers(date time.Time) bool {
+	return !date.Before(a.StartDate) && !date.After(a.EndDate)
+}
+
+// CalendarGroup is an employee, team or department and their absences, in start date order
+type CalendarGroup struct {
+	Name       string            `json:"name"`
+	EmployeeID int               `json:"employee_id,omitempty"` // Set when grouping by employee
+	Absences   []CalendarAbsence `json:"absences"`
+}
+
+// GroupAbsences groups calendar absences by employee, team or department, in name order.
+// Employees without a team or department are grouped as "Unassigned".
+func GroupAbsences(absences []CalendarAbsence, by CalendarGrouping) []CalendarGroup {
+	groups := []CalendarGroup{}
+	index := map[string]int{}
+
+	for _, a := range absences {
+		key, name := "", ""
+		switch by {
+		case GroupByTeam:
+			name = a.Team
+		case GroupByDepartment:
+			name = a.Department
+		default:
+			key, name = fmt.Sprint(a.EmployeeID), a.EmployeeName
+		}
+		if name == "" {
+			name = "Unassigned"
+		}
+		if key == "" {
+			key = strings.ToLower(name)
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			group := CalendarGroup{Name: name}
+			if by == GroupByEmployee {
+				group.EmployeeID = a.EmployeeID
+			}
+			groups = append(groups, group)
+		}
+		groups[i].Absences = append(groups[i].Absences, a)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		return groups[i].EmployeeID < groups[j].EmployeeID
+	})
+	for _, g := range groups {
+		sort.SliceStable(g.Absences, func(i, j int) bool {
+			return g.Absences[i].StartDate.Before(g.Absences[j].StartDate)
+		})
+	}
+	return groups
+}
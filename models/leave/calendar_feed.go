@@ -0,0 +1,103 @@
+package leave
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How far back and ahead a calendar feed reaches from today
+const (
+	FeedPastDays   = 365
+	FeedFutureDays = 730
+)
+
+// CalendarFeed is a link to a user's approved leave in iCalendar format, for calendar apps that
+// cannot send a JWT. Its token is signed and names a nonce, so a revoked or replaced feed stops
+// working even though its signature is still valid.
+type CalendarFeed struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Nonce     string     `json:"-"`
+	Token     string     `json:"token,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActive reports whether the feed has not been revoked
+func (f *CalendarFeed) IsActive() bool {
+	return f.RevokedAt == nil
+}
+
+// Matches reports whether a token's nonce is that of this feed and the feed is still active
+func (f *CalendarFeed) Matches(nonce string) bool {
+	return f.IsActive() && subtle.ConstantTimeCompare([]byte(f.Nonce), []byte(nonce)) == 1
+}
+
+// SignFeedToken builds the token of a calendar feed: "<feed id>.<nonce>.<signature>", where the
+// signature is the base64url HMAC-SHA256 of the id and nonce
+func SignFeedToken(secret string, feedID int, nonce string) string {
+	payload := fmt.Sprintf("%d.%s", feedID, nonce)
+	return payload + "." + feedSignature(secret, payload)
+}
+
+// ParseFeedToken checks a calendar feed token's signature and returns the feed ID and nonce it
+// names; ok is false for a malformed or forged token
+func ParseFeedToken(secret, token string) (feedID int, nonce string, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[1] == "" {
+		return 0, "", false
+	}
+	feedID, err := strconv.Atoi(parts[0])
+	if err != nil || feedID <= 0 {
+		return 0, "", false
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(feedSignature(secret, payload))) {
+		return 0, "", false
+	}
+	return feedID, parts[1], true
+}
+
+// feedSignature signs a feed token payload
+func feedSignature(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EventUID identifies a leave request's calendar event, so the invite, its updates and the feed
+// all describe the same event
+func EventUID(leaveRequestID int) string {
+	return fmt.Sprintf("leave-%d@employee-service", leaveRequestID)
+}
+
+// EventSummary titles an absence's calendar event, e.g. "Ann Lee – SICK (first half)". Events in
+// a user's own calendar leave out their name.
+func EventSummary(a CalendarAbsence, withName bool) string {
+	summary := string(a.LeaveType) + " leave"
+	if withName {
+		summary = a.EmployeeName + " – " + string(a.LeaveType)
+	}
+	if label := DayPartLabel(a.DayPart, a.Hours); label != "" {
+		summary += " (" + label + ")"
+	}
+	return summary
+}
+
+// EventDescription describes an absence in its calendar event
+func EventDescription(a CalendarAbsence) string {
+	description := fmt.Sprintf("%s leave, %s day(s) from %s to %s", a.LeaveType, FormatDays(a.DaysCount),
+		a.StartDate.Format("2006-01-02"), a.EndDate.Format("2006-01-02"))
+	if a.Team != "" {
+		description += "\nTeam: " + a.Team
+	}
+	return description
+}
@@ -0,0 +1,88 @@
+package leave_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"employee-service/models/leave"
+)
+
+// TestParseFeedToken tests that only tokens signed with the secret are accepted
+func TestParseFeedToken(t *testing.T) {
+	token := leave.SignFeedToken("secret", 42, "a1b2c3")
+	parts := strings.Split(token, ".")
+
+	cases := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"Signed token", token, true},
+		{"Other secret", leave.SignFeedToken("other", 42, "a1b2c3"), false},
+		{"Other feed", "43.a1b2c3." + parts[2], false},
+		{"Other nonce", "42.ffffff." + parts[2], false},
+		{"No signature", "42.a1b2c3", false},
+		{"Empty nonce", leave.SignFeedToken("secret", 42, ""), false},
+		{"Bad feed ID", leave.SignFeedToken("secret", 0, "a1b2c3"), false},
+		{"Empty", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			feedID, nonce, ok := leave.ParseFeedToken("secret", c.token)
+			if ok != c.ok {
+				t.Fatalf("Expected ok %v, got %v", c.ok, ok)
+			}
+			if ok && (feedID != 42 || nonce != "a1b2c3") {
+				t.Errorf("Expected feed 42 with nonce a1b2c3, got %d and %q", feedID, nonce)
+			}
+		})
+	}
+}
+
+// TestCalendarFeedMatches tests that revoked feeds and replaced nonces no longer match
+func TestCalendarFeedMatches(t *testing.T) {
+	revokedAt := time.Now()
+	cases := []struct {
+		name     string
+		feed     leave.CalendarFeed
+		nonce    string
+		expected bool
+	}{
+		{"Active feed", leave.CalendarFeed{Nonce: "a1b2c3"}, "a1b2c3", true},
+		{"Replaced nonce", leave.CalendarFeed{Nonce: "d4e5f6"}, "a1b2c3", false},
+		{"Revoked feed", leave.CalendarFeed{Nonce: "a1b2c3", RevokedAt: &revokedAt}, "a1b2c3", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.feed.Matches(c.nonce); got != c.expected {
+				t.Errorf("Expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestEventSummary tests calendar event titles for own and team leave
+func TestEventSummary(t *testing.T) {
+	cases := []struct {
+		name     string
+		absence  leave.CalendarAbsence
+		withName bool
+		expected string
+	}{
+		{"Own leave", leave.CalendarAbsence{EmployeeName: "Ann Lee", LeaveType: leave.TypeAnnual}, false, "ANNUAL leave"},
+		{"Team leave", leave.CalendarAbsence{EmployeeName: "Ann Lee", LeaveType: leave.TypeSick}, true, "Ann Lee – SICK"},
+		{"Half day", leave.CalendarAbsence{EmployeeName: "Ann Lee", LeaveType: leave.TypeSick, DayPart: leave.DayPartFirstHalf}, true, "Ann Lee – SICK (first half)"},
+		{"Hours", leave.CalendarAbsence{LeaveType: leave.TypeAnnual, DayPart: leave.DayPartHours, Hours: 2}, false, "ANNUAL leave (2 hours)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := leave.EventSummary(c.absence, c.withName); got != c.expected {
+				t.Errorf("Expected %q, got %q", c.expected, got)
+			}
+		})
+	}
+}
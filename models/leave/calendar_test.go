@@ -0,0 +1,93 @@
+package leave_test
+
+import (
+	"testing"
+
+	"employee-service/models/leave"
+)
+
+// TestCalendarQueryValidate tests validation of team calendar queries
+func TestCalendarQueryValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		query leave.CalendarQuery
+		field string
+	}{
+		{"One month", leave.CalendarQuery{From: "2026-03-01", To: "2026-03-31"}, ""},
+		{"Single day by team", leave.CalendarQuery{From: "2026-03-02", To: "2026-03-02", GroupBy: "team"}, ""},
+		{"Longest range", leave.CalendarQuery{From: "2026-01-01", To: "2026-04-02", GroupBy: leave.GroupByDepartment}, ""},
+		{"Too long", leave.CalendarQuery{From: "2026-01-01", To: "2026-04-03"}, "to"},
+		{"Reversed", leave.CalendarQuery{From: "2026-03-10", To: "2026-03-01"}, "to"},
+		{"No from", leave.CalendarQuery{To: "2026-03-01"}, "from"},
+		{"Bad to", leave.CalendarQuery{From: "2026-03-01", To: "03/10/2026"}, "to"},
+		{"Unknown grouping", leave.CalendarQuery{From: "2026-03-01", To: "2026-03-10", GroupBy: "LOCATION"}, "group_by"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.query.Validate(), c.field)
+		})
+	}
+
+	query := leave.CalendarQuery{From: "2026-03-01", To: "2026-03-31"}
+	if err := query.Validate(); err != nil || query.GroupBy != leave.GroupByEmployee {
+		t.Errorf("Expected grouping to default to EMPLOYEE, got %q (%v)", query.GroupBy, err)
+	}
+}
+
+// TestGroupAbsences tests grouping absences by employee, team and department
+func TestGroupAbsences(t *testing.T) {
+	absences := []leave.CalendarAbsence{
+		{LeaveRequestID: 1, EmployeeID: 2, EmployeeName: "Zoe Adams", Team: "QA", Department: "Engineering", StartDate: mustDate(t, "2026-03-09"), EndDate: mustDate(t, "2026-03-10")},
+		{LeaveRequestID: 2, EmployeeID: 1, EmployeeName: "Ann Lee", Team: "Platform", Department: "Engineering", StartDate: mustDate(t, "2026-03-02"), EndDate: mustDate(t, "2026-03-02")},
+		{LeaveRequestID: 3, EmployeeID: 2, EmployeeName: "Zoe Adams", Team: "QA", Department: "Engineering", StartDate: mustDate(t, "2026-03-03"), EndDate: mustDate(t, "2026-03-03")},
+		{LeaveRequestID: 4, EmployeeID: 3, EmployeeName: "Raj Patel", Department: "Sales", StartDate: mustDate(t, "2026-03-04"), EndDate: mustDate(t, "2026-03-05")},
+	}
+
+	cases := []struct {
+		name     string
+		by       leave.CalendarGrouping
+		expected map[string][]int // Group name to leave request IDs, in order
+		order    []string
+	}{
+		{"By employee", leave.GroupByEmployee, map[string][]int{"Ann Lee": {2}, "Raj Patel": {4}, "Zoe Adams": {3, 1}}, []string{"Ann Lee", "Raj Patel", "Zoe Adams"}},
+		{"By team", leave.GroupByTeam, map[string][]int{"Platform": {2}, "QA": {3, 1}, "Unassigned": {4}}, []string{"Platform", "QA", "Unassigned"}},
+		{"By department", leave.GroupByDepartment, map[string][]int{"Engineering": {2, 3, 1}, "Sales": {4}}, []string{"Engineering", "Sales"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			groups := leave.GroupAbsences(absences, c.by)
+			if len(groups) != len(c.order) {
+				t.Fatalf("Expected %d groups, got %d", len(c.order), len(groups))
+			}
+			for i, g := range groups {
+				if g.Name != c.order[i] {
+					t.Errorf("Expected group %d to be %s, got %s", i, c.order[i], g.Name)
+				}
+				ids := make([]int, len(g.Absences))
+				for j, a := range g.Absences {
+					ids[j] = a.LeaveRequestID
+				}
+				if !equalInts(ids, c.expected[g.Name]) {
+					t.Errorf("Expected %s to hold requests %v, got %v", g.Name, c.expected[g.Name], ids)
+				}
+				if (c.by == leave.GroupByEmployee) != (g.EmployeeID != 0) {
+					t.Errorf("Unexpected employee ID %d on group %s", g.EmployeeID, g.Name)
+				}
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package leave
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// CancellationStatus represents the status of a request to cancel approved leave
+type CancellationStatus string
+
+const (
+	CancellationPending  CancellationStatus = "PENDING"
+	CancellationApproved CancellationStatus = "APPROVED"
+	CancellationRejected CancellationStatus = "REJECTED"
+)
+
+// LeaveCancellation is a request to cancel approved leave, either all of it or, when the
+// employee returns early, the days from FromDate onwards. It needs an approver's decision
+// before the balance and salary are restored.
+type LeaveCancellation struct {
+	ID              int                `json:"id"`
+	LeaveRequestID  int                `json:"leave_request_id"`
+	EmployeeID      int                `json:"employee_id"`
+	RequestedBy     int                `json:"requested_by"` // User who asked for the cancellation
+	FromDate        time.Time          `json:"from_date"`    // First day no longer taken
+	Reason          string             `json:"reason"`
+	Status          CancellationStatus `json:"status"`
+	DaysRefunded    float64            `json:"days_refunded"`   // Set when approved
+	SalaryRefunded  float64            `json:"salary_refunded"` // Set when approved
+	DecidedBy       *int               `json:"decided_by"`
+	DecisionComment string             `json:"decision_comment"`
+	DecidedAt       *time.Time         `json:"decided_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+// IsFull reports whether the cancellation covers the whole leave request
+func (c *LeaveCancellation) IsFull(lr *LeaveRequest) bool {
+	return !c.FromDate.After(lr.StartDate)
+}
+
+// CancelApprovedLeaveRequest represents the request to cancel approved leave
+type CancelApprovedLeaveRequest struct {
+	FromDate string `json:"from_date"` // format: YYYY-MM-DD; empty cancels the whole leave
+	Reason   string `json:"reason"`
+}
+
+// Validate validates the CancelApprovedLeaveRequest
+func (r *CancelApprovedLeaveRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if strings.TrimSpace(r.Reason) == "" {
+		validationErr.AddField("reason", "reason is required")
+	}
+	if r.FromDate != "" {
+		if _, err := time.Parse("2006-01-02", r.FromDate); err != nil {
+			validationErr.AddField("from_date", "invalid from_date format (use YYYY-MM-DD)")
+		}
+	}
+
+	return validationErr.Validate()
+}
+
+// DecideCancellationRequest represents an approver's decision on a cancellation request
+type DecideCancellationRequest struct {
+	Comment string `json:"comment"`
+}
+
+// CancellationRefund returns the days of an approved leave request that are given back when
+// it is cancelled from a date: the days charged for the rest of the period, counted from the
+// skipped days stored at approval like the salary refund. Days before today have been taken and
+// cannot be refunded.
+func (lr *LeaveRequest) CancellationRefund(fromDate time.Time, today time.Time) (float64, error) {
+	if lr.Status != StatusApproved {
+		return 0, errors.NewValidationError().AddField("status", "only approved leave requests can be cancelled this way")
+	}
+
+	from := dateOnly(fromDate)
+	if from.Before(dateOnly(lr.StartDate)) {
+		from = dateOnly(lr.StartDate)
+	}
+	if from.After(dateOnly(lr.EndDate)) {
+		return 0, errors.NewValidationError().AddField("from_date", "from_date must be within the leave period")
+	}
+	if from.Before(dateOnly(today)) {
+		return 0, errors.NewValidationError().AddField("from_date", "days that have already been taken cannot be cancelled")
+	}
+
+	refund := math.Min(LeaveDays(len(lr.ChargedDates(from)), lr.DayPart, lr.Hours), lr.DaysCount)
+	if refund <= 0 {
+		return 0, errors.NewValidationError().AddField("from_date", "no working days left to cancel")
+	}
+	return refund, nil
+}
+
+// Shorten ends an approved leave request the day before fromDate after refunding days, dropping
+// skipped days past the new end date
+func (lr *LeaveRequest) Shorten(fromDate time.Time, refundedDays, salaryRefund float64) {
+	lr.EndDate = dateOnly(fromDate).AddDate(0, 0, -1)
+	lr.DaysCount = RoundDays(lr.DaysCount - refundedDays)
+	lr.SalaryDeduction = math.Round((lr.SalaryDeduction-salaryRefund)*100) / 100
+
+	end := lr.EndDate.Format("2006-01-02")
+	kept := []SkippedDay{}
+	for _, day := range lr.SkippedDays {
+		if day.Date <= end {
+			kept = append(kept, day)
+		}
+	}
+	lr.SkippedDays = kept
+}
+
+// NewReversalEntry builds the ledger entry that returns the refunded days of a cancelled leave
+// request to its balance
+func NewReversalEntry(lr *LeaveRequest, days float64, fromDate time.Time, actorUserID int) *LeaveLedgerEntry {
+	leaveRequestID := lr.ID
+	return &LeaveLedgerEntry{
+		EmployeeID:     lr.EmployeeID,
+		LeaveType:      lr.LeaveType,
+		EntryType:      LedgerReversal,
+		Days:           days,
+		LeaveRequestID: &leaveRequestID,
+		ActorUserID:    &actorUserID,
+		Reference:      fmt.Sprintf("leave_request:%d", lr.ID),
+		EffectiveDate:  dateOnly(fromDate),
+		Description: fmt.Sprintf("Leave from %s to %s cancelled",
+			dateOnly(fromDate).Format("2006-01-02"), lr.EndDate.Format("2006-01-02")),
+	}
+}
@@ -0,0 +1,120 @@
+package leave_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/holiday"
+	"employee-service/models/leave"
+)
+
+// TestCancellationRefund tests which days of approved leave are refunded when it is cancelled
+func TestCancellationRefund(t *testing.T) {
+	// Monday 2026-03-09 to Friday 2026-03-20 with a holiday: nine working days. The days skipped
+	// at approval are stored, so the holiday still counts if the calendar changes afterwards.
+	holidays := []holiday.Holiday{{Date: mustDate(t, "2026-03-19"), Name: "Festival"}}
+	_, skipped := leave.CalculateDays(mustDate(t, "2026-03-09"), mustDate(t, "2026-03-20"), holidays)
+	approved := leave.LeaveRequest{
+		ID:          1,
+		Status:      leave.StatusApproved,
+		StartDate:   mustDate(t, "2026-03-09"),
+		EndDate:     mustDate(t, "2026-03-20"),
+		DaysCount:   9,
+		DayPart:     leave.DayPartFull,
+		SkippedDays: skipped,
+	}
+	halfDays := approved
+	halfDays.DayPart = leave.DayPartFirstHalf
+	halfDays.DaysCount = 4.5
+	pending := approved
+	pending.Status = leave.StatusPending
+
+	cases := []struct {
+		name     string
+		request  leave.LeaveRequest
+		from     string
+		today    string
+		expected float64
+		field    string // Field expected to fail; empty when the cancellation is allowed
+	}{
+		{"Whole future leave", approved, "2026-03-09", "2026-03-02", 9, ""},
+		{"From before the start", approved, "2026-03-01", "2026-03-01", 9, ""},
+		{"Returning early", approved, "2026-03-16", "2026-03-16", 4, ""},
+		{"Half days", halfDays, "2026-03-16", "2026-03-13", 2, ""},
+		{"Only a weekend left", approved, "2026-03-21", "2026-03-02", 0, "from_date"},
+		{"Days already taken", approved, "2026-03-10", "2026-03-12", 0, "from_date"},
+		{"Whole leave already started", approved, "2026-03-09", "2026-03-10", 0, "from_date"},
+		{"Not approved", pending, "2026-03-09", "2026-03-02", 0, "status"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			refund, err := c.request.CancellationRefund(mustDate(t, c.from), mustDate(t, c.today))
+			assertValidationField(t, err, c.field)
+			if refund != c.expected {
+				t.Errorf("Expected %v days refunded, got %v", c.expected, refund)
+			}
+		})
+	}
+}
+
+// TestShortenLeaveRequest tests ending approved leave early after a partial cancellation
+func TestShortenLeaveRequest(t *testing.T) {
+	lr := leave.LeaveRequest{
+		ID:              1,
+		EmployeeID:      3,
+		LeaveType:       leave.TypeAnnual,
+		StartDate:       mustDate(t, "2026-03-09"),
+		EndDate:         mustDate(t, "2026-03-20"),
+		DaysCount:       10,
+		SalaryDeduction: 5000,
+		SkippedDays: []leave.SkippedDay{
+			{Date: "2026-03-14", Reason: "weekend"},
+			{Date: "2026-03-15", Reason: "weekend"},
+			{Date: "2026-03-19", Reason: "holiday: Festival"},
+		},
+	}
+
+	from := mustDate(t, "2026-03-16")
+	entry := leave.NewReversalEntry(&lr, 4, from, 7)
+	if entry.EntryType != leave.LedgerReversal || entry.Days != 4 || *entry.LeaveRequestID != 1 || *entry.ActorUserID != 7 {
+		t.Errorf("Unexpected reversal entry: %+v", entry)
+	}
+
+	cancellation := leave.LeaveCancellation{FromDate: from}
+	if cancellation.IsFull(&lr) {
+		t.Error("Expected a cancellation after the start date to be partial")
+	}
+
+	lr.Shorten(from, 4, 2000)
+
+	if !lr.EndDate.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the leave to end on 2026-03-15, got %s", lr.EndDate.Format("2006-01-02"))
+	}
+	if lr.DaysCount != 6 || lr.SalaryDeduction != 3000 {
+		t.Errorf("Expected 6 days and a 3000 deduction, got %v days and %v", lr.DaysCount, lr.SalaryDeduction)
+	}
+	if len(lr.SkippedDays) != 2 {
+		t.Errorf("Expected the skipped days after the new end date to be dropped, got %v", lr.SkippedDays)
+	}
+}
+
+// TestCancelApprovedLeaveRequestValidate tests validation of cancellation requests
+func TestCancelApprovedLeaveRequestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		request leave.CancelApprovedLeaveRequest
+		field   string
+	}{
+		{"Whole leave", leave.CancelApprovedLeaveRequest{Reason: "plans changed"}, ""},
+		{"From a date", leave.CancelApprovedLeaveRequest{FromDate: "2026-03-16", Reason: "back early"}, ""},
+		{"Missing reason", leave.CancelApprovedLeaveRequest{FromDate: "2026-03-16"}, "reason"},
+		{"Invalid date", leave.CancelApprovedLeaveRequest{FromDate: "16/03/2026", Reason: "back early"}, "from_date"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.request.Validate(), c.field)
+		})
+	}
+}
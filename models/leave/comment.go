@@ -0,0 +1,47 @@
+package leave
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"employee-service/errors"
+)
+
+// MaxCommentLength is the longest comment accepted, in characters
+const MaxCommentLength = 2000
+
+// LeaveComment is a message in the thread between an employee and the approvers of their leave
+// request
+type LeaveComment struct {
+	ID             int       `json:"id"`
+	LeaveRequestID int       `json:"leave_request_id"`
+	AuthorUserID   *int      `json:"author_user_id"` // nil once the author's account is deleted
+	AuthorName     string    `json:"author_name"`
+	Body           string    `json:"body"`
+	RequestsInfo   bool      `json:"requests_info"` // The comment asked the employee for more information
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AddCommentRequest represents the request to comment on a leave request. Approvers can set
+// RequestInfo to mark the request as needing more information, which pauses approval reminders
+// until the employee replies.
+type AddCommentRequest struct {
+	Body        string `json:"body"`
+	RequestInfo bool   `json:"request_info"`
+}
+
+// Validate validates the AddCommentRequest
+func (r *AddCommentRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" {
+		validationErr.AddField("body", "body is required")
+	} else if utf8.RuneCountInString(r.Body) > MaxCommentLength {
+		validationErr.AddField("body", fmt.Sprintf("body must be at most %d characters", MaxCommentLength))
+	}
+
+	return validationErr.Validate()
+}
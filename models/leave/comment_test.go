@@ -0,0 +1,35 @@
+package leave_test
+
+import (
+	"strings"
+	"testing"
+
+	"employee-service/models/leave"
+)
+
+// TestAddCommentRequestValidate tests comment validation
+func TestAddCommentRequestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"Valid", "Can you attach the doctor's note?", ""},
+		{"Empty", "", "body"},
+		{"Only whitespace", " \n\t ", "body"},
+		{"At the length limit", strings.Repeat("é", leave.MaxCommentLength), ""},
+		{"Too long", strings.Repeat("a", leave.MaxCommentLength+1), "body"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := leave.AddCommentRequest{Body: c.body}
+			assertValidationField(t, req.Validate(), c.field)
+		})
+	}
+
+	req := leave.AddCommentRequest{Body: "  Sure, attached.  "}
+	if err := req.Validate(); err != nil || req.Body != "Sure, attached." {
+		t.Errorf("Expected the body to be trimmed, got %q (%v)", req.Body, err)
+	}
+}
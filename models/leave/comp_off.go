@@ -0,0 +1,220 @@
+package leave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/holiday"
+)
+
+// CompOffStatus represents the status of a comp-off credit request
+type CompOffStatus string
+
+const (
+	CompOffPending  CompOffStatus = "PENDING"
+	CompOffApproved CompOffStatus = "APPROVED"
+	CompOffRejected CompOffStatus = "REJECTED"
+)
+
+// CompOffPolicy configures compensatory off earned by working on weekends and holidays
+type CompOffPolicy struct {
+	ExpiryDays      int // Days after the date worked that unused comp-off lapses
+	ClaimWindowDays int // Days after the date worked that a credit can still be requested
+}
+
+// DefaultCompOffPolicy lets comp-off be claimed within a month and used within three
+var DefaultCompOffPolicy = CompOffPolicy{ExpiryDays: 90, ClaimWindowDays: 30}
+
+// Validate checks that credits can be claimed before they expire
+func (p CompOffPolicy) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if p.ExpiryDays <= 0 {
+		validationErr.AddField("expiry_days", "expiry_days must be positive")
+	}
+	if p.ClaimWindowDays <= 0 {
+		validationErr.AddField("claim_window_days", "claim_window_days must be positive")
+	} else if p.ExpiryDays > 0 && p.ClaimWindowDays >= p.ExpiryDays {
+		validationErr.AddField("claim_window_days", "claim_window_days must be shorter than expiry_days")
+	}
+
+	return validationErr.Validate()
+}
+
+// ExpiresOn returns the day comp-off earned on a date lapses; it can be used until the day before
+func (p CompOffPolicy) ExpiresOn(workedDate time.Time) time.Time {
+	return dateOnly(workedDate).AddDate(0, 0, p.ExpiryDays)
+}
+
+// CheckWorkedDate checks that comp-off can be claimed for a date: a weekend day or a holiday of
+// the employee's location, in the past, within the claim window and not yet expired
+func (p CompOffPolicy) CheckWorkedDate(workedDate time.Time, holidays []holiday.Holiday, today time.Time) error {
+	validationErr := errors.NewValidationError()
+	worked := dateOnly(workedDate)
+	today = dateOnly(today)
+
+	if worked.After(today) {
+		return validationErr.AddField("worked_date", "comp-off can only be claimed for days already worked")
+	}
+	if today.After(worked.AddDate(0, 0, p.ClaimWindowDays)) {
+		return validationErr.AddField("worked_date", fmt.Sprintf("comp-off must be claimed within %d days of the date worked", p.ClaimWindowDays))
+	}
+	if !today.Before(p.ExpiresOn(worked)) {
+		return validationErr.AddField("worked_date", "comp-off for this date has already expired")
+	}
+
+	if _, skipped := CalculateDays(worked, worked, holidays); len(skipped) == 0 {
+		return validationErr.AddField("worked_date", "comp-off is only earned by working on a weekend or holiday")
+	}
+
+	return nil
+}
+
+// CompOffCredit is a request to be credited comp-off for working on a weekend or holiday. Once
+// approved its days are added to the COMP_OFF balance until ExpiresOn, when whatever is left of
+// them lapses.
+type CompOffCredit struct {
+	ID              int           `json:"id"`
+	EmployeeID      int           `json:"employee_id"`
+	EmployeeName    string        `json:"employee_name,omitempty"`
+	RequestedBy     int           `json:"requested_by"` // User who asked for the credit
+	WorkedDate      time.Time     `json:"worked_date"`
+	DayPart         DayPart       `json:"day_part"` // FULL, FIRST_HALF or SECOND_HALF
+	Days            float64       `json:"days"`
+	Reason          string        `json:"reason"`
+	Status          CompOffStatus `json:"status"`
+	DecidedBy       *int          `json:"decided_by"`
+	DecisionComment string        `json:"decision_comment"`
+	DecidedAt       *time.Time    `json:"decided_at"`
+	ExpiresOn       *time.Time    `json:"expires_on"`   // Set when approved
+	DaysExpired     float64       `json:"days_expired"` // Unused days that lapsed
+	ExpiredAt       *time.Time    `json:"expired_at"`   // When the expiry job processed the credit
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// IsDue reports whether an approved credit has reached its expiry date and not been expired yet
+func (c *CompOffCredit) IsDue(today time.Time) bool {
+	return c.Status == CompOffApproved && c.ExpiredAt == nil && c.ExpiresOn != nil &&
+		!dateOnly(today).Before(dateOnly(*c.ExpiresOn))
+}
+
+// RequestCompOffRequest represents the request to be credited comp-off for a day worked
+type RequestCompOffRequest struct {
+	WorkedDate string  `json:"worked_date"` // format: YYYY-MM-DD
+	DayPart    DayPart `json:"day_part"`    // FULL (default), FIRST_HALF or SECOND_HALF
+	Reason     string  `json:"reason"`
+}
+
+// Validate validates the RequestCompOffRequest, defaulting to a full day
+func (r *RequestCompOffRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.WorkedDate == "" {
+		validationErr.AddField("worked_date", "worked_date is required")
+	} else if _, err := time.Parse("2006-01-02", r.WorkedDate); err != nil {
+		validationErr.AddField("worked_date", "invalid worked_date format (use YYYY-MM-DD)")
+	}
+
+	r.DayPart = DayPart(strings.ToUpper(string(r.DayPart)))
+	if r.DayPart == "" {
+		r.DayPart = DayPartFull
+	}
+	if r.DayPart != DayPartFull && r.DayPart != DayPartFirstHalf && r.DayPart != DayPartSecondHalf {
+		validationErr.AddField("day_part", "day_part must be FULL, FIRST_HALF or SECOND_HALF")
+	}
+
+	if strings.TrimSpace(r.Reason) == "" {
+		validationErr.AddField("reason", "reason is required")
+	}
+
+	return validationErr.Validate()
+}
+
+// DecideCompOffRequest represents an approver's decision on a comp-off credit request
+type DecideCompOffRequest struct {
+	Comment string `json:"comment"`
+}
+
+// CompOffExpiry is the part of a due credit that lapses
+type CompOffExpiry struct {
+	CreditID int
+	Days     float64
+}
+
+// ExpireCompOff works out how much of an employee's due comp-off credits lapses. Comp-off is
+// used oldest credit first, so the balance is held by the latest credits: only what the credits
+// that are still valid cannot account for lapses, taken from the due credits latest first. Every
+// due credit is returned, with 0 days when it was used up, so it is not checked again.
+func ExpireCompOff(credits []CompOffCredit, balance float64, today time.Time) []CompOffExpiry {
+	due := []CompOffCredit{}
+	valid := 0.0
+	for _, c := range credits {
+		if c.Status != CompOffApproved || c.ExpiredAt != nil {
+			continue
+		}
+		if c.IsDue(today) {
+			due = append(due, c)
+		} else {
+			valid += c.Days
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].ExpiresOn.Before(*due[j].ExpiresOn) })
+
+	lapse := RoundDays(balance - valid)
+	expiries := make([]CompOffExpiry, len(due))
+	for i := len(due) - 1; i >= 0; i-- {
+		days := 0.0
+		if lapse > 0 {
+			days = due[i].Days
+			if lapse < days {
+				days = lapse
+			}
+			lapse = RoundDays(lapse - days)
+		}
+		expiries[i] = CompOffExpiry{CreditID: due[i].ID, Days: days}
+	}
+	return expiries
+}
+
+// NewCompOffCreditEntry builds the ledger entry that adds an approved comp-off credit to the balance
+func NewCompOffCreditEntry(c *CompOffCredit, actorUserID int) *LeaveLedgerEntry {
+	return &LeaveLedgerEntry{
+		EmployeeID:    c.EmployeeID,
+		LeaveType:     TypeCompOff,
+		EntryType:     LedgerCredit,
+		Days:          c.Days,
+		ActorUserID:   &actorUserID,
+		Reference:     fmt.Sprintf("comp_off:%d", c.ID),
+		EffectiveDate: dateOnly(c.WorkedDate),
+		Description:   fmt.Sprintf("Comp-off for working on %s", c.WorkedDate.Format("2006-01-02")),
+	}
+}
+
+// NewCompOffExpiryEntry builds the ledger entry that removes the lapsed days of a comp-off credit
+func NewCompOffExpiryEntry(c *CompOffCredit, days float64) *LeaveLedgerEntry {
+	var effectiveDate time.Time
+	if c.ExpiresOn != nil {
+		effectiveDate = dateOnly(*c.ExpiresOn)
+	}
+	return &LeaveLedgerEntry{
+		EmployeeID:    c.EmployeeID,
+		LeaveType:     TypeCompOff,
+		EntryType:     LedgerExpiry,
+		Days:          -days,
+		Reference:     fmt.Sprintf("comp_off:%d", c.ID),
+		EffectiveDate: effectiveDate,
+		Description:   fmt.Sprintf("Unused comp-off for %s expired", c.WorkedDate.Format("2006-01-02")),
+	}
+}
+
+// CompOffExpiryResult summarizes a run of the comp-off expiry job
+type CompOffExpiryResult struct {
+	AsOf               string  `json:"as_of"`
+	EmployeesProcessed int     `json:"employees_processed"`
+	CreditsExpired     int     `json:"credits_expired"`
+	DaysLapsed         float64 `json:"days_lapsed"`
+	Errors             int     `json:"errors"`
+}
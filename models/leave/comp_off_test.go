@@ -0,0 +1,140 @@
+package leave_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/holiday"
+	"employee-service/models/leave"
+)
+
+// TestRequestCompOffRequestValidate tests comp-off request validation
+func TestRequestCompOffRequestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   leave.RequestCompOffRequest
+		field string
+	}{
+		{"Full day", leave.RequestCompOffRequest{WorkedDate: "2026-03-07", Reason: "Release support"}, ""},
+		{"Half day", leave.RequestCompOffRequest{WorkedDate: "2026-03-07", DayPart: "first_half", Reason: "On call"}, ""},
+		{"No date", leave.RequestCompOffRequest{Reason: "On call"}, "worked_date"},
+		{"Bad date", leave.RequestCompOffRequest{WorkedDate: "07/03/2026", Reason: "On call"}, "worked_date"},
+		{"Hours", leave.RequestCompOffRequest{WorkedDate: "2026-03-07", DayPart: leave.DayPartHours, Reason: "On call"}, "day_part"},
+		{"No reason", leave.RequestCompOffRequest{WorkedDate: "2026-03-07", Reason: " "}, "reason"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.req.Validate(), c.field)
+		})
+	}
+
+	req := leave.RequestCompOffRequest{WorkedDate: "2026-03-07", Reason: "On call"}
+	if err := req.Validate(); err != nil || req.DayPart != leave.DayPartFull {
+		t.Errorf("Expected day_part to default to FULL, got %q (%v)", req.DayPart, err)
+	}
+}
+
+// TestCompOffPolicyCheckWorkedDate tests which days earn comp-off
+func TestCompOffPolicyCheckWorkedDate(t *testing.T) {
+	policy := leave.CompOffPolicy{ExpiryDays: 60, ClaimWindowDays: 30}
+	holidays := []holiday.Holiday{{Name: "Holi", Date: mustDate(t, "2026-03-04")}}
+	today := mustDate(t, "2026-03-10")
+
+	cases := []struct {
+		name   string
+		worked string
+		field  string
+	}{
+		{"Saturday", "2026-03-07", ""},
+		{"Sunday", "2026-03-08", ""},
+		{"Holiday", "2026-03-04", ""},
+		{"Working day", "2026-03-09", "worked_date"},
+		{"Future weekend", "2026-03-14", "worked_date"},
+		{"Last day of the claim window", "2026-02-08", ""},
+		{"Claimed too late", "2026-02-07", "worked_date"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, policy.CheckWorkedDate(mustDate(t, c.worked), holidays, today), c.field)
+		})
+	}
+
+	if expires := policy.ExpiresOn(mustDate(t, "2026-03-07")); !expires.Equal(mustDate(t, "2026-05-06")) {
+		t.Errorf("Expected comp-off for 2026-03-07 to expire on 2026-05-06, got %s", expires.Format("2006-01-02"))
+	}
+}
+
+// TestCompOffPolicyValidate tests comp-off policy settings
+func TestCompOffPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy leave.CompOffPolicy
+		field  string
+	}{
+		{"Default", leave.DefaultCompOffPolicy, ""},
+		{"No expiry", leave.CompOffPolicy{ExpiryDays: 0, ClaimWindowDays: 30}, "expiry_days"},
+		{"No claim window", leave.CompOffPolicy{ExpiryDays: 90, ClaimWindowDays: 0}, "claim_window_days"},
+		{"Claim window outlasts expiry", leave.CompOffPolicy{ExpiryDays: 30, ClaimWindowDays: 30}, "claim_window_days"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.policy.Validate(), c.field)
+		})
+	}
+}
+
+// TestExpireCompOff tests that only the unused part of due credits lapses, oldest used first
+func TestExpireCompOff(t *testing.T) {
+	today := mustDate(t, "2026-06-01")
+	credit := func(id int, days float64, expires string) leave.CompOffCredit {
+		expiresOn := mustDate(t, expires)
+		return leave.CompOffCredit{ID: id, Days: days, Status: leave.CompOffApproved, ExpiresOn: &expiresOn}
+	}
+	expiredAt := time.Now()
+	processed := credit(9, 1, "2026-04-01")
+	processed.ExpiredAt = &expiredAt
+	pending := credit(8, 1, "2026-04-01")
+	pending.Status = leave.CompOffPending
+
+	cases := []struct {
+		name     string
+		credits  []leave.CompOffCredit
+		balance  float64
+		expected map[int]float64 // Credit ID to days lapsed
+	}{
+		{"Unused credit lapses", []leave.CompOffCredit{credit(1, 1, "2026-06-01")}, 1, map[int]float64{1: 1}},
+		{"Used credit lapses nothing", []leave.CompOffCredit{credit(1, 1, "2026-05-20")}, 0, map[int]float64{1: 0}},
+		{"Not due yet", []leave.CompOffCredit{credit(1, 1, "2026-06-02")}, 1, map[int]float64{}},
+		{"Later credit holds the balance", []leave.CompOffCredit{
+			credit(1, 1, "2026-05-01"),
+			credit(2, 1, "2026-07-01"),
+		}, 1, map[int]float64{1: 0}},
+		{"Partly used", []leave.CompOffCredit{
+			credit(1, 1, "2026-05-01"),
+			credit(2, 0.5, "2026-07-01"),
+		}, 1, map[int]float64{1: 0.5}},
+		{"Latest due credit lapses first", []leave.CompOffCredit{
+			credit(2, 1, "2026-05-15"),
+			credit(1, 1, "2026-05-01"),
+		}, 1.5, map[int]float64{1: 0.5, 2: 1}},
+		{"Processed and pending credits are ignored", []leave.CompOffCredit{processed, pending, credit(1, 1, "2026-05-01")}, 1, map[int]float64{1: 1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expiries := leave.ExpireCompOff(c.credits, c.balance, today)
+			if len(expiries) != len(c.expected) {
+				t.Fatalf("Expected %d due credits, got %+v", len(c.expected), expiries)
+			}
+			for _, e := range expiries {
+				days, ok := c.expected[e.CreditID]
+				if !ok || days != e.Days {
+					t.Errorf("Expected credit %d to lapse %v days, got %v", e.CreditID, days, e.Days)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package leave
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// CoverageScope is the kind of group a coverage rule applies to
+type CoverageScope string
+
+const (
+	CoverageTeam       CoverageScope = "TEAM"
+	CoverageDepartment CoverageScope = "DEPARTMENT"
+)
+
+// CoverageAction is what happens to an approval that would break a coverage rule
+type CoverageAction string
+
+const (
+	CoverageWarn  CoverageAction = "WARN"  // Flag the request to approvers but allow the approval
+	CoverageBlock CoverageAction = "BLOCK" // Refuse the final approval
+)
+
+// CoverageRule limits how many members of a team or department may be out at once,
+// e.g. at most 2 of the QA team. Group names are matched case-insensitively.
+type CoverageRule struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	Scope     CoverageScope  `json:"scope"`
+	Group     string         `json:"group"` // Team or department name
+	MaxAbsent int            `json:"max_absent"`
+	Action    CoverageAction `json:"action"`
+	IsActive  bool           `json:"is_active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Applies reports whether the rule covers an employee of a team and department
+func (r *CoverageRule) Applies(team, department string) bool {
+	group := team
+	if r.Scope == CoverageDepartment {
+		group = department
+	}
+	return group != "" && strings.EqualFold(strings.TrimSpace(group), r.Group)
+}
+
+// Check reports whether an absence would take more of the rule's group out than it allows on any
+// working day, counting the other employees' absences. The conflict names the busiest day, the
+// earliest one on a tie. A partial day counts as an absence; weekends are never checked.
+func (r *CoverageRule) Check(absence CalendarAbsence, others []CalendarAbsence) *CoverageConflict {
+	if !r.Applies(absence.Team, absence.Department) {
+		return nil
+	}
+
+	var conflict *CoverageConflict
+	for date := absence.StartDate; !date.After(absence.EndDate); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+
+		out := map[int]string{}
+		for i := range others {
+			other := &others[i]
+			if other.EmployeeID == absence.EmployeeID || !other.Covers(date) || !r.Applies(other.Team, other.Department) {
+				continue
+			}
+			out[other.EmployeeID] = other.EmployeeName
+		}
+
+		absent := len(out) + 1
+		if absent <= r.MaxAbsent || (conflict != nil && absent <= conflict.Absent) {
+			continue
+		}
+
+		names := make([]string, 0, len(out))
+		for _, name := range out {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		conflict = &CoverageConflict{
+			RuleID:    r.ID,
+			RuleName:  r.Name,
+			Scope:     r.Scope,
+			Group:     r.Group,
+			MaxAbsent: r.MaxAbsent,
+			Blocking:  r.Action == CoverageBlock,
+			Date:      date.Format("2006-01-02"),
+			Absent:    absent,
+			AlsoOut:   names,
+		}
+	}
+	return conflict
+}
+
+// CheckCoverage checks an absence against every active coverage rule that applies to it
+func CheckCoverage(rules []CoverageRule, absence CalendarAbsence, others []CalendarAbsence) []CoverageConflict {
+	conflicts := []CoverageConflict{}
+	for i := range rules {
+		if !rules[i].IsActive {
+			continue
+		}
+		if conflict := rules[i].Check(absence, others); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+	return conflicts
+}
+
+// CoverageConflict reports a coverage rule that an absence would break
+type CoverageConflict struct {
+	RuleID    int           `json:"rule_id"`
+	RuleName  string        `json:"rule_name,omitempty"`
+	Scope     CoverageScope `json:"scope"`
+	Group     string        `json:"group"`
+	MaxAbsent int           `json:"max_absent"`
+	Blocking  bool          `json:"blocking"`
+	Date      string        `json:"date"`     // Busiest working day, format: YYYY-MM-DD
+	Absent    int           `json:"absent"`   // Members out that day, including the employee
+	AlsoOut   []string      `json:"also_out"` // The other members out that day
+}
+
+// Message describes the conflict for approvers
+func (c *CoverageConflict) Message() string {
+	return fmt.Sprintf("%d of %s %s would be out on %s (at most %d allowed; also out: %s)",
+		c.Absent, strings.ToLower(string(c.Scope)), c.Group, c.Date, c.MaxAbsent, strings.Join(c.AlsoOut, ", "))
+}
+
+// CoverageError returns the validation error that refuses an approval breaking blocking
+// coverage rules, or nil when every conflict is only a warning
+func CoverageError(conflicts []CoverageConflict) error {
+	var messages []string
+	for i := range conflicts {
+		if conflicts[i].Blocking {
+			messages = append(messages, conflicts[i].Message())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.NewValidationError().AddField("coverage", "approving would break coverage: "+strings.Join(messages, "; "))
+}
+
+// CoverageRuleRequest represents the request to create or replace a coverage rule
+type CoverageRuleRequest struct {
+	Name      string         `json:"name"`
+	Scope     CoverageScope  `json:"scope"`
+	Group     string         `json:"group"`
+	MaxAbsent int            `json:"max_absent"`
+	Action    CoverageAction `json:"action"`              // defaults to WARN
+	IsActive  *bool          `json:"is_active,omitempty"` // defaults to true
+}
+
+// Validate validates the CoverageRuleRequest and normalises its scope, group and action
+func (r *CoverageRuleRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	r.Name = strings.TrimSpace(r.Name)
+	r.Group = strings.TrimSpace(r.Group)
+	r.Scope = CoverageScope(strings.ToUpper(strings.TrimSpace(string(r.Scope))))
+	r.Action = CoverageAction(strings.ToUpper(strings.TrimSpace(string(r.Action))))
+	if r.Action == "" {
+		r.Action = CoverageWarn
+	}
+
+	if r.Scope != CoverageTeam && r.Scope != CoverageDepartment {
+		validationErr.AddField("scope", "scope must be TEAM or DEPARTMENT")
+	}
+	if r.Group == "" {
+		validationErr.AddField("group", "group is required")
+	}
+	if r.MaxAbsent < 1 {
+		validationErr.AddField("max_absent", "max_absent must be at least 1")
+	}
+	if r.Action != CoverageWarn && r.Action != CoverageBlock {
+		validationErr.AddField("action", "action must be WARN or BLOCK")
+	}
+
+	return validationErr.Validate()
+}
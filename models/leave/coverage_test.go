@@ -0,0 +1,142 @@
+package leave_test
+
+import (
+	"strings"
+	"testing"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// TestCoverageRuleCheck tests that an absence breaking a coverage rule names the busiest day
+func TestCoverageRuleCheck(t *testing.T) {
+	rule := leave.CoverageRule{ID: 1, Scope: leave.CoverageTeam, Group: "QA", MaxAbsent: 2, Action: leave.CoverageBlock, IsActive: true}
+
+	absent := func(employeeID int, name, team, from, to string) leave.CalendarAbsence {
+		return leave.CalendarAbsence{EmployeeID: employeeID, EmployeeName: name, Team: team, Department: "Engineering",
+			StartDate: mustDate(t, from), EndDate: mustDate(t, to)}
+	}
+	// Mon 2 March to Fri 6 March 2026
+	candidate := absent(1, "Ann Lee", "QA", "2026-03-02", "2026-03-06")
+
+	cases := []struct {
+		name    string
+		others  []leave.CalendarAbsence
+		date    string
+		alsoOut []string
+	}{
+		{"Nobody else out", nil, "", nil},
+		{"Within the limit", []leave.CalendarAbsence{absent(2, "Bo Chen", "QA", "2026-03-02", "2026-03-06")}, "", nil},
+		{"Over the limit", []leave.CalendarAbsence{
+			absent(2, "Bo Chen", "QA", "2026-03-02", "2026-03-03"),
+			absent(3, "Cy Diaz", "qa", "2026-03-03", "2026-03-04"),
+		}, "2026-03-03", []string{"Bo Chen", "Cy Diaz"}},
+		{"Busiest day wins", []leave.CalendarAbsence{
+			absent(2, "Bo Chen", "QA", "2026-03-02", "2026-03-06"),
+			absent(3, "Cy Diaz", "QA", "2026-03-02", "2026-03-06"),
+			absent(4, "Di Evans", "QA", "2026-03-05", "2026-03-05"),
+		}, "2026-03-05", []string{"Bo Chen", "Cy Diaz", "Di Evans"}},
+		{"Other teams don't count", []leave.CalendarAbsence{
+			absent(2, "Bo Chen", "QA", "2026-03-02", "2026-03-02"),
+			absent(3, "Cy Diaz", "Platform", "2026-03-02", "2026-03-02"),
+		}, "", nil},
+		{"Own other leave doesn't count", []leave.CalendarAbsence{
+			absent(2, "Bo Chen", "QA", "2026-03-02", "2026-03-02"),
+			absent(1, "Ann Lee", "QA", "2026-03-02", "2026-03-02"),
+		}, "", nil},
+		{"Weekends don't count", []leave.CalendarAbsence{
+			absent(2, "Bo Chen", "QA", "2026-03-07", "2026-03-08"),
+			absent(3, "Cy Diaz", "QA", "2026-03-07", "2026-03-08"),
+		}, "", nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conflict := rule.Check(candidate, c.others)
+			if c.date == "" {
+				if conflict != nil {
+					t.Errorf("Expected no conflict, got %+v", conflict)
+				}
+				return
+			}
+			if conflict == nil {
+				t.Fatalf("Expected a conflict on %s", c.date)
+			}
+			if conflict.Date != c.date || conflict.Absent != len(c.alsoOut)+1 || !conflict.Blocking {
+				t.Errorf("Expected a blocking conflict on %s with %d out, got %+v", c.date, len(c.alsoOut)+1, conflict)
+			}
+			if strings.Join(conflict.AlsoOut, ",") != strings.Join(c.alsoOut, ",") {
+				t.Errorf("Expected %v also out, got %v", c.alsoOut, conflict.AlsoOut)
+			}
+		})
+	}
+
+	// A rule for another group never applies
+	other := leave.CoverageRule{Scope: leave.CoverageDepartment, Group: "Sales", MaxAbsent: 1}
+	if conflict := other.Check(candidate, []leave.CalendarAbsence{absent(2, "Bo Chen", "QA", "2026-03-02", "2026-03-02")}); conflict != nil {
+		t.Errorf("Expected a Sales rule to ignore Engineering, got %+v", conflict)
+	}
+}
+
+// TestCheckCoverage tests that only active rules are checked and only blocking ones refuse approval
+func TestCheckCoverage(t *testing.T) {
+	candidate := leave.CalendarAbsence{EmployeeID: 1, EmployeeName: "Ann Lee", Team: "QA", Department: "Engineering",
+		StartDate: mustDate(t, "2026-03-02"), EndDate: mustDate(t, "2026-03-02")}
+	others := []leave.CalendarAbsence{{EmployeeID: 2, EmployeeName: "Bo Chen", Team: "QA", Department: "Engineering",
+		StartDate: mustDate(t, "2026-03-02"), EndDate: mustDate(t, "2026-03-02")}}
+
+	warn := leave.CoverageRule{ID: 1, Scope: leave.CoverageDepartment, Group: "engineering", MaxAbsent: 1, Action: leave.CoverageWarn, IsActive: true}
+	block := leave.CoverageRule{ID: 2, Scope: leave.CoverageTeam, Group: "QA", MaxAbsent: 1, Action: leave.CoverageBlock, IsActive: true}
+	inactive := block
+	inactive.IsActive = false
+
+	conflicts := leave.CheckCoverage([]leave.CoverageRule{warn, inactive}, candidate, others)
+	if len(conflicts) != 1 || conflicts[0].RuleID != 1 || conflicts[0].Blocking {
+		t.Fatalf("Expected only the warning, got %+v", conflicts)
+	}
+	if err := leave.CoverageError(conflicts); err != nil {
+		t.Errorf("Expected a warning not to refuse approval, got %v", err)
+	}
+
+	conflicts = leave.CheckCoverage([]leave.CoverageRule{warn, block}, candidate, others)
+	if len(conflicts) != 2 {
+		t.Fatalf("Expected both rules to be broken, got %+v", conflicts)
+	}
+	err := leave.CoverageError(conflicts)
+	validationErr, ok := err.(*errors.ValidationError)
+	if !ok {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	message := validationErr.Fields["coverage"]
+	if !strings.Contains(message, "2 of team QA would be out on 2026-03-02 (at most 1 allowed; also out: Bo Chen)") ||
+		strings.Contains(message, "department") {
+		t.Errorf("Expected only the blocking rule in the error, got %q", message)
+	}
+}
+
+// TestCoverageRuleRequestValidate tests coverage rule validation
+func TestCoverageRuleRequestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		req   leave.CoverageRuleRequest
+		field string
+	}{
+		{"Team rule", leave.CoverageRuleRequest{Name: "QA cover", Scope: leave.CoverageTeam, Group: "QA", MaxAbsent: 2, Action: leave.CoverageBlock}, ""},
+		{"Lower case department", leave.CoverageRuleRequest{Scope: "department", Group: "Support", MaxAbsent: 3, Action: "warn"}, ""},
+		{"Unknown scope", leave.CoverageRuleRequest{Scope: "LOCATION", Group: "Pune", MaxAbsent: 2}, "scope"},
+		{"No group", leave.CoverageRuleRequest{Scope: leave.CoverageTeam, Group: "  ", MaxAbsent: 2}, "group"},
+		{"Nobody may be out", leave.CoverageRuleRequest{Scope: leave.CoverageTeam, Group: "QA", MaxAbsent: 0}, "max_absent"},
+		{"Unknown action", leave.CoverageRuleRequest{Scope: leave.CoverageTeam, Group: "QA", MaxAbsent: 2, Action: "DENY"}, "action"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.req.Validate(), c.field)
+		})
+	}
+
+	req := leave.CoverageRuleRequest{Scope: "team", Group: " QA ", MaxAbsent: 2}
+	if err := req.Validate(); err != nil || req.Action != leave.CoverageWarn || req.Scope != leave.CoverageTeam || req.Group != "QA" {
+		t.Errorf("Expected the request to be normalised with a WARN default, got %+v (%v)", req, err)
+	}
+}
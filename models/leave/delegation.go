@@ -0,0 +1,82 @@
+package leave
+
+import (
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/user"
+)
+
+// DelegationSource records how an approval delegation was created
+type DelegationSource string
+
+const (
+	DelegationManual DelegationSource = "MANUAL"
+	DelegationLeave  DelegationSource = "LEAVE" // Created when the delegator's own leave was approved
+)
+
+// ApprovalDelegation hands a user's approval rights to another user for a date range
+type ApprovalDelegation struct {
+	ID              int              `json:"id"`
+	DelegatorUserID int              `json:"delegator_user_id"`
+	DelegateUserID  int              `json:"delegate_user_id"`
+	StartDate       time.Time        `json:"start_date"`
+	EndDate         time.Time        `json:"end_date"`
+	Reason          string           `json:"reason"`
+	Source          DelegationSource `json:"source"`
+	LeaveRequestID  *int             `json:"leave_request_id"` // Set for LEAVE delegations
+	RevokedAt       *time.Time       `json:"revoked_at"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+// IsActiveOn reports whether the delegation is in effect on a date
+func (d *ApprovalDelegation) IsActiveOn(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return d.RevokedAt == nil && !day.Before(d.StartDate) && !day.After(d.EndDate)
+}
+
+// CanReceiveDelegation reports whether users with the role may act as approval delegates.
+// Delegates exercise the delegator's full approval rights, so only approvers qualify.
+func CanReceiveDelegation(role string) bool {
+	return role == user.RoleAdmin || role == user.RoleManager
+}
+
+// CreateDelegationRequest represents the request to delegate approval rights
+type CreateDelegationRequest struct {
+	DelegateUserID int    `json:"delegate_user_id"`
+	StartDate      string `json:"start_date"` // format: YYYY-MM-DD
+	EndDate        string `json:"end_date"`   // format: YYYY-MM-DD
+	Reason         string `json:"reason"`
+}
+
+// Validate validates the CreateDelegationRequest
+func (r *CreateDelegationRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.DelegateUserID <= 0 {
+		validationErr.AddField("delegate_user_id", "delegate_user_id is required")
+	}
+
+	startDate, errStart := time.Parse("2006-01-02", r.StartDate)
+	if errStart != nil {
+		validationErr.AddField("start_date", "invalid start_date format (use YYYY-MM-DD)")
+	}
+
+	endDate, errEnd := time.Parse("2006-01-02", r.EndDate)
+	if errEnd != nil {
+		validationErr.AddField("end_date", "invalid end_date format (use YYYY-MM-DD)")
+	} else if endDate.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		validationErr.AddField("end_date", "end_date cannot be in the past")
+	}
+
+	if errStart == nil && errEnd == nil && startDate.After(endDate) {
+		validationErr.AddField("end_date", "end_date must be on or after start_date")
+	}
+
+	return validationErr.Validate()
+}
+
+// DefaultDelegateRequest sets the user who receives an approver's rights while their leave is approved
+type DefaultDelegateRequest struct {
+	DelegateUserID *int `json:"delegate_user_id"` // null removes the default delegate
+}
@@ -0,0 +1,56 @@
+package leave_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/leave"
+	"employee-service/models/user"
+)
+
+// TestDelegationIsActiveOn tests which dates an approval delegation covers
+func TestDelegationIsActiveOn(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC) }
+	revokedAt := day(3)
+
+	cases := []struct {
+		name       string
+		delegation leave.ApprovalDelegation
+		date       time.Time
+		expected   bool
+	}{
+		{"Before start", leave.ApprovalDelegation{StartDate: day(2), EndDate: day(4)}, day(1), false},
+		{"First day", leave.ApprovalDelegation{StartDate: day(2), EndDate: day(4)}, day(2), true},
+		{"Last day afternoon", leave.ApprovalDelegation{StartDate: day(2), EndDate: day(4)}, day(4).Add(15 * time.Hour), true},
+		{"After end", leave.ApprovalDelegation{StartDate: day(2), EndDate: day(4)}, day(5), false},
+		{"Revoked", leave.ApprovalDelegation{StartDate: day(2), EndDate: day(4), RevokedAt: &revokedAt}, day(3), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.delegation.IsActiveOn(c.date); got != c.expected {
+				t.Errorf("IsActiveOn(%s) = %v, expected %v", c.date.Format(time.RFC3339), got, c.expected)
+			}
+		})
+	}
+}
+
+// TestCanReceiveDelegation tests which roles may act as approval delegates
+func TestCanReceiveDelegation(t *testing.T) {
+	cases := []struct {
+		role     string
+		expected bool
+	}{
+		{user.RoleAdmin, true},
+		{user.RoleManager, true},
+		{user.RoleEmployee, false},
+		{user.RoleUser, false},
+		{"", false},
+	}
+
+	for _, c := range cases {
+		if got := leave.CanReceiveDelegation(c.role); got != c.expected {
+			t.Errorf("CanReceiveDelegation(%q): expected %v, got %v", c.role, c.expected, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package leave
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// DayPart describes which part of a day a leave request covers
+type DayPart string
+
+const (
+	DayPartFull       DayPart = "FULL"
+	DayPartFirstHalf  DayPart = "FIRST_HALF"
+	DayPartSecondHalf DayPart = "SECOND_HALF"
+	DayPartHours      DayPart = "HOURS"
+)
+
+// HoursPerDay is the length of a working day used to convert hourly leave into days
+const HoursPerDay = 8.0
+
+// IsValidDayPart checks if the day part is supported
+func IsValidDayPart(part DayPart) bool {
+	switch part {
+	case DayPartFull, DayPartFirstHalf, DayPartSecondHalf, DayPartHours:
+		return true
+	}
+	return false
+}
+
+// IsPartial reports whether the day part covers less than a full day
+func (p DayPart) IsPartial() bool {
+	return p == DayPartFirstHalf || p == DayPartSecondHalf || p == DayPartHours
+}
+
+// LeaveDays converts the working days in a leave period into the days charged to the balance
+func LeaveDays(workingDays int, part DayPart, hours float64) float64 {
+	switch part {
+	case DayPartFirstHalf, DayPartSecondHalf:
+		return float64(workingDays) * 0.5
+	case DayPartHours:
+		return RoundDays(float64(workingDays) * hours / HoursPerDay)
+	}
+	return float64(workingDays)
+}
+
+// DayPartLabel describes a partial day for notifications, e.g. "first half" or "2 hours".
+// It returns an empty string for full days.
+func DayPartLabel(part DayPart, hours float64) string {
+	switch part {
+	case DayPartFirstHalf:
+		return "first half"
+	case DayPartSecondHalf:
+		return "second half"
+	case DayPartHours:
+		return fmt.Sprintf("%s hours", FormatDays(hours))
+	}
+	return ""
+}
+
+// RoundDays rounds a number of days to the precision stored in the database.
+// Three decimals keep hourly leave exact, as it is taken in whole hours (1 hour = 0.125 days).
+func RoundDays(days float64) float64 {
+	return math.Round(days*1000) / 1000
+}
+
+// FormatDays formats a number of days without trailing zeros, e.g. "1.5" or "3"
+func FormatDays(days float64) string {
+	return strconv.FormatFloat(RoundDays(days), 'f', -1, 64)
+}
@@ -1,7 +1,10 @@
 package leave
 
 import (
+	"fmt"
+
 	"employee-service/errors"
+	"employee-service/models/holiday"
 	"time"
 )
 
@@ -26,6 +29,7 @@ const (
 	TypePaternity LeaveType = "PATERNITY"
 	TypeUnpaid    LeaveType = "UNPAID"
 	TypeCasual    LeaveType = "CASUAL"
+	TypeCompOff   LeaveType = "COMP_OFF" // Earned by working on weekends and holidays; see CompOffCredit
 )
 
 // LeaveRequest represents a leave request
@@ -36,12 +40,19 @@ type LeaveRequest struct {
 	StartDate    time.Time `json:"start_date"`
 	EndDate      time.Time `json:"end_date"`
 	Reason       string    `json:"reason"`
-	DaysCount    int       `json:"days_count"`
+	DaysCount    float64   `json:"days_count"`
+	DayPart      DayPart   `json:"day_part"`
+	Hours        float64   `json:"hours,omitempty"` // Only for hourly leave
 	Status       LeaveStatus `json:"status"`
 	Notes        *string   `json:"notes"`
 	ApprovedBy   *int      `json:"approved_by"`
 	ApprovalDate *time.Time `json:"approval_date"`
 	SalaryDeduction float64 `json:"salary_deduction"` // Amount deducted from salary for paid leave
+	SkippedDays  []SkippedDay `json:"skipped_days"` // Days in the range not charged to the balance
+	Approvals    []LeaveApproval `json:"approvals,omitempty"` // Approval chain, in step order
+	Attachments  []LeaveAttachment `json:"attachments,omitempty"` // Supporting documents, in upload order
+	NeedsInfo    bool      `json:"needs_info"` // An approver asked for more information before deciding
+	CoverageConflicts []CoverageConflict `json:"coverage_conflicts,omitempty"` // Coverage rules broken by approving it, set on approval
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -55,15 +66,27 @@ type LeaveRequestDetail struct {
 	StartDate    time.Time `json:"start_date"`
 	EndDate      time.Time `json:"end_date"`
 	Reason       string    `json:"reason"`
-	DaysCount    int       `json:"days_count"`
+	DaysCount    float64   `json:"days_count"`
+	DayPart      DayPart   `json:"day_part"`
+	Hours        float64   `json:"hours,omitempty"`
 	Status       LeaveStatus `json:"status"`
 	Notes        *string   `json:"notes"`
 	ApprovedBy   *int      `json:"approved_by"`
 	ApprovalDate *time.Time `json:"approval_date"`
+	SkippedDays  []SkippedDay `json:"skipped_days"`
+	CurrentApproval *LeaveApproval `json:"current_approval,omitempty"` // Step awaiting a decision
+	NeedsInfo    bool      `json:"needs_info"` // The step awaiting a decision is paused for more information
+	CoverageConflicts []CoverageConflict `json:"coverage_conflicts,omitempty"` // Coverage rules approving a pending request would break
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// SkippedDay is a day inside a leave period that is not counted as leave
+type SkippedDay struct {
+	Date   string `json:"date"`   // format: YYYY-MM-DD
+	Reason string `json:"reason"` // e.g. "weekend" or "holiday: Diwali"
+}
+
 // ApplyLeaveRequest represents the request to apply for leave
 type ApplyLeaveRequest struct {
 	LeaveType LeaveType `json:"leave_type"`
@@ -71,6 +94,8 @@ type ApplyLeaveRequest struct {
 	EndDate   string    `json:"end_date"`   // format: YYYY-MM-DD
 	Reason    string    `json:"reason"`
 	Notes     string    `json:"notes"`
+	DayPart   DayPart   `json:"day_part"` // FULL (default), FIRST_HALF, SECOND_HALF or HOURS
+	Hours     float64   `json:"hours"`    // Required when day_part is HOURS
 }
 
 // ApproveLeaveRequest represents the request to approve a leave
@@ -88,26 +113,20 @@ type LeaveBalance struct {
 	ID         int       `json:"id"`
 	EmployeeID int       `json:"employee_id"`
 	LeaveType  LeaveType `json:"leave_type"`
-	Balance    int       `json:"balance"`
+	Balance    float64   `json:"balance"`
+	LeaveYear  int       `json:"leave_year"` // Calendar year the balance belongs to; advanced by the year-end rollover
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
-// DefaultLeaveBalances returns the default leave balances
-func DefaultLeaveBalances() map[LeaveType]int {
-	return map[LeaveType]int{
-		TypeAnnual:    10,
-		TypeSick:      15,
-		TypeCasual:    10,
-		TypeMaternity: 90,
-		TypePaternity: 7,
-		TypeUnpaid:    10,
-		TypePersonal:  10,
-	}
-}
-
 // Validate validates the ApplyLeaveRequest
 func (r *ApplyLeaveRequest) Validate() error {
+	return r.validate(false)
+}
+
+// validate validates the ApplyLeaveRequest, accepting a start date in the past when allowPast is
+// set, e.g. for leave HR records after the fact
+func (r *ApplyLeaveRequest) validate(allowPast bool) error {
 	validationErr := errors.NewValidationError()
 
 	if r.LeaveType == "" {
@@ -131,7 +150,7 @@ func (r *ApplyLeaveRequest) Validate() error {
 		startDate, err := time.Parse("2006-01-02", r.StartDate)
 		if err != nil {
 			validationErr.AddField("start_date", "invalid start_date format (use YYYY-MM-DD)")
-		} else if startDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		} else if !allowPast && startDate.Before(time.Now().Truncate(24*time.Hour)) {
 			validationErr.AddField("start_date", "start_date cannot be in the past")
 		}
 	}
@@ -152,78 +171,81 @@ func (r *ApplyLeaveRequest) Validate() error {
 		}
 	}
 
+	// Half days and hourly leave cover a single date
+	if !IsValidDayPart(r.GetDayPart()) {
+		validationErr.AddField("day_part", "day_part must be FULL, FIRST_HALF, SECOND_HALF or HOURS")
+	} else if r.DayPart.IsPartial() && r.StartDate != r.EndDate {
+		validationErr.AddField("day_part", "half-day and hourly leave must start and end on the same date")
+	}
+	if r.DayPart == DayPartHours {
+		if r.Hours <= 0 || r.Hours >= HoursPerDay {
+			validationErr.AddField("hours", fmt.Sprintf("hours must be greater than 0 and less than %g", HoursPerDay))
+		} else if r.Hours != float64(int(r.Hours)) {
+			validationErr.AddField("hours", "hours must be whole hours")
+		}
+	} else if r.Hours != 0 {
+		validationErr.AddField("hours", "hours can only be set when day_part is HOURS")
+	}
+
 	return validationErr.Validate()
 }
 
-// CalculateDays calculates working days between two dates (excludes weekends)
-func CalculateDays(startDate, endDate time.Time) int {
+// GetDayPart returns the requested day part, defaulting to a full day
+func (r *ApplyLeaveRequest) GetDayPart() DayPart {
+	if r.DayPart == "" {
+		return DayPartFull
+	}
+	return r.DayPart
+}
+
+// CalculateDays calculates working days between two dates, excluding weekends
+// and the given holidays. It also returns the days that were skipped and why.
+func CalculateDays(startDate, endDate time.Time, holidays []holiday.Holiday) (int, []SkippedDay) {
+	holidayNames := make(map[string]string, len(holidays))
+	for _, h := range holidays {
+		holidayNames[h.Date.Format("2006-01-02")] = h.Name
+	}
+
 	days := 0
+	skipped := []SkippedDay{}
 	current := startDate
 
 	for !current.After(endDate) {
+		date := current.Format("2006-01-02")
 		// 0 = Sunday, 6 = Saturday
-		if current.Weekday() != time.Saturday && current.Weekday() != time.Sunday {
+		if current.Weekday() == time.Saturday || current.Weekday() == time.Sunday {
+			skipped = append(skipped, SkippedDay{Date: date, Reason: "weekend"})
+		} else if name, ok := holidayNames[date]; ok {
+			skipped = append(skipped, SkippedDay{Date: date, Reason: "holiday: " + name})
+		} else {
 			days++
 		}
 		current = current.AddDate(0, 0, 1)
 	}
 
-	return days
-}
-
-// IsPaidLeave checks if the leave type is paid leave (deducts from salary)
-// Only ANNUAL and SICK leaves have salary deduction
-// MATERNITY, PATERNITY, UNPAID, PERSONAL, and CASUAL leaves do NOT deduct salary
-func IsPaidLeave(leaveType LeaveType) bool {
-	switch leaveType {
-	case TypeAnnual, TypeSick:
-		return true
-	default:
-		return false
-	}
+	return days, skipped
 }
 
-// GetLeaveLimit returns the maximum days allowed for each leave type
-func GetLeaveLimit(leaveType LeaveType) int {
-	switch leaveType {
-	case TypeAnnual:
-		return 20 // Default annual leave limit
-	case TypeSick:
-		return 10 // Sick leave limit
-	case TypePersonal:
-		return 10 // Personal leave limit
-	case TypeMaternity:
-		return 90 // Maternity leave limit
-	case TypePaternity:
-		return 7 // Paternity leave limit
-	case TypeUnpaid:
-		return 10 // Unpaid leave limit
-	case TypeCasual:
-		return 5 // Casual leave limit
-	default:
-		return 0
+// ChargedDates returns the days of a leave request from fromDate to its end that were charged
+// when it was approved: every day in the period except the stored skipped days. Refunds count
+// these rather than the current holiday calendar, so a holiday added or removed after approval
+// changes neither the days nor the pay given back.
+func (lr *LeaveRequest) ChargedDates(fromDate time.Time) []time.Time {
+	skipped := make(map[string]bool, len(lr.SkippedDays))
+	for _, d := range lr.SkippedDays {
+		skipped[d.Date] = true
 	}
-}
 
-// IsManagedLeave checks if the leave type has balance limits
-func IsManagedLeave(leaveType LeaveType) bool {
-	switch leaveType {
-	case TypeAnnual, TypeSick, TypeCasual, TypeMaternity, TypePaternity, TypeUnpaid, TypePersonal:
-		return true
-	default:
-		return false
+	start := dateOnly(lr.StartDate)
+	if from := dateOnly(fromDate); from.After(start) {
+		start = from
 	}
-}
 
-// CanApplyMaternityLeave checks if employee is eligible for maternity leave
-// Returns (canApply, errorMessage)
-func CanApplyMaternityLeave(gender string, isMarried bool) (bool, string) {
-	if gender != "Female" && gender != "female" {
-		return false, "maternity leave can only be applied by female employees"
-	}
-	if !isMarried {
-		return false, "maternity leave can only be applied by married female employees"
+	dates := []time.Time{}
+	for date := start; !date.After(dateOnly(lr.EndDate)); date = date.AddDate(0, 0, 1) {
+		if !skipped[date.Format("2006-01-02")] {
+			dates = append(dates, date)
+		}
 	}
-	return true, ""
+	return dates
 }
-
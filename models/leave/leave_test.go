@@ -0,0 +1,168 @@
+package leave_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"employee-service/models/holiday"
+	"employee-service/models/leave"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatalf("invalid test date %s: %v", value, err)
+	}
+	return d
+}
+
+// TestCalculateDays tests working day calculation with weekends and holidays
+func TestCalculateDays(t *testing.T) {
+	t.Run("Skips weekends", func(t *testing.T) {
+		// Friday 2025-10-17 to Monday 2025-10-20
+		days, skipped := leave.CalculateDays(mustDate(t, "2025-10-17"), mustDate(t, "2025-10-20"), nil)
+
+		if days != 2 {
+			t.Errorf("Expected 2 working days, got %d", days)
+		}
+		if len(skipped) != 2 {
+			t.Fatalf("Expected 2 skipped days, got %d", len(skipped))
+		}
+		if skipped[0].Date != "2025-10-18" || skipped[0].Reason != "weekend" {
+			t.Errorf("Unexpected skipped day: %+v", skipped[0])
+		}
+	})
+
+	t.Run("Skips holidays", func(t *testing.T) {
+		holidays := []holiday.Holiday{
+			{Name: "Diwali", Date: mustDate(t, "2025-10-21")},
+		}
+
+		// Monday 2025-10-20 to Wednesday 2025-10-22
+		days, skipped := leave.CalculateDays(mustDate(t, "2025-10-20"), mustDate(t, "2025-10-22"), holidays)
+
+		if days != 2 {
+			t.Errorf("Expected 2 working days, got %d", days)
+		}
+		if len(skipped) != 1 || skipped[0].Reason != "holiday: Diwali" {
+			t.Errorf("Expected Diwali to be skipped, got %+v", skipped)
+		}
+	})
+
+	t.Run("Holiday on a weekend is reported once", func(t *testing.T) {
+		holidays := []holiday.Holiday{
+			{Name: "Founders Day", Date: mustDate(t, "2025-10-18")},
+		}
+
+		days, skipped := leave.CalculateDays(mustDate(t, "2025-10-18"), mustDate(t, "2025-10-18"), holidays)
+
+		if days != 0 {
+			t.Errorf("Expected 0 working days, got %d", days)
+		}
+		if len(skipped) != 1 || skipped[0].Reason != "weekend" {
+			t.Errorf("Expected a single weekend entry, got %+v", skipped)
+		}
+	})
+}
+
+// TestLeaveDays tests conversion of working days into charged days for partial days
+func TestLeaveDays(t *testing.T) {
+	cases := []struct {
+		name     string
+		working  int
+		part     leave.DayPart
+		hours    float64
+		expected float64
+	}{
+		{"Full days", 3, leave.DayPartFull, 0, 3},
+		{"First half", 1, leave.DayPartFirstHalf, 0, 0.5},
+		{"Second half on a holiday", 0, leave.DayPartSecondHalf, 0, 0},
+		{"Hourly", 1, leave.DayPartHours, 2, 0.25},
+		{"One hour", 1, leave.DayPartHours, 1, 0.125},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := leave.LeaveDays(c.working, c.part, c.hours); got != c.expected {
+				t.Errorf("Expected %v days, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestApplyLeaveRequestDayPart tests validation of half-day and hourly requests
+func TestApplyLeaveRequestDayPart(t *testing.T) {
+	date := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	nextDate := time.Now().AddDate(0, 0, 8).Format("2006-01-02")
+
+	valid := &leave.ApplyLeaveRequest{LeaveType: leave.TypeCasual, StartDate: date, EndDate: date, Reason: "appointment", DayPart: leave.DayPartFirstHalf}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected half day to be valid, got %v", err)
+	}
+
+	multiDay := &leave.ApplyLeaveRequest{LeaveType: leave.TypeCasual, StartDate: date, EndDate: nextDate, Reason: "appointment", DayPart: leave.DayPartSecondHalf}
+	if err := multiDay.Validate(); err == nil {
+		t.Error("Expected half day spanning two dates to be rejected")
+	}
+
+	noHours := &leave.ApplyLeaveRequest{LeaveType: leave.TypeCasual, StartDate: date, EndDate: date, Reason: "appointment", DayPart: leave.DayPartHours}
+	if err := noHours.Validate(); err == nil {
+		t.Error("Expected hourly leave without hours to be rejected")
+	}
+
+	halfHour := &leave.ApplyLeaveRequest{LeaveType: leave.TypeCasual, StartDate: date, EndDate: date, Reason: "appointment", DayPart: leave.DayPartHours, Hours: 1.5}
+	if err := halfHour.Validate(); err == nil {
+		t.Error("Expected hourly leave in part hours to be rejected")
+	}
+}
+
+// TestFindOverlaps tests detection of overlapping leave requests
+func TestFindOverlaps(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+
+	existing := []leave.LeaveRequest{
+		{ID: 1, StartDate: day(2), EndDate: day(4), DayPart: leave.DayPartFull},
+		{ID: 2, StartDate: day(10), EndDate: day(10), DayPart: leave.DayPartFirstHalf},
+		{ID: 3, StartDate: day(12), EndDate: day(12), DayPart: leave.DayPartHours, Hours: 2},
+	}
+
+	cases := []struct {
+		name     string
+		request  leave.LeaveRequest
+		expected []int
+	}{
+		{"No overlap", leave.LeaveRequest{StartDate: day(5), EndDate: day(9), DayPart: leave.DayPartFull}, []int{}},
+		{"Shared last day", leave.LeaveRequest{StartDate: day(4), EndDate: day(10), DayPart: leave.DayPartFull}, []int{1, 2}},
+		{"Opposite half", leave.LeaveRequest{StartDate: day(10), EndDate: day(10), DayPart: leave.DayPartSecondHalf}, []int{}},
+		{"Same half", leave.LeaveRequest{StartDate: day(10), EndDate: day(10), DayPart: leave.DayPartFirstHalf}, []int{2}},
+		{"Half day over hourly leave", leave.LeaveRequest{StartDate: day(12), EndDate: day(12), DayPart: leave.DayPartFirstHalf}, []int{3}},
+		{"Ignores itself", leave.LeaveRequest{ID: 1, StartDate: day(2), EndDate: day(4), DayPart: leave.DayPartFull}, []int{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.request.FindOverlaps(existing)
+			if fmt.Sprint(got) != fmt.Sprint(c.expected) {
+				t.Errorf("Expected overlaps %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+// TestNewOverlapError tests that overlap errors carry the conflicting request IDs as a list
+func TestNewOverlapError(t *testing.T) {
+	err := leave.NewOverlapError([]int{4, 9})
+
+	if fmt.Sprint(err.ConflictingRequestIDs) != "[4 9]" {
+		t.Errorf("Expected conflicting IDs [4 9], got %v", err.ConflictingRequestIDs)
+	}
+	if _, ok := err.Fields()["dates"]; !ok {
+		t.Errorf("Expected the dates field to be reported, got %v", err.Fields())
+	}
+	if !strings.Contains(err.Error(), "4, 9") {
+		t.Errorf("Expected the message to name the conflicting requests, got %q", err.Error())
+	}
+}
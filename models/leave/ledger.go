@@ -0,0 +1,111 @@
+package leave
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// LedgerEntryType represents the kind of movement in the leave balance ledger
+type LedgerEntryType string
+
+const (
+	LedgerCredit     LedgerEntryType = "CREDIT"     // Days granted, e.g. by accrual
+	LedgerDebit      LedgerEntryType = "DEBIT"      // Days taken by an approved leave or encashed
+	LedgerAdjustment LedgerEntryType = "ADJUSTMENT" // Manual correction by an admin
+	LedgerReversal   LedgerEntryType = "REVERSAL"   // Days returned when a debited leave is undone
+	LedgerExpiry     LedgerEntryType = "EXPIRY"     // Days lapsed at year end or when comp-off expires
+)
+
+// LeaveLedgerEntry is an append-only movement of an employee's leave balance.
+// The balance for a leave type is the sum of Days over its entries.
+type LeaveLedgerEntry struct {
+	ID             int             `json:"id"`
+	EmployeeID     int             `json:"employee_id"`
+	LeaveType      LeaveType       `json:"leave_type"`
+	EntryType      LedgerEntryType `json:"entry_type"`
+	Days           float64         `json:"days"` // Positive for credits, negative for debits
+	LeaveRequestID *int            `json:"leave_request_id,omitempty"`
+	ActorUserID    *int            `json:"actor_user_id,omitempty"` // Admin or approver who caused the entry; nil for system jobs
+	Reference      string          `json:"reference,omitempty"`     // e.g. "accrual:2025-05", "rollover:2025"
+	Description    string          `json:"description"`
+	BalanceAfter   float64         `json:"balance_after"`  // Running balance, derived when the history is read
+	EffectiveDate  time.Time       `json:"effective_date"` // Day the movement counts towards, e.g. the first day of the leave
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AdjustLeaveBalanceRequest represents an admin adjustment of a leave balance
+type AdjustLeaveBalanceRequest struct {
+	LeaveType LeaveType `json:"leave_type"`
+	Days      float64   `json:"days"` // Positive to add days, negative to remove them
+	Reason    string    `json:"reason"`
+}
+
+// Validate validates the AdjustLeaveBalanceRequest
+func (r *AdjustLeaveBalanceRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.LeaveType == "" {
+		validationErr.AddField("leave_type", "leave_type is required")
+	}
+	if r.Days == 0 {
+		validationErr.AddField("days", "days must not be zero")
+	}
+	if strings.TrimSpace(r.Reason) == "" {
+		validationErr.AddField("reason", "reason is required")
+	}
+
+	return validationErr.Validate()
+}
+
+// SetRunningBalances fills in BalanceAfter for ledger entries in chronological order.
+// The last entry's BalanceAfter is the balance the ledger adds up to.
+func SetRunningBalances(entries []LeaveLedgerEntry) {
+	balance := 0.0
+	for i := range entries {
+		balance = RoundDays(balance + entries[i].Days)
+		entries[i].BalanceAfter = balance
+	}
+}
+
+// NewDebitEntry builds the ledger entry that charges an approved leave request to a balance.
+// It fails if the balance does not cover the request.
+func NewDebitEntry(lr *LeaveRequest, balance float64, actorUserID int) (*LeaveLedgerEntry, error) {
+	if balance < lr.DaysCount {
+		return nil, errors.NewValidationError().AddField("balance", "insufficient leave balance")
+	}
+
+	leaveRequestID := lr.ID
+	return &LeaveLedgerEntry{
+		EmployeeID:     lr.EmployeeID,
+		LeaveType:      lr.LeaveType,
+		EntryType:      LedgerDebit,
+		Days:           -lr.DaysCount,
+		LeaveRequestID: &leaveRequestID,
+		ActorUserID:    &actorUserID,
+		Reference:      fmt.Sprintf("leave_request:%d", lr.ID),
+		EffectiveDate:  dateOnly(lr.StartDate),
+		Description: fmt.Sprintf("Leave from %s to %s approved",
+			lr.StartDate.Format("2006-01-02"), lr.EndDate.Format("2006-01-02")),
+	}, nil
+}
+
+// NewAdjustmentEntry builds the ledger entry for a manual adjustment of a balance.
+// Adjustments that would make the balance negative are rejected.
+func NewAdjustmentEntry(employeeID int, leaveType LeaveType, days, balance float64, actorUserID int, reason string) (*LeaveLedgerEntry, error) {
+	if balance+days < 0 {
+		return nil, errors.NewValidationError().AddField("days", fmt.Sprintf("adjustment would make the balance negative (current balance: %s)", FormatDays(balance)))
+	}
+
+	return &LeaveLedgerEntry{
+		EmployeeID:  employeeID,
+		LeaveType:   leaveType,
+		EntryType:   LedgerAdjustment,
+		Days:        days,
+		ActorUserID: &actorUserID,
+		Reference:   "admin",
+		Description: reason,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package leave_test
+
+import (
+	"testing"
+
+	"employee-service/models/leave"
+)
+
+// TestSetRunningBalances tests deriving the balance from ledger entries
+func TestSetRunningBalances(t *testing.T) {
+	entries := []leave.LeaveLedgerEntry{
+		{EntryType: leave.LedgerAdjustment, Days: 10, Reference: "migration"},
+		{EntryType: leave.LedgerCredit, Days: 1.67, Reference: "accrual:2026-01"},
+		{EntryType: leave.LedgerDebit, Days: -2.5},
+		{EntryType: leave.LedgerReversal, Days: 0.5},
+		{EntryType: leave.LedgerCredit, Days: 1.66, Reference: "accrual:2026-02"},
+	}
+
+	leave.SetRunningBalances(entries)
+
+	expected := []float64{10, 11.67, 9.17, 9.67, 11.33}
+	for i, e := range entries {
+		if e.BalanceAfter != expected[i] {
+			t.Errorf("Entry %d: expected balance %v, got %v", i, expected[i], e.BalanceAfter)
+		}
+	}
+}
+
+// TestNewDebitEntry tests charging an approved leave request to the ledger
+func TestNewDebitEntry(t *testing.T) {
+	lr := &leave.LeaveRequest{
+		ID:         42,
+		EmployeeID: 7,
+		LeaveType:  leave.TypeAnnual,
+		StartDate:  mustDate(t, "2026-03-02"),
+		EndDate:    mustDate(t, "2026-03-04"),
+		DaysCount:  2.5,
+	}
+
+	entry, err := leave.NewDebitEntry(lr, 2.5, 3)
+	if err != nil {
+		t.Fatalf("Expected the exact balance to cover the request, got %v", err)
+	}
+	if entry.EntryType != leave.LedgerDebit || entry.Days != -2.5 {
+		t.Errorf("Expected a debit of -2.5 days, got %s %v", entry.EntryType, entry.Days)
+	}
+	if entry.LeaveRequestID == nil || *entry.LeaveRequestID != 42 || entry.ActorUserID == nil || *entry.ActorUserID != 3 {
+		t.Errorf("Expected the entry to reference request 42 and approver 3, got %+v", entry)
+	}
+	if entry.Reference != "leave_request:42" {
+		t.Errorf("Unexpected reference %q", entry.Reference)
+	}
+	if !entry.EffectiveDate.Equal(mustDate(t, "2026-03-02")) {
+		t.Errorf("Expected the debit to count from the first day of leave, got %s", entry.EffectiveDate.Format("2006-01-02"))
+	}
+
+	if _, err := leave.NewDebitEntry(lr, 2, 3); err == nil {
+		t.Error("Expected an insufficient balance to be rejected")
+	}
+}
+
+// TestNewAdjustmentEntry tests manual balance adjustments
+func TestNewAdjustmentEntry(t *testing.T) {
+	cases := []struct {
+		name    string
+		days    float64
+		balance float64
+		valid   bool
+	}{
+		{"Add days", 2, 0, true},
+		{"Remove part of the balance", -1.5, 4, true},
+		{"Remove the whole balance", -4, 4, true},
+		{"Make the balance negative", -4.5, 4, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			entry, err := leave.NewAdjustmentEntry(7, leave.TypeSick, c.days, c.balance, 1, "correction")
+			if !c.valid {
+				assertValidationField(t, err, "days")
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the adjustment to be accepted, got %v", err)
+			}
+			if entry.EntryType != leave.LedgerAdjustment || entry.Days != c.days || entry.Description != "correction" {
+				t.Errorf("Unexpected adjustment entry %+v", entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package leave
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Overlaps reports whether two leave requests claim the same working time.
+// Requests for opposite halves of the same single day do not overlap.
+func (lr *LeaveRequest) Overlaps(other *LeaveRequest) bool {
+	if lr.StartDate.After(other.EndDate) || other.StartDate.After(lr.EndDate) {
+		return false
+	}
+
+	halves := map[DayPart]DayPart{
+		DayPartFirstHalf:  DayPartSecondHalf,
+		DayPartSecondHalf: DayPartFirstHalf,
+	}
+	if opposite, ok := halves[lr.DayPart]; ok && other.DayPart == opposite {
+		return false
+	}
+	return true
+}
+
+// FindOverlaps returns the IDs of the requests that overlap the given request, in ascending order.
+// The request itself is ignored so that it can be re-checked against its own stored row.
+func (lr *LeaveRequest) FindOverlaps(existing []LeaveRequest) []int {
+	ids := []int{}
+	for i := range existing {
+		other := &existing[i]
+		if other.ID == lr.ID && lr.ID != 0 {
+			continue
+		}
+		if lr.Overlaps(other) {
+			ids = append(ids, other.ID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// OverlapError is returned when a leave request overlaps other leave requests of the same
+// employee. It names the conflicting requests so clients can link to them.
+type OverlapError struct {
+	Message               string `json:"message"`
+	ConflictingRequestIDs []int  `json:"conflicting_request_ids"`
+}
+
+// Error implements the error interface
+func (e *OverlapError) Error() string {
+	ids := make([]string, len(e.ConflictingRequestIDs))
+	for i, id := range e.ConflictingRequestIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return fmt.Sprintf("%s (conflicting requests: %s)", e.Message, strings.Join(ids, ", "))
+}
+
+// Fields returns the field errors reported alongside the conflicting request IDs
+func (e *OverlapError) Fields() map[string]string {
+	return map[string]string{"dates": e.Message}
+}
+
+// NewOverlapError builds the error returned when a leave request overlaps pending or approved
+// requests of the same employee
+func NewOverlapError(conflictingIDs []int) *OverlapError {
+	return &OverlapError{
+		Message:               "leave period overlaps existing pending or approved leave requests",
+		ConflictingRequestIDs: conflictingIDs,
+	}
+}
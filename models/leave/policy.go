@@ -0,0 +1,186 @@
+package leave
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+)
+
+// LeavePolicy holds the configurable rules for a leave type
+type LeavePolicy struct {
+	ID                  int       `json:"id"`
+	LeaveType           LeaveType `json:"leave_type"`
+	AnnualEntitlement   int       `json:"annual_entitlement"` // Days granted per year
+	IsPaid              bool      `json:"is_paid"`            // Paid leave deducts from salary
+	EligibleGender      string    `json:"eligible_gender"`    // "" = any, "Male" or "Female"
+	RequiresMarried     bool      `json:"requires_married"`
+	MinTenureDays       int       `json:"min_tenure_days"`                // Days since hired_date before the leave can start
+	MaxConsecutiveDays  int       `json:"max_consecutive_days"`           // 0 = no limit
+	NoticeDays          int       `json:"notice_days"`                    // Minimum days between applying and start_date
+	AccrualFrequency    string    `json:"accrual_frequency"`              // MONTHLY or ANNUAL
+	CarryForwardMax     int       `json:"carry_forward_max"`              // Days carried into the next leave year
+	EncashExcess        bool      `json:"encash_excess"`                  // Encash days above CarryForwardMax instead of lapsing them
+	LowBalanceThreshold float64   `json:"low_balance_threshold"`          // Warn employees at or below this balance; 0 = no warning
+	AttachmentAfterDays float64   `json:"attachment_required_after_days"` // Leave longer than this needs a supporting document; 0 = never
+	IsActive            bool      `json:"is_active"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// CreateLeavePolicyRequest represents the request to create a leave policy
+type CreateLeavePolicyRequest struct {
+	LeaveType           LeaveType `json:"leave_type"`
+	AnnualEntitlement   int       `json:"annual_entitlement"`
+	IsPaid              bool      `json:"is_paid"`
+	EligibleGender      string    `json:"eligible_gender"`
+	RequiresMarried     bool      `json:"requires_married"`
+	MinTenureDays       int       `json:"min_tenure_days"`
+	MaxConsecutiveDays  int       `json:"max_consecutive_days"`
+	NoticeDays          int       `json:"notice_days"`
+	AccrualFrequency    string    `json:"accrual_frequency"` // defaults to MONTHLY
+	CarryForwardMax     int       `json:"carry_forward_max"`
+	EncashExcess        bool      `json:"encash_excess"`
+	LowBalanceThreshold float64   `json:"low_balance_threshold"`
+	AttachmentAfterDays float64   `json:"attachment_required_after_days"`
+	IsActive            *bool     `json:"is_active,omitempty"` // defaults to true
+}
+
+// UpdateLeavePolicyRequest represents the request to update a leave policy
+type UpdateLeavePolicyRequest struct {
+	AnnualEntitlement   *int     `json:"annual_entitlement,omitempty"`
+	IsPaid              *bool    `json:"is_paid,omitempty"`
+	EligibleGender      *string  `json:"eligible_gender,omitempty"`
+	RequiresMarried     *bool    `json:"requires_married,omitempty"`
+	MinTenureDays       *int     `json:"min_tenure_days,omitempty"`
+	MaxConsecutiveDays  *int     `json:"max_consecutive_days,omitempty"`
+	NoticeDays          *int     `json:"notice_days,omitempty"`
+	AccrualFrequency    *string  `json:"accrual_frequency,omitempty"`
+	CarryForwardMax     *int     `json:"carry_forward_max,omitempty"`
+	EncashExcess        *bool    `json:"encash_excess,omitempty"`
+	LowBalanceThreshold *float64 `json:"low_balance_threshold,omitempty"`
+	AttachmentAfterDays *float64 `json:"attachment_required_after_days,omitempty"`
+	IsActive            *bool    `json:"is_active,omitempty"`
+}
+
+// Validate validates the CreateLeavePolicyRequest
+func (r *CreateLeavePolicyRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if strings.TrimSpace(string(r.LeaveType)) == "" {
+		validationErr.AddField("leave_type", "leave_type is required")
+	}
+	if r.AnnualEntitlement < 0 {
+		validationErr.AddField("annual_entitlement", "annual_entitlement cannot be negative")
+	}
+	if r.EligibleGender != "" && r.EligibleGender != "Male" && r.EligibleGender != "Female" {
+		validationErr.AddField("eligible_gender", "eligible_gender must be empty, 'Male' or 'Female'")
+	}
+	if r.MinTenureDays < 0 {
+		validationErr.AddField("min_tenure_days", "min_tenure_days cannot be negative")
+	}
+	if r.MaxConsecutiveDays < 0 {
+		validationErr.AddField("max_consecutive_days", "max_consecutive_days cannot be negative")
+	}
+	if r.NoticeDays < 0 {
+		validationErr.AddField("notice_days", "notice_days cannot be negative")
+	}
+	if r.AccrualFrequency != "" && !IsValidAccrualFrequency(r.AccrualFrequency) {
+		validationErr.AddField("accrual_frequency", "accrual_frequency must be MONTHLY or ANNUAL")
+	}
+	if r.CarryForwardMax < 0 {
+		validationErr.AddField("carry_forward_max", "carry_forward_max cannot be negative")
+	}
+	if r.LowBalanceThreshold < 0 {
+		validationErr.AddField("low_balance_threshold", "low_balance_threshold cannot be negative")
+	}
+	if r.AttachmentAfterDays < 0 {
+		validationErr.AddField("attachment_required_after_days", "attachment_required_after_days cannot be negative")
+	}
+
+	return validationErr.Validate()
+}
+
+// Validate validates the UpdateLeavePolicyRequest
+func (r *UpdateLeavePolicyRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.AnnualEntitlement != nil && *r.AnnualEntitlement < 0 {
+		validationErr.AddField("annual_entitlement", "annual_entitlement cannot be negative")
+	}
+	if r.EligibleGender != nil && *r.EligibleGender != "" && *r.EligibleGender != "Male" && *r.EligibleGender != "Female" {
+		validationErr.AddField("eligible_gender", "eligible_gender must be empty, 'Male' or 'Female'")
+	}
+	if r.MinTenureDays != nil && *r.MinTenureDays < 0 {
+		validationErr.AddField("min_tenure_days", "min_tenure_days cannot be negative")
+	}
+	if r.MaxConsecutiveDays != nil && *r.MaxConsecutiveDays < 0 {
+		validationErr.AddField("max_consecutive_days", "max_consecutive_days cannot be negative")
+	}
+	if r.NoticeDays != nil && *r.NoticeDays < 0 {
+		validationErr.AddField("notice_days", "notice_days cannot be negative")
+	}
+	if r.AccrualFrequency != nil && !IsValidAccrualFrequency(*r.AccrualFrequency) {
+		validationErr.AddField("accrual_frequency", "accrual_frequency must be MONTHLY or ANNUAL")
+	}
+	if r.CarryForwardMax != nil && *r.CarryForwardMax < 0 {
+		validationErr.AddField("carry_forward_max", "carry_forward_max cannot be negative")
+	}
+	if r.LowBalanceThreshold != nil && *r.LowBalanceThreshold < 0 {
+		validationErr.AddField("low_balance_threshold", "low_balance_threshold cannot be negative")
+	}
+	if r.AttachmentAfterDays != nil && *r.AttachmentAfterDays < 0 {
+		validationErr.AddField("attachment_required_after_days", "attachment_required_after_days cannot be negative")
+	}
+
+	return validationErr.Validate()
+}
+
+// CheckEligibility validates a leave application against the policy rules.
+// The returned ValidationError has no fields when the application is allowed.
+func (p *LeavePolicy) CheckEligibility(gender string, isMarried bool, hiredDate, startDate time.Time, daysCount float64, today time.Time) *errors.ValidationError {
+	validationErr := errors.NewValidationError()
+	name := strings.ToLower(string(p.LeaveType))
+
+	if !p.IsActive {
+		return validationErr.AddField("leave_type", fmt.Sprintf("%s leave is not currently offered", name))
+	}
+
+	if p.EligibleGender != "" && !strings.EqualFold(gender, p.EligibleGender) {
+		validationErr.AddField("leave_type", fmt.Sprintf("%s leave is only available for %s employees", name, strings.ToLower(p.EligibleGender)))
+	} else if p.RequiresMarried && !isMarried {
+		validationErr.AddField("leave_type", fmt.Sprintf("%s leave is only available for married employees", name))
+	}
+
+	if p.MinTenureDays > 0 {
+		eligibleFrom := hiredDate.AddDate(0, 0, p.MinTenureDays)
+		if startDate.Before(eligibleFrom) {
+			validationErr.AddField("start_date", fmt.Sprintf("%s leave requires at least %d days of service (eligible from %s)", name, p.MinTenureDays, eligibleFrom.Format("2006-01-02")))
+		}
+	}
+
+	if p.MaxConsecutiveDays > 0 && daysCount > float64(p.MaxConsecutiveDays) {
+		validationErr.AddField("dates", fmt.Sprintf("%s leave cannot exceed %d consecutive days", name, p.MaxConsecutiveDays))
+	}
+
+	if p.NoticeDays > 0 {
+		earliestStart := today.Truncate(24*time.Hour).AddDate(0, 0, p.NoticeDays)
+		if startDate.Before(earliestStart) {
+			validationErr.AddField("start_date", fmt.Sprintf("%s leave must be applied at least %d days in advance", name, p.NoticeDays))
+		}
+	}
+
+	return validationErr
+}
+
+// DeductsSalary reports whether approved leave of this type is taken out of the salary: paid
+// leave, and UNPAID leave, which is leave without pay whatever the policy says
+func (p *LeavePolicy) DeductsSalary() bool {
+	return p.IsPaid || p.LeaveType == TypeUnpaid
+}
+
+// IsLowBalance reports whether a balance is at or below the policy's low-balance threshold
+func (p *LeavePolicy) IsLowBalance(balance float64) bool {
+	return p.LowBalanceThreshold > 0 && balance <= p.LowBalanceThreshold
+}
@@ -0,0 +1,128 @@
+package leave_test
+
+import (
+	"testing"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// assertValidationField checks that err is nil when field is empty, and otherwise a
+// validation error for exactly that field
+func assertValidationField(t *testing.T, err error, field string) {
+	t.Helper()
+	if field == "" {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		return
+	}
+
+	validationErr, ok := err.(*errors.ValidationError)
+	if !ok {
+		t.Fatalf("Expected a validation error for %s, got %v", field, err)
+	}
+	if _, ok := validationErr.Fields[field]; !ok || len(validationErr.Fields) != 1 {
+		t.Errorf("Expected only %s to fail, got %v", field, validationErr.Fields)
+	}
+}
+
+// TestCheckEligibility tests the eligibility rules of a leave policy
+func TestCheckEligibility(t *testing.T) {
+	today := mustDate(t, "2026-03-02")
+	hired := mustDate(t, "2025-01-06")
+	nextWeek := mustDate(t, "2026-03-09")
+
+	cases := []struct {
+		name     string
+		policy   leave.LeavePolicy
+		gender   string
+		married  bool
+		hired    string
+		days     float64
+		expected []string // Fields expected to fail; empty when the application is allowed
+	}{
+		{"Allowed", leave.LeavePolicy{LeaveType: leave.TypeAnnual, IsActive: true}, "Male", false, "", 3, nil},
+		{"Inactive policy", leave.LeavePolicy{LeaveType: leave.TypeAnnual}, "Male", false, "", 3, []string{"leave_type"}},
+		{"Gender restricted", leave.LeavePolicy{LeaveType: leave.TypeMaternity, EligibleGender: "Female", IsActive: true}, "Male", true, "", 3, []string{"leave_type"}},
+		{"Gender matches case-insensitively", leave.LeavePolicy{LeaveType: leave.TypeMaternity, EligibleGender: "Female", IsActive: true}, "female", false, "", 3, nil},
+		{"Requires married", leave.LeavePolicy{LeaveType: leave.TypePaternity, EligibleGender: "Male", RequiresMarried: true, IsActive: true}, "Male", false, "", 3, []string{"leave_type"}},
+		{"Married", leave.LeavePolicy{LeaveType: leave.TypePaternity, EligibleGender: "Male", RequiresMarried: true, IsActive: true}, "Male", true, "", 3, nil},
+		{"Tenure not reached", leave.LeavePolicy{LeaveType: leave.TypeAnnual, MinTenureDays: 90, IsActive: true}, "Male", false, "2026-01-05", 1, []string{"start_date"}},
+		{"Tenure reached on start date", leave.LeavePolicy{LeaveType: leave.TypeAnnual, MinTenureDays: 90, IsActive: true}, "Male", false, "2025-12-09", 1, nil},
+		{"Too many consecutive days", leave.LeavePolicy{LeaveType: leave.TypeCasual, MaxConsecutiveDays: 3, IsActive: true}, "Male", false, "", 3.5, []string{"dates"}},
+		{"Exactly the consecutive limit", leave.LeavePolicy{LeaveType: leave.TypeCasual, MaxConsecutiveDays: 3, IsActive: true}, "Male", false, "", 3, nil},
+		{"Short notice", leave.LeavePolicy{LeaveType: leave.TypeAnnual, NoticeDays: 14, IsActive: true}, "Male", false, "", 1, []string{"start_date"}},
+		{"Enough notice", leave.LeavePolicy{LeaveType: leave.TypeAnnual, NoticeDays: 7, IsActive: true}, "Male", false, "", 1, nil},
+		{"Several rules fail", leave.LeavePolicy{LeaveType: leave.TypeCasual, MaxConsecutiveDays: 2, NoticeDays: 14, IsActive: true}, "Male", false, "", 5, []string{"dates", "start_date"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hiredDate := hired
+			if c.hired != "" {
+				hiredDate = mustDate(t, c.hired)
+			}
+
+			got := c.policy.CheckEligibility(c.gender, c.married, hiredDate, nextWeek, c.days, today)
+			if len(got.Fields) != len(c.expected) {
+				t.Fatalf("Expected failing fields %v, got %v", c.expected, got.Fields)
+			}
+			for _, field := range c.expected {
+				if _, ok := got.Fields[field]; !ok {
+					t.Errorf("Expected %s to fail, got %v", field, got.Fields)
+				}
+			}
+		})
+	}
+}
+
+// TestLeavePolicyRequestValidate tests validation of policy create and update requests
+func TestLeavePolicyRequestValidate(t *testing.T) {
+	negative := -1
+	gender := "Other"
+	frequency := "weekly"
+	annual := "annual"
+
+	createCases := []struct {
+		name    string
+		request leave.CreateLeavePolicyRequest
+		field   string // Field expected to fail; empty when the request is valid
+	}{
+		{"Valid", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", AnnualEntitlement: 5, EligibleGender: "Female", AccrualFrequency: "annual"}, ""},
+		{"Missing leave type", leave.CreateLeavePolicyRequest{LeaveType: "  ", AnnualEntitlement: 5}, "leave_type"},
+		{"Negative entitlement", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", AnnualEntitlement: -2}, "annual_entitlement"},
+		{"Unknown gender", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", EligibleGender: "Other"}, "eligible_gender"},
+		{"Negative tenure", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", MinTenureDays: -1}, "min_tenure_days"},
+		{"Negative consecutive days", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", MaxConsecutiveDays: -1}, "max_consecutive_days"},
+		{"Negative notice", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", NoticeDays: -1}, "notice_days"},
+		{"Unknown accrual frequency", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", AccrualFrequency: "weekly"}, "accrual_frequency"},
+		{"Negative carry forward", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", CarryForwardMax: -1}, "carry_forward_max"},
+		{"Negative low balance threshold", leave.CreateLeavePolicyRequest{LeaveType: "STUDY", LowBalanceThreshold: -0.5}, "low_balance_threshold"},
+	}
+
+	for _, c := range createCases {
+		t.Run("Create/"+c.name, func(t *testing.T) {
+			assertValidationField(t, c.request.Validate(), c.field)
+		})
+	}
+
+	updateCases := []struct {
+		name    string
+		request leave.UpdateLeavePolicyRequest
+		field   string
+	}{
+		{"Empty update", leave.UpdateLeavePolicyRequest{}, ""},
+		{"Valid frequency", leave.UpdateLeavePolicyRequest{AccrualFrequency: &annual}, ""},
+		{"Negative entitlement", leave.UpdateLeavePolicyRequest{AnnualEntitlement: &negative}, "annual_entitlement"},
+		{"Unknown gender", leave.UpdateLeavePolicyRequest{EligibleGender: &gender}, "eligible_gender"},
+		{"Unknown accrual frequency", leave.UpdateLeavePolicyRequest{AccrualFrequency: &frequency}, "accrual_frequency"},
+		{"Negative notice", leave.UpdateLeavePolicyRequest{NoticeDays: &negative}, "notice_days"},
+	}
+
+	for _, c := range updateCases {
+		t.Run("Update/"+c.name, func(t *testing.T) {
+			assertValidationField(t, c.request.Validate(), c.field)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package leave
+
+import "time"
+
+// ReminderAction is what the reminder job owes a waiting approval step
+type ReminderAction string
+
+const (
+	ReminderNone     ReminderAction = ""
+	ReminderRemind   ReminderAction = "REMIND"   // Remind the step's approvers
+	ReminderEscalate ReminderAction = "ESCALATE" // Escalate to the next approver level
+)
+
+// ReminderPolicy configures when waiting approval steps are chased
+type ReminderPolicy struct {
+	RemindAfter   time.Duration // Remind the approvers once a step has waited this long
+	EscalateAfter time.Duration // Escalate once a step has waited this long; 0 disables escalation
+}
+
+// Due returns the action owed for a step that has been waiting for a decision.
+// Each step is reminded and escalated at most once, and never reminded after it was escalated.
+// Steps waiting for more information from the employee are paused and owe nothing.
+func (p ReminderPolicy) Due(step *LeaveApproval, waiting time.Duration) ReminderAction {
+	if step.EscalatedAt != nil || step.InfoRequestedAt != nil {
+		return ReminderNone
+	}
+	if p.EscalateAfter > 0 && waiting >= p.EscalateAfter {
+		return ReminderEscalate
+	}
+	if p.RemindAfter > 0 && waiting >= p.RemindAfter && step.RemindedAt == nil {
+		return ReminderRemind
+	}
+	return ReminderNone
+}
+
+// ReminderRunResult summarizes a run of the approval reminder job
+type ReminderRunResult struct {
+	RunAt        string `json:"run_at"`
+	StepsChecked int    `json:"steps_checked"`
+	Reminded     int    `json:"reminded"`
+	Escalated    int    `json:"escalated"`
+	Errors       int    `json:"errors"`
+}
@@ -0,0 +1,116 @@
+package leave_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/leave"
+)
+
+// TestReminderPolicyDue tests when a waiting approval step is reminded and escalated
+func TestReminderPolicyDue(t *testing.T) {
+	policy := leave.ReminderPolicy{RemindAfter: 48 * time.Hour, EscalateAfter: 96 * time.Hour}
+	sent := time.Now()
+
+	cases := []struct {
+		name     string
+		step     leave.LeaveApproval
+		waiting  time.Duration
+		expected leave.ReminderAction
+	}{
+		{"Within SLA", leave.LeaveApproval{}, 47 * time.Hour, leave.ReminderNone},
+		{"Past reminder SLA", leave.LeaveApproval{}, 48 * time.Hour, leave.ReminderRemind},
+		{"Already reminded", leave.LeaveApproval{RemindedAt: &sent}, 60 * time.Hour, leave.ReminderNone},
+		{"Past escalation SLA", leave.LeaveApproval{RemindedAt: &sent}, 96 * time.Hour, leave.ReminderEscalate},
+		{"Escalated without reminder", leave.LeaveApproval{}, 120 * time.Hour, leave.ReminderEscalate},
+		{"Already escalated", leave.LeaveApproval{RemindedAt: &sent, EscalatedAt: &sent}, 200 * time.Hour, leave.ReminderNone},
+		{"Paused for more information", leave.LeaveApproval{InfoRequestedAt: &sent}, 200 * time.Hour, leave.ReminderNone},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := policy.Due(&c.step, c.waiting); got != c.expected {
+				t.Errorf("Due(%s) = %q, expected %q", c.waiting, got, c.expected)
+			}
+		})
+	}
+
+	noEscalation := leave.ReminderPolicy{RemindAfter: 48 * time.Hour}
+	if got := noEscalation.Due(&leave.LeaveApproval{RemindedAt: &sent}, 500*time.Hour); got != leave.ReminderNone {
+		t.Errorf("Due without escalation = %q, expected none", got)
+	}
+}
+
+// TestPendingSince tests when an approval step started waiting
+func TestPendingSince(t *testing.T) {
+	requested := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	decided := requested.Add(30 * time.Hour)
+
+	approvals := []leave.LeaveApproval{
+		{Step: 1, Decision: leave.DecisionApproved, DecidedAt: &decided},
+		{Step: 2, Decision: leave.DecisionPending},
+	}
+
+	if got := leave.PendingSince(approvals, &approvals[0], requested); !got.Equal(requested) {
+		t.Errorf("first step pending since %s, expected %s", got, requested)
+	}
+	if got := leave.PendingSince(approvals, &approvals[1], requested); !got.Equal(decided) {
+		t.Errorf("second step pending since %s, expected %s", got, decided)
+	}
+}
+
+// TestLeaveApprovalWaiting tests that time paused for more information does not count as waiting
+func TestLeaveApprovalWaiting(t *testing.T) {
+	pendingSince := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	now := pendingSince.Add(72 * time.Hour)
+	askedAt := now.Add(-24 * time.Hour)
+	justNow := now
+
+	cases := []struct {
+		name     string
+		step     leave.LeaveApproval
+		expected time.Duration
+	}{
+		{"Never paused", leave.LeaveApproval{}, 72 * time.Hour},
+		{"Paused earlier", leave.LeaveApproval{PausedSeconds: int64((30 * time.Hour).Seconds())}, 42 * time.Hour},
+		{"Paused now", leave.LeaveApproval{InfoRequestedAt: &askedAt}, 48 * time.Hour},
+		{"Paused earlier and now", leave.LeaveApproval{PausedSeconds: int64((10 * time.Hour).Seconds()), InfoRequestedAt: &askedAt}, 38 * time.Hour},
+		{"Paused longer than waited", leave.LeaveApproval{PausedSeconds: int64((100 * time.Hour).Seconds()), InfoRequestedAt: &justNow}, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.step.Waiting(pendingSince, now); got != c.expected {
+				t.Errorf("Waiting = %s, expected %s", got, c.expected)
+			}
+		})
+	}
+}
+
+// TestAwaitingInfo tests that only the step awaiting a decision pauses a request
+func TestAwaitingInfo(t *testing.T) {
+	asked := time.Date(2026, time.March, 3, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		approvals []leave.LeaveApproval
+		expected  bool
+	}{
+		{"No chain", nil, false},
+		{"Waiting for decision", []leave.LeaveApproval{{Step: 1, Decision: leave.DecisionPending}}, false},
+		{"Waiting for information", []leave.LeaveApproval{{Step: 1, Decision: leave.DecisionPending, InfoRequestedAt: &asked}}, true},
+		{"Earlier step was paused", []leave.LeaveApproval{
+			{Step: 1, Decision: leave.DecisionApproved, InfoRequestedAt: &asked},
+			{Step: 2, Decision: leave.DecisionPending},
+		}, false},
+		{"Decided", []leave.LeaveApproval{{Step: 1, Decision: leave.DecisionRejected, InfoRequestedAt: &asked}}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := leave.AwaitingInfo(c.approvals); got != c.expected {
+				t.Errorf("AwaitingInfo = %v, expected %v", got, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package leave
+
+import (
+	"time"
+)
+
+// FieldChange is one field of a leave request that an edit changed
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// LeaveRequestRevision records an edit of a pending leave request so approvers can see what changed
+type LeaveRequestRevision struct {
+	ID             int           `json:"id"`
+	LeaveRequestID int           `json:"leave_request_id"`
+	Revision       int           `json:"revision"`  // 1 for the first edit
+	EditedBy       int           `json:"edited_by"` // User who made the edit
+	Changes        []FieldChange `json:"changes"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// DiffLeaveRequests lists the fields an employee can edit that differ between two versions of a
+// leave request, in a stable order
+func DiffLeaveRequests(before, after *LeaveRequest) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("leave_type", string(before.LeaveType), string(after.LeaveType))
+	add("start_date", before.StartDate.Format("2006-01-02"), after.StartDate.Format("2006-01-02"))
+	add("end_date", before.EndDate.Format("2006-01-02"), after.EndDate.Format("2006-01-02"))
+	add("day_part", string(before.DayPart), string(after.DayPart))
+	add("hours", FormatDays(before.Hours), FormatDays(after.Hours))
+	add("days_count", FormatDays(before.DaysCount), FormatDays(after.DaysCount))
+	add("reason", before.Reason, after.Reason)
+	add("notes", notesText(before.Notes), notesText(after.Notes))
+
+	return changes
+}
+
+// notesText returns optional notes as text
+func notesText(notes *string) string {
+	if notes == nil {
+		return ""
+	}
+	return *notes
+}
+
+// NeedsReapproval reports whether an edit changes what approvers agreed to. Only edits to the
+// reason or notes keep the decisions already made on the approval chain.
+func NeedsReapproval(changes []FieldChange) bool {
+	for _, c := range changes {
+		if c.Field != "reason" && c.Field != "notes" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package leave_test
+
+import (
+	"testing"
+
+	"employee-service/models/leave"
+)
+
+// TestDiffLeaveRequests tests which edits of a leave request are recorded and whether they need approval again
+func TestDiffLeaveRequests(t *testing.T) {
+	notes := "handover done"
+	before := leave.LeaveRequest{
+		LeaveType: leave.TypeAnnual,
+		StartDate: mustDate(t, "2026-03-09"),
+		EndDate:   mustDate(t, "2026-03-13"),
+		DayPart:   leave.DayPartFull,
+		DaysCount: 5,
+		Reason:    "family trip",
+	}
+
+	cases := []struct {
+		name       string
+		edit       func(lr *leave.LeaveRequest)
+		fields     []string
+		reapproval bool
+	}{
+		{"No change", func(lr *leave.LeaveRequest) {}, []string{}, false},
+		{"Reason and notes", func(lr *leave.LeaveRequest) { lr.Reason = "wedding"; lr.Notes = &notes }, []string{"reason", "notes"}, false},
+		{"Shorter leave", func(lr *leave.LeaveRequest) { lr.EndDate = mustDate(t, "2026-03-11"); lr.DaysCount = 3 }, []string{"end_date", "days_count"}, true},
+		{"Half day", func(lr *leave.LeaveRequest) {
+			lr.EndDate = lr.StartDate
+			lr.DayPart = leave.DayPartSecondHalf
+			lr.DaysCount = 0.5
+		}, []string{"end_date", "day_part", "days_count"}, true},
+		{"Leave type", func(lr *leave.LeaveRequest) { lr.LeaveType = leave.TypeCasual }, []string{"leave_type"}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			after := before
+			c.edit(&after)
+
+			changes := leave.DiffLeaveRequests(&before, &after)
+			if len(changes) != len(c.fields) {
+				t.Fatalf("Expected changes to %v, got %+v", c.fields, changes)
+			}
+			for i, field := range c.fields {
+				if changes[i].Field != field {
+					t.Errorf("Expected change %d to be %s, got %+v", i, field, changes[i])
+				}
+			}
+			if got := leave.NeedsReapproval(changes); got != c.reapproval {
+				t.Errorf("Expected reapproval %v, got %v", c.reapproval, got)
+			}
+		})
+	}
+
+	changes := leave.DiffLeaveRequests(&before, &leave.LeaveRequest{
+		LeaveType: before.LeaveType, StartDate: before.StartDate, EndDate: mustDate(t, "2026-03-11"),
+		DayPart: before.DayPart, DaysCount: 3, Reason: before.Reason,
+	})
+	if changes[0].From != "2026-03-13" || changes[0].To != "2026-03-11" || changes[1].From != "5" || changes[1].To != "3" {
+		t.Errorf("Expected old and new values to be recorded, got %+v", changes)
+	}
+}
@@ -0,0 +1,89 @@
+package leave
+
+import "time"
+
+// LeaveRollover records the year-end processing of one leave balance.
+// Each (employee, leave type, year) is rolled over at most once.
+type LeaveRollover struct {
+	ID             int       `json:"id"`
+	EmployeeID     int       `json:"employee_id"`
+	EmployeeName   string    `json:"employee_name,omitempty"`
+	LeaveType      LeaveType `json:"leave_type"`
+	Year           int       `json:"year"`            // Leave year that was closed
+	ClosingBalance float64   `json:"closing_balance"` // Balance at year end, excluding days accrued for the new year
+	CarriedForward float64   `json:"carried_forward"`
+	Lapsed         float64   `json:"lapsed"`
+	Encashed       float64   `json:"encashed"`
+	EncashAmount   float64   `json:"encash_amount"` // Pay for the encashed days, priced by payroll
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// YearEndBalance is a leave balance that is due for year-end processing
+type YearEndBalance struct {
+	EmployeeID   int
+	EmployeeName string
+	LeaveType    LeaveType
+	Balance      float64 // Balance at the end of the year
+}
+
+// YearEndDate returns the last day of a leave year, which ledger entries count towards
+// when they are effective on or before it
+func YearEndDate(year int) time.Time {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// RolloverReport summarizes a year-end run per employee
+type RolloverReport struct {
+	Year                int                     `json:"year"`
+	DryRun              bool                    `json:"dry_run"`
+	Employees           []EmployeeRolloverEntry `json:"employees"`
+	BalancesProcessed   int                     `json:"balances_processed"`
+	AlreadyProcessed    int                     `json:"already_processed"` // Balances rolled over by an earlier run
+	TotalCarriedForward float64                 `json:"total_carried_forward"`
+	TotalLapsed         float64                 `json:"total_lapsed"`
+	TotalEncashed       float64                 `json:"total_encashed"`
+	TotalEncashAmount   float64                 `json:"total_encash_amount"`
+	Errors              int                     `json:"errors"`
+}
+
+// EmployeeRolloverEntry groups the rollover changes for one employee
+type EmployeeRolloverEntry struct {
+	EmployeeID   int             `json:"employee_id"`
+	EmployeeName string          `json:"employee_name"`
+	Changes      []LeaveRollover `json:"changes"`
+}
+
+// YearEnd applies the carry-forward rules of the policy to a closing balance.
+// Up to CarryForwardMax days are carried; the excess is encashed or lapses. Encashed days are
+// priced from the employee's salary by payroll, which sets EncashAmount.
+func (p *LeavePolicy) YearEnd(employeeID int, year int, closingBalance float64) LeaveRollover {
+	rollover := LeaveRollover{
+		EmployeeID:     employeeID,
+		LeaveType:      p.LeaveType,
+		Year:           year,
+		ClosingBalance: closingBalance,
+	}
+
+	// Comp-off lapses credit by credit when it expires, not at year end
+	if p.LeaveType == TypeCompOff {
+		rollover.CarriedForward = closingBalance
+		return rollover
+	}
+
+	carryForwardMax := float64(p.CarryForwardMax)
+	if closingBalance <= carryForwardMax {
+		// Nothing in excess (negative balances are carried as-is)
+		rollover.CarriedForward = closingBalance
+		return rollover
+	}
+
+	rollover.CarriedForward = carryForwardMax
+	excess := RoundDays(closingBalance - carryForwardMax)
+	if p.EncashExcess {
+		rollover.Encashed = excess
+	} else {
+		rollover.Lapsed = excess
+	}
+
+	return rollover
+}
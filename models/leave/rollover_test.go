@@ -0,0 +1,38 @@
+package leave_test
+
+import (
+	"testing"
+
+	"employee-service/models/leave"
+)
+
+// TestYearEnd tests carry-forward, lapse and encashment at year end
+func TestYearEnd(t *testing.T) {
+	policy := &leave.LeavePolicy{LeaveType: leave.TypeAnnual, CarryForwardMax: 5}
+
+	rollover := policy.YearEnd(1, 2025, 8)
+	if rollover.CarriedForward != 5 || rollover.Lapsed != 3 || rollover.Encashed != 0 {
+		t.Errorf("Expected 5 carried and 3 lapsed, got %+v", rollover)
+	}
+
+	rollover = policy.YearEnd(1, 2025, 4)
+	if rollover.CarriedForward != 4 || rollover.Lapsed != 0 {
+		t.Errorf("Expected all 4 days carried, got %+v", rollover)
+	}
+
+	policy.EncashExcess = true
+	rollover = policy.YearEnd(1, 2025, 8)
+	if rollover.CarriedForward != 5 || rollover.Lapsed != 0 || rollover.Encashed != 3 {
+		t.Errorf("Expected 5 carried and 3 encashed, got %+v", rollover)
+	}
+	if rollover.EncashAmount != 0 {
+		t.Errorf("Expected the encash amount to be left to payroll, got %.2f", rollover.EncashAmount)
+	}
+
+	// Comp-off is carried in full; it lapses when each credit expires instead
+	compOff := &leave.LeavePolicy{LeaveType: leave.TypeCompOff}
+	rollover = compOff.YearEnd(1, 2025, 3)
+	if rollover.CarriedForward != 3 || rollover.Lapsed != 0 || rollover.Encashed != 0 {
+		t.Errorf("Expected all 3 comp-off days carried, got %+v", rollover)
+	}
+}
@@ -14,6 +14,9 @@ const (
 	EventLeaveCancelled EventType = "LEAVE_CANCELLED"
 	EventLowBalance     EventType = "LOW_BALANCE"
 	EventApprovalReminder EventType = "APPROVAL_REMINDER"
+	EventApprovalEscalation EventType = "APPROVAL_ESCALATION"
+	EventLeaveComment   EventType = "LEAVE_COMMENT"
+	EventLeaveDecisions EventType = "LEAVE_DECISIONS" // Several requests of one employee decided at once
 )
 
 // DeliveryChannel represents how the notification is sent
@@ -50,6 +53,9 @@ type Notification struct {
 	ErrorMessage    *string             `json:"error_message"`
 	SentAt          *time.Time          `json:"sent_at"`
 	NextRetryAt     *time.Time          `json:"next_retry_at"`
+	AttachmentName  string              `json:"attachment_name,omitempty"` // File attached to the email, e.g. "leave.ics"; empty for none
+	AttachmentType  string              `json:"attachment_type,omitempty"` // MIME type of the attachment
+	AttachmentContent string            `json:"-"`
 	CreatedAt       time.Time           `json:"created_at"`
 	UpdatedAt       time.Time           `json:"updated_at"`
 }
@@ -64,13 +70,21 @@ type TemplateData struct {
 	LeaveType        string
 	StartDate        string
 	EndDate          string
-	TotalDays        int
+	TotalDays        float64
+	DayPart          string // Partial-day description, e.g. "first half"; empty for full days
 	Reason           string
 	RejectionReason  string
 	TotalDeduction   float64
 	IsPaidLeave      bool
-	CurrentBalance   int
-	LowBalanceThreshold int
+	CurrentBalance   float64
+	LowBalanceThreshold float64
+	PendingSince     string // When the approval step started waiting, for reminders
+	RecipientName    string // Who the email is addressed to, for emails sent to employees and approvers alike
+	CommentAuthor    string
+	Comment          string
+	InfoRequested    bool   // The comment asks the employee for more information
+	DecisionSummary  string // One line per leave request decided in a bulk decision
+	DecisionNote     string // Note shared by every request of a bulk decision
 }
 
 // EmailTemplate represents an email template
@@ -97,7 +111,7 @@ Leave Details:
 - Leave Type: {{.leave_type}}
 - Start Date: {{.start_date}}
 - End Date: {{.end_date}}
-- Total Days: {{.total_days}}
+- Total Days: {{.total_days}}{{if .day_part}} ({{.day_part}}){{end}}
 - Reason: {{.reason}}
 
 Current Status: PENDING
@@ -124,13 +138,12 @@ Your leave request has been APPROVED.
 Leave Details:
 - Leave Type: {{.leave_type}}
 - Duration: {{.start_date}} to {{.end_date}}
-- Total Days: {{.total_days}}
+- Total Days: {{.total_days}}{{if .day_part}} ({{.day_part}}){{end}}
 
-Salary Deduction Policy:
-- Paid Leave Deduction: ₹500 per day
+Salary Deduction:
 - Total Deduction: ₹{{.total_deduction}}
 
-This amount will be deducted from your salary.
+This amount will be deducted from your pay in the pay periods your leave falls in.
 
 Approved By: {{.admin_name}}
 
@@ -139,7 +152,7 @@ HR Management System
 (no-reply)`,
 			}
 		} else {
-			// For unpaid leaves (MATERNITY, PATERNITY, UNPAID, etc)
+			// For leave that keeps full pay (MATERNITY, PATERNITY, CASUAL, etc)
 			return EmailTemplate{
 				Name:    "leave_approved_employee",
 				Subject: "Leave Approved",
@@ -152,7 +165,7 @@ Your leave request has been APPROVED.
 Leave Details:
 - Leave Type: {{.leave_type}}
 - Duration: {{.start_date}} to {{.end_date}}
-- Total Days: {{.total_days}}
+- Total Days: {{.total_days}}{{if .day_part}} ({{.day_part}}){{end}}
 
 Salary Deduction Policy:
 - No salary deduction applies to this leave type
@@ -177,7 +190,7 @@ This is an automated SMTP notification.
 Your leave request has been REJECTED.
 
 Leave Type: {{.leave_type}}
-Requested Days: {{.total_days}}
+Requested Days: {{.total_days}}{{if .day_part}} ({{.day_part}}){{end}}
 
 Reason for Rejection:
 {{.rejection_reason}}
@@ -202,7 +215,7 @@ Your leave request has been CANCELLED.
 Leave Details:
 - Leave Type: {{.leave_type}}
 - Duration: {{.start_date}} to {{.end_date}}
-- Total Days: {{.total_days}}
+- Total Days: {{.total_days}}{{if .day_part}} ({{.day_part}}){{end}}
 
 If this was unexpected, please contact HR/Admin for assistance.
 
@@ -222,6 +235,7 @@ This is an automated SMTP notification.
 Your {{.leave_type}} leave balance is running low.
 
 Current Balance: {{.current_balance}} days
+Warning Threshold: {{.low_balance_threshold}} days
 
 Please plan your leaves accordingly. Contact HR for more information.
 
@@ -243,9 +257,74 @@ You have pending leave requests that require your attention:
 Employee: {{.employee_name}}
 Leave Type: {{.leave_type}}
 Duration: {{.start_date}} to {{.end_date}} ({{.total_days}} days)
+Awaiting approval since: {{.pending_since}}
 
 Please login to the admin portal to approve or reject these requests.
 
+HR Management System
+(no-reply)`,
+		}
+
+	case EventApprovalEscalation:
+		return EmailTemplate{
+			Name:    "approval_escalation",
+			Subject: "Escalation: Leave Request Awaiting Approval",
+			Body: `Hello {{.admin_name}},
+
+This is an automated SMTP notification.
+
+A leave request has been waiting for approval longer than allowed and has been escalated to you:
+
+Employee: {{.employee_name}}
+Leave Type: {{.leave_type}}
+Duration: {{.start_date}} to {{.end_date}} ({{.total_days}} days)
+Awaiting approval since: {{.pending_since}}
+
+Please login to the admin portal to approve or reject this request.
+
+HR Management System
+(no-reply)`,
+		}
+
+	case EventLeaveComment:
+		return EmailTemplate{
+			Name:    "leave_comment",
+			Subject: "{{if .info_requested}}More Information Needed: {{else}}New Comment: {{end}}Leave Request of {{.employee_name}}",
+			Body: `Hello {{.recipient_name}},
+
+This is an automated SMTP notification.
+
+{{.comment_author}} commented on the leave request of {{.employee_name}}:
+
+{{.comment}}
+{{if .info_requested}}
+More information is needed before this request can be decided. Approval reminders are paused until the employee replies.
+{{end}}
+Leave Type: {{.leave_type}}
+Duration: {{.start_date}} to {{.end_date}} ({{.total_days}} days)
+
+Please login to the portal to view the conversation and reply.
+
+HR Management System
+(no-reply)`,
+		}
+
+	case EventLeaveDecisions:
+		return EmailTemplate{
+			Name:    "leave_decisions",
+			Subject: "Your Leave Requests Have Been Reviewed",
+			Body: `Hello {{.employee_name}},
+
+This is an automated SMTP notification.
+
+{{.admin_name}} has reviewed the following leave requests of yours:
+
+{{.decision_summary}}{{if .decision_note}}
+Note: {{.decision_note}}
+{{end}}
+Please login to the portal for details.
+
+Regards,
 HR Management System
 (no-reply)`,
 		}
@@ -0,0 +1,309 @@
+package payroll
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+)
+
+// PeriodLayout is the format of a pay period, one calendar month
+const PeriodLayout = "2006-01"
+
+// AdjustmentKind says why an employee's pay in a period is adjusted
+type AdjustmentKind string
+
+// Payroll adjustment kinds
+const (
+	KindLeaveDeduction  AdjustmentKind = "LEAVE_DEDUCTION"   // Paid leave charged to the salary
+	KindLeaveWithoutPay AdjustmentKind = "LEAVE_WITHOUT_PAY" // UNPAID leave charged to the salary
+	KindLeaveRefund     AdjustmentKind = "LEAVE_REFUND"      // Deducted leave given back when it is cancelled
+	KindLeaveEncashment AdjustmentKind = "LEAVE_ENCASHMENT"  // Unused leave paid out at year end
+)
+
+// PayrollAdjustment is an amount added to or taken from an employee's pay in one pay period.
+// The base salary on the employee record is never changed; payroll applies these on top of it.
+type PayrollAdjustment struct {
+	ID             int            `json:"id"`
+	EmployeeID     int            `json:"employee_id"`
+	EmployeeName   string         `json:"employee_name,omitempty"`
+	PayPeriod      string         `json:"pay_period"` // "2006-01"
+	Kind           AdjustmentKind `json:"kind"`
+	Days           float64        `json:"days"`
+	DailyRate      float64        `json:"daily_rate"`
+	Amount         float64        `json:"amount"` // Negative when taken from pay
+	LeaveRequestID *int           `json:"leave_request_id,omitempty"`
+	Description    string         `json:"description"`
+	CreatedBy      *int           `json:"created_by,omitempty"`
+	PayrollRunID   *int           `json:"payroll_run_id,omitempty"` // Run that paid it; nil until paid
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// RateFormula says how a monthly salary is turned into a daily rate
+type RateFormula string
+
+// Daily rate formulas
+const (
+	FormulaCalendarDays RateFormula = "CALENDAR_DAYS" // Salary / days in the month
+	FormulaWorkingDays  RateFormula = "WORKING_DAYS"  // Salary / weekdays in the month
+	FormulaFixedDivisor RateFormula = "FIXED_DIVISOR" // Salary / a fixed number of days, e.g. 30
+)
+
+// RatePolicy derives the daily rate of pay from an employee's monthly salary
+type RatePolicy struct {
+	Formula RateFormula `json:"formula"`
+	Divisor float64     `json:"divisor,omitempty"` // Days per month for FIXED_DIVISOR
+}
+
+// DefaultRatePolicy divides the salary by the days in the month
+var DefaultRatePolicy = RatePolicy{Formula: FormulaCalendarDays}
+
+// Validate checks that the formula is known and has what it needs
+func (p RatePolicy) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	switch p.Formula {
+	case FormulaCalendarDays, FormulaWorkingDays:
+	case FormulaFixedDivisor:
+		if p.Divisor <= 0 {
+			validationErr.AddField("divisor", "divisor must be positive for the FIXED_DIVISOR formula")
+		}
+	default:
+		validationErr.AddField("formula", fmt.Sprintf("unknown daily rate formula: %s", p.Formula))
+	}
+
+	return validationErr.Validate()
+}
+
+// DailyRate returns one day's pay in the pay period starting at month, rounded to cents
+func (p RatePolicy) DailyRate(monthlySalary float64, month time.Time) float64 {
+	var days float64
+	switch p.Formula {
+	case FormulaWorkingDays:
+		days = float64(weekdaysInMonth(month))
+	case FormulaFixedDivisor:
+		days = p.Divisor
+	default:
+		days = float64(daysInMonth(month))
+	}
+	if days <= 0 {
+		return 0
+	}
+	return roundAmount(monthlySalary / days)
+}
+
+// PeriodOf returns the pay period a date falls in
+func PeriodOf(date time.Time) string {
+	return date.Format(PeriodLayout)
+}
+
+// ParsePeriod parses a pay period such as "2026-03"
+func ParsePeriod(period string) (time.Time, error) {
+	month, err := time.Parse(PeriodLayout, period)
+	if err != nil {
+		return time.Time{}, errors.NewValidationError().AddField("period", "invalid pay period (use YYYY-MM)")
+	}
+	return month, nil
+}
+
+// LeaveDeductions splits the days of an approved leave request by pay period and prices them at
+// the daily rate of each period, from the salary in effect on the first day of leave in the
+// period. Weekends and holidays skipped by the request are not charged. UNPAID leave is deducted
+// as leave without pay.
+func LeaveDeductions(lr *leave.LeaveRequest, emp *employee.Employee, history employee.CompensationHistory, policy RatePolicy) []PayrollAdjustment {
+	days, firstDays := leaveDaysByPeriod(lr, lr.StartDate)
+	adjustments := make([]PayrollAdjustment, 0, len(days))
+
+	kind := KindLeaveDeduction
+	if lr.LeaveType == leave.TypeUnpaid {
+		kind = KindLeaveWithoutPay
+	}
+
+	for _, period := range sortedPeriods(days) {
+		month, _ := time.Parse(PeriodLayout, period)
+		rate := policy.DailyRate(history.On(emp, firstDays[period]).Salary, month)
+		adjustments = append(adjustments, PayrollAdjustment{
+			EmployeeID:     lr.EmployeeID,
+			PayPeriod:      period,
+			Kind:           kind,
+			Days:           days[period],
+			DailyRate:      rate,
+			Amount:         -roundAmount(days[period] * rate),
+			LeaveRequestID: &lr.ID,
+			Description:    fmt.Sprintf("%s leave %s to %s", lr.LeaveType, lr.StartDate.Format("2006-01-02"), lr.EndDate.Format("2006-01-02")),
+		})
+	}
+
+	return adjustments
+}
+
+// LeaveRefunds gives back the deducted days of a leave request from fromDate on, at the rate each
+// pay period was deducted at. Periods without a deduction are not refunded.
+func LeaveRefunds(lr *leave.LeaveRequest, fromDate time.Time, deductions []PayrollAdjustment) []PayrollAdjustment {
+	rates := map[string]float64{}
+	for _, d := range deductions {
+		if d.Kind == KindLeaveDeduction || d.Kind == KindLeaveWithoutPay {
+			rates[d.PayPeriod] = d.DailyRate
+		}
+	}
+
+	days, _ := leaveDaysByPeriod(lr, fromDate)
+	adjustments := []PayrollAdjustment{}
+
+	for _, period := range sortedPeriods(days) {
+		rate, ok := rates[period]
+		if !ok {
+			continue
+		}
+		adjustments = append(adjustments, PayrollAdjustment{
+			EmployeeID:     lr.EmployeeID,
+			PayPeriod:      period,
+			Kind:           KindLeaveRefund,
+			Days:           days[period],
+			DailyRate:      rate,
+			Amount:         roundAmount(days[period] * rate),
+			LeaveRequestID: &lr.ID,
+			Description:    fmt.Sprintf("%s leave cancelled from %s", lr.LeaveType, fromDate.Format("2006-01-02")),
+		})
+	}
+
+	return adjustments
+}
+
+// LeaveEncashment prices the days encashed by a year-end rollover at the daily rate of December of
+// the closed leave year, from the salary in effect on its last day, and records the amount on the
+// rollover. It returns the adjustment that pays it; a locked December is paid by the next run.
+func LeaveEncashment(rollover *leave.LeaveRollover, emp *employee.Employee, history employee.CompensationHistory, policy RatePolicy) PayrollAdjustment {
+	yearEnd := leave.YearEndDate(rollover.Year)
+	month := time.Date(yearEnd.Year(), yearEnd.Month(), 1, 0, 0, 0, 0, time.UTC)
+	rate := policy.DailyRate(history.On(emp, yearEnd).Salary, month)
+	rollover.EncashAmount = roundAmount(rollover.Encashed * rate)
+
+	return PayrollAdjustment{
+		EmployeeID:  rollover.EmployeeID,
+		PayPeriod:   PeriodOf(yearEnd),
+		Kind:        KindLeaveEncashment,
+		Days:        rollover.Encashed,
+		DailyRate:   rate,
+		Amount:      rollover.EncashAmount,
+		Description: fmt.Sprintf("%s leave encashed at the end of %d", rollover.LeaveType, rollover.Year),
+	}
+}
+
+// TotalAmount sums the amounts of adjustments
+func TotalAmount(adjustments []PayrollAdjustment) float64 {
+	total := 0.0
+	for _, a := range adjustments {
+		total += a.Amount
+	}
+	return roundAmount(total)
+}
+
+// EmployeeDeductions totals the leave adjustments of one employee in a pay period
+type EmployeeDeductions struct {
+	EmployeeID   int                 `json:"employee_id"`
+	EmployeeName string              `json:"employee_name"`
+	Days         float64             `json:"days"`   // Deducted days less refunded days
+	Amount       float64             `json:"amount"` // Net amount to take from pay
+	Adjustments  []PayrollAdjustment `json:"adjustments"`
+}
+
+// DeductionReport lists the leave deductions due in a pay period, per employee
+type DeductionReport struct {
+	PayPeriod   string               `json:"pay_period"`
+	RatePolicy  RatePolicy           `json:"rate_policy"`
+	Employees   []EmployeeDeductions `json:"employees"`
+	TotalAmount float64              `json:"total_amount"`
+}
+
+// NewDeductionReport groups the leave adjustments of a pay period by employee, in the order given
+func NewDeductionReport(period string, policy RatePolicy, adjustments []PayrollAdjustment) *DeductionReport {
+	report := &DeductionReport{PayPeriod: period, RatePolicy: policy, Employees: []EmployeeDeductions{}}
+	index := map[int]int{}
+
+	for _, a := range adjustments {
+		i, ok := index[a.EmployeeID]
+		if !ok {
+			i = len(report.Employees)
+			index[a.EmployeeID] = i
+			report.Employees = append(report.Employees, EmployeeDeductions{
+				EmployeeID:   a.EmployeeID,
+				EmployeeName: a.EmployeeName,
+				Adjustments:  []PayrollAdjustment{},
+			})
+		}
+
+		e := &report.Employees[i]
+		e.Adjustments = append(e.Adjustments, a)
+		switch a.Kind {
+		case KindLeaveDeduction, KindLeaveWithoutPay:
+			e.Days = leave.RoundDays(e.Days + a.Days)
+		case KindLeaveRefund:
+			e.Days = leave.RoundDays(e.Days - a.Days)
+		}
+		e.Amount = roundAmount(e.Amount - a.Amount)
+	}
+
+	for _, e := range report.Employees {
+		report.TotalAmount = roundAmount(report.TotalAmount + e.Amount)
+	}
+	return report
+}
+
+// ParseRateFormula reads a formula name, ignoring case
+func ParseRateFormula(name string) RateFormula {
+	return RateFormula(strings.ToUpper(strings.TrimSpace(name)))
+}
+
+// leaveDaysByPeriod returns the days of a leave request charged in each pay period and the first
+// charged day in each, counting working days from fromDate to the end of the leave
+func leaveDaysByPeriod(lr *leave.LeaveRequest, fromDate time.Time) (map[string]float64, map[string]time.Time) {
+	perDay := leave.LeaveDays(1, lr.DayPart, lr.Hours)
+	days := map[string]float64{}
+	firstDays := map[string]time.Time{}
+	for _, date := range lr.ChargedDates(fromDate) {
+		period := PeriodOf(date)
+		if _, ok := days[period]; !ok {
+			firstDays[period] = date
+		}
+		days[period] = leave.RoundDays(days[period] + perDay)
+	}
+	return days, firstDays
+}
+
+// sortedPeriods returns the periods of a per-period map in order
+func sortedPeriods(days map[string]float64) []string {
+	periods := make([]string, 0, len(days))
+	for period := range days {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+	return periods
+}
+
+// daysInMonth returns the number of days in the month of date
+func daysInMonth(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekdaysInMonth returns the number of Mondays to Fridays in the month of date
+func weekdaysInMonth(date time.Time) int {
+	count := 0
+	for day := 1; day <= daysInMonth(date); day++ {
+		weekday := time.Date(date.Year(), date.Month(), day, 0, 0, 0, 0, time.UTC).Weekday()
+		if weekday != time.Saturday && weekday != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+// roundAmount rounds a money amount to cents
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
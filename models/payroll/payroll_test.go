@@ -0,0 +1,217 @@
+package payroll_test
+
+import (
+	"testing"
+	"time"
+
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+	"employee-service/models/payroll"
+)
+
+func mustDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatalf("invalid test date %s: %v", value, err)
+	}
+	return d
+}
+
+// TestDailyRate tests the daily rate formulas
+func TestDailyRate(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   payroll.RatePolicy
+		salary   float64
+		month    string
+		expected float64
+	}{
+		{"Calendar days in March", payroll.RatePolicy{Formula: payroll.FormulaCalendarDays}, 31000, "2026-03-01", 1000},
+		{"Calendar days in February", payroll.RatePolicy{Formula: payroll.FormulaCalendarDays}, 28000, "2026-02-01", 1000},
+		{"Calendar days in April", payroll.RatePolicy{Formula: payroll.FormulaCalendarDays}, 31000, "2026-04-01", 1033.33},
+		{"Weekdays in March", payroll.RatePolicy{Formula: payroll.FormulaWorkingDays}, 22000, "2026-03-01", 1000},
+		{"Fixed divisor", payroll.RatePolicy{Formula: payroll.FormulaFixedDivisor, Divisor: 30}, 30000, "2026-02-01", 1000},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rate := c.policy.DailyRate(c.salary, mustDate(t, c.month))
+			if rate != c.expected {
+				t.Errorf("Expected a daily rate of %v, got %v", c.expected, rate)
+			}
+		})
+	}
+}
+
+// TestRatePolicyValidate tests validation of the configured daily rate formula
+func TestRatePolicyValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy payroll.RatePolicy
+		field  string
+	}{
+		{"Calendar days", payroll.RatePolicy{Formula: payroll.ParseRateFormula(" calendar_days ")}, ""},
+		{"Fixed divisor", payroll.RatePolicy{Formula: payroll.FormulaFixedDivisor, Divisor: 26}, ""},
+		{"Fixed without divisor", payroll.RatePolicy{Formula: payroll.FormulaFixedDivisor}, "divisor"},
+		{"Unknown formula", payroll.RatePolicy{Formula: "HOURLY"}, "formula"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.policy.Validate(), c.field)
+		})
+	}
+}
+
+// TestLeaveDeductions tests splitting approved leave into deductions per pay period and refunding them
+func TestLeaveDeductions(t *testing.T) {
+	// Monday 2026-03-30 to Friday 2026-04-03 with a holiday on 2026-04-02
+	lr := leave.LeaveRequest{
+		ID:          7,
+		EmployeeID:  3,
+		LeaveType:   leave.TypeAnnual,
+		Status:      leave.StatusApproved,
+		StartDate:   mustDate(t, "2026-03-30"),
+		EndDate:     mustDate(t, "2026-04-03"),
+		DaysCount:   4,
+		DayPart:     leave.DayPartFull,
+		SkippedDays: []leave.SkippedDay{{Date: "2026-04-02", Reason: "holiday: Festival"}},
+	}
+	halfDays := lr
+	halfDays.DayPart = leave.DayPartFirstHalf
+	halfDays.DaysCount = 2
+
+	emp := &employee.Employee{ID: 3, Salary: 31000, Hired: mustDate(t, "2024-01-08")}
+	raised := 36000.0
+	raise := employee.CompensationHistory{{EmployeeID: 3, EffectiveDate: mustDate(t, "2026-04-01"), Salary: &raised}}
+
+	cases := []struct {
+		name    string
+		request leave.LeaveRequest
+		history employee.CompensationHistory
+		periods []string
+		days    []float64
+		amounts []float64
+	}{
+		{"Full days", lr, nil, []string{"2026-03", "2026-04"}, []float64{2, 2}, []float64{-2000, -2066.66}},
+		{"Half days", halfDays, nil, []string{"2026-03", "2026-04"}, []float64{1, 1}, []float64{-1000, -1033.33}},
+		{"Raise during the leave", lr, raise, []string{"2026-03", "2026-04"}, []float64{2, 2}, []float64{-2000, -2400}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deductions := payroll.LeaveDeductions(&c.request, emp, c.history, payroll.DefaultRatePolicy)
+			if len(deductions) != len(c.periods) {
+				t.Fatalf("Expected %d deductions, got %+v", len(c.periods), deductions)
+			}
+			for i, d := range deductions {
+				if d.PayPeriod != c.periods[i] || d.Days != c.days[i] || d.Amount != c.amounts[i] {
+					t.Errorf("Expected %v days and %v in %s, got %+v", c.days[i], c.amounts[i], c.periods[i], d)
+				}
+				if d.Kind != payroll.KindLeaveDeduction || d.EmployeeID != 3 || *d.LeaveRequestID != 7 {
+					t.Errorf("Unexpected deduction: %+v", d)
+				}
+			}
+		})
+	}
+
+	t.Run("Refund from a date", func(t *testing.T) {
+		deductions := payroll.LeaveDeductions(&lr, emp, nil, payroll.DefaultRatePolicy)
+		// Only the April days are refunded, at the rate they were deducted at
+		refunds := payroll.LeaveRefunds(&lr, mustDate(t, "2026-04-01"), deductions)
+
+		if len(refunds) != 1 {
+			t.Fatalf("Expected one refund, got %+v", refunds)
+		}
+		if refunds[0].PayPeriod != "2026-04" || refunds[0].Days != 2 || refunds[0].Amount != 2066.66 || refunds[0].Kind != payroll.KindLeaveRefund {
+			t.Errorf("Unexpected refund: %+v", refunds[0])
+		}
+		if total := payroll.TotalAmount(append(deductions, refunds...)); total != -2000 {
+			t.Errorf("Expected 2000 left deducted, got %v", total)
+		}
+	})
+
+	t.Run("Refunded days match the refunded pay", func(t *testing.T) {
+		// Both refunds count the skipped days stored at approval, so removing the holiday from
+		// the calendar afterwards changes neither
+		deductions := payroll.LeaveDeductions(&lr, emp, nil, payroll.DefaultRatePolicy)
+		from := mustDate(t, "2026-04-01")
+
+		days, err := lr.CancellationRefund(from, mustDate(t, "2026-03-31"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		refunded := 0.0
+		for _, r := range payroll.LeaveRefunds(&lr, from, deductions) {
+			refunded += r.Days
+		}
+		if days != 2 || refunded != days {
+			t.Errorf("Expected 2 days refunded with pay for 2, got %v days with pay for %v", days, refunded)
+		}
+	})
+
+	t.Run("No refund without a deduction", func(t *testing.T) {
+		if refunds := payroll.LeaveRefunds(&lr, lr.StartDate, nil); len(refunds) != 0 {
+			t.Errorf("Expected no refunds, got %+v", refunds)
+		}
+	})
+}
+
+// TestLeaveEncashment tests pricing the days encashed at year end from the employee's salary
+func TestLeaveEncashment(t *testing.T) {
+	emp := &employee.Employee{ID: 3, Salary: 31000, Hired: mustDate(t, "2024-01-08")}
+	raised := 62000.0
+	raise := employee.CompensationHistory{{EmployeeID: 3, EffectiveDate: mustDate(t, "2025-12-15"), Salary: &raised}}
+
+	cases := []struct {
+		name    string
+		history employee.CompensationHistory
+		policy  payroll.RatePolicy
+		rate    float64
+		amount  float64
+	}{
+		{"Calendar days in December", nil, payroll.DefaultRatePolicy, 1000, 3000},
+		{"Fixed divisor", nil, payroll.RatePolicy{Formula: payroll.FormulaFixedDivisor, Divisor: 31}, 1000, 3000},
+		{"Salary at the year end", raise, payroll.DefaultRatePolicy, 2000, 6000},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rollover := leave.LeaveRollover{EmployeeID: 3, LeaveType: leave.TypeAnnual, Year: 2025, Encashed: 3}
+			a := payroll.LeaveEncashment(&rollover, emp, c.history, c.policy)
+
+			if a.Kind != payroll.KindLeaveEncashment || a.PayPeriod != "2025-12" || a.Days != 3 || a.EmployeeID != 3 {
+				t.Errorf("Unexpected encashment: %+v", a)
+			}
+			if a.DailyRate != c.rate || a.Amount != c.amount || rollover.EncashAmount != c.amount {
+				t.Errorf("Expected %v at %v a day, got %+v with %v on the rollover", c.amount, c.rate, a, rollover.EncashAmount)
+			}
+		})
+	}
+}
+
+// TestNewDeductionReport tests totalling the leave adjustments of a pay period per employee
+func TestNewDeductionReport(t *testing.T) {
+	adjustments := []payroll.PayrollAdjustment{
+		{EmployeeID: 3, EmployeeName: "Asha Rao", Kind: payroll.KindLeaveDeduction, Days: 4, Amount: -4000},
+		{EmployeeID: 5, EmployeeName: "Ben Okafor", Kind: payroll.KindLeaveDeduction, Days: 1.5, Amount: -1500},
+		{EmployeeID: 3, EmployeeName: "Asha Rao", Kind: payroll.KindLeaveRefund, Days: 1, Amount: 1000},
+	}
+
+	report := payroll.NewDeductionReport("2026-03", payroll.DefaultRatePolicy, adjustments)
+
+	if len(report.Employees) != 2 {
+		t.Fatalf("Expected 2 employees, got %+v", report.Employees)
+	}
+	first := report.Employees[0]
+	if first.EmployeeID != 3 || first.Days != 3 || first.Amount != 3000 || len(first.Adjustments) != 2 {
+		t.Errorf("Unexpected deductions for employee 3: %+v", first)
+	}
+	if report.Employees[1].Amount != 1500 {
+		t.Errorf("Expected 1500 for employee 5, got %v", report.Employees[1].Amount)
+	}
+	if report.TotalAmount != 4500 {
+		t.Errorf("Expected 4500 in total, got %v", report.TotalAmount)
+	}
+}
@@ -0,0 +1,226 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+)
+
+// KindCorrection is an adjustment entered by HR, e.g. to correct a locked pay period
+const KindCorrection AdjustmentKind = "CORRECTION"
+
+// PayrollRun is the payroll of one pay period. Running it locks the period: it cannot be run
+// again, and later adjustments dated in it are paid by the next run.
+type PayrollRun struct {
+	ID               int       `json:"id"`
+	PayPeriod        string    `json:"pay_period"`
+	RunBy            int       `json:"run_by"` // User who ran the payroll
+	EmployeeCount    int       `json:"employee_count"`
+	TotalGross       float64   `json:"total_gross"`
+	TotalAdjustments float64   `json:"total_adjustments"`
+	TotalNet         float64   `json:"total_net"`
+	CreatedAt        time.Time `json:"created_at"` // When the period was locked
+}
+
+// Payslip is an employee's pay for a pay period: the salary earned in the period plus every
+// adjustment the run paid
+type Payslip struct {
+	ID               int                 `json:"id"`
+	PayrollRunID     int                 `json:"payroll_run_id"`
+	EmployeeID       int                 `json:"employee_id"`
+	EmployeeName     string              `json:"employee_name"`
+	Position         string              `json:"position"`
+	PayPeriod        string              `json:"pay_period"`
+	GrossPay         float64             `json:"gross_pay"`
+	TotalAdjustments float64             `json:"total_adjustments"`
+	NetPay           float64             `json:"net_pay"`
+	Adjustments      []PayrollAdjustment `json:"adjustments"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
+
+// CreateCorrectionRequest represents the request to correct an employee's pay
+type CreateCorrectionRequest struct {
+	EmployeeID  int     `json:"employee_id"`
+	PayPeriod   string  `json:"pay_period"`  // Period the correction relates to, "2006-01"
+	Amount      float64 `json:"amount"`      // Positive to add to pay, negative to take from it
+	Description string  `json:"description"` // Shown on the payslip
+}
+
+// Validate validates the CreateCorrectionRequest
+func (r *CreateCorrectionRequest) Validate() error {
+	validationErr := errors.NewValidationError()
+
+	if r.EmployeeID <= 0 {
+		validationErr.AddField("employee_id", "employee_id is required")
+	}
+	if _, err := time.Parse(PeriodLayout, r.PayPeriod); err != nil {
+		validationErr.AddField("pay_period", "invalid pay period (use YYYY-MM)")
+	}
+	if r.Amount == 0 {
+		validationErr.AddField("amount", "amount must not be zero")
+	} else if roundAmount(r.Amount) != r.Amount {
+		validationErr.AddField("amount", "amount cannot have more than two decimals")
+	}
+	if strings.TrimSpace(r.Description) == "" {
+		validationErr.AddField("description", "description is required")
+	}
+
+	return validationErr.Validate()
+}
+
+// CheckRunnable refuses to run the payroll of a pay period that has not started by today
+func CheckRunnable(period string, today time.Time) error {
+	month, err := ParsePeriod(period)
+	if err != nil {
+		return err
+	}
+	if month.After(time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)) {
+		return errors.NewValidationError().AddField("period", "cannot run payroll for a future pay period")
+	}
+	return nil
+}
+
+// IsPaidIn reports whether an employee is on the payroll of the pay period starting at month,
+// i.e. was hired before it ended
+func IsPaidIn(emp *employee.Employee, month time.Time) bool {
+	return !dateOnly(emp.Hired).After(lastDayOfMonth(month))
+}
+
+// GrossPay returns the monthly salary earned in the pay period starting at month. Each calendar
+// day is paid at the salary in effect on it, so employees hired or given a raise during the period
+// are paid pro rata.
+func GrossPay(emp *employee.Employee, history employee.CompensationHistory, month time.Time) float64 {
+	start := dateOnly(emp.Hired)
+	if start.Before(month) {
+		start = month
+	}
+
+	days := float64(daysInMonth(month))
+	gross := 0.0
+	for date := start; !date.After(lastDayOfMonth(month)); date = date.AddDate(0, 0, 1) {
+		gross += history.On(emp, date).Salary / days
+	}
+	return roundAmount(gross)
+}
+
+// NewPayslip builds the payslip of an employee for a pay period from the adjustments the run
+// pays them. The payslip shows the position held at the end of the period.
+func NewPayslip(emp *employee.Employee, history employee.CompensationHistory, period string, adjustments []PayrollAdjustment) Payslip {
+	month, _ := time.Parse(PeriodLayout, period)
+	gross := GrossPay(emp, history, month)
+	total := TotalAmount(adjustments)
+
+	return Payslip{
+		EmployeeID:       emp.ID,
+		EmployeeName:     fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		Position:         history.On(emp, lastDayOfMonth(month)).Position,
+		PayPeriod:        period,
+		GrossPay:         gross,
+		TotalAdjustments: total,
+		NetPay:           roundAmount(gross + total),
+		Adjustments:      adjustments,
+	}
+}
+
+// Totals adds up the payslips of a run
+func (r *PayrollRun) Totals(payslips []Payslip) {
+	r.EmployeeCount = len(payslips)
+	r.TotalGross, r.TotalAdjustments, r.TotalNet = 0, 0, 0
+	for _, p := range payslips {
+		r.TotalGross = roundAmount(r.TotalGross + p.GrossPay)
+		r.TotalAdjustments = roundAmount(r.TotalAdjustments + p.TotalAdjustments)
+		r.TotalNet = roundAmount(r.TotalNet + p.NetPay)
+	}
+}
+
+// Lines describes a payslip line by line, for printing
+func (p *Payslip) Lines() []string {
+	lines := []string{
+		fmt.Sprintf("Payslip for %s", p.PayPeriod),
+		"",
+		fmt.Sprintf("Employee: %s (ID %d)", p.EmployeeName, p.EmployeeID),
+		fmt.Sprintf("Position: %s", p.Position),
+		"",
+		fmt.Sprintf("Gross pay: %s", formatAmount(p.GrossPay)),
+	}
+
+	if len(p.Adjustments) > 0 {
+		lines = append(lines, "", "Adjustments:")
+		for _, a := range p.Adjustments {
+			lines = append(lines, fmt.Sprintf("  %s  %s  %s", a.PayPeriod, describeAdjustment(a), formatAmount(a.Amount)))
+		}
+		lines = append(lines, fmt.Sprintf("Total adjustments: %s", formatAmount(p.TotalAdjustments)))
+	}
+
+	return append(lines, "", fmt.Sprintf("Net pay: %s", formatAmount(p.NetPay)))
+}
+
+// payslipCSVHeader is the header row of payslip CSV exports. Each adjustment is a row after
+// the payslip's summary row.
+var payslipCSVHeader = []string{
+	"pay_period", "employee_id", "employee_name", "position", "line", "adjustment_period",
+	"days", "daily_rate", "amount", "gross_pay", "total_adjustments", "net_pay",
+}
+
+// WritePayslipsCSV writes payslips as CSV: a PAYSLIP summary row per payslip followed by a row
+// per adjustment
+func WritePayslipsCSV(w io.Writer, payslips []Payslip) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(payslipCSVHeader); err != nil {
+		return err
+	}
+
+	for _, p := range payslips {
+		employeeID := strconv.Itoa(p.EmployeeID)
+		err := cw.Write([]string{
+			p.PayPeriod, employeeID, p.EmployeeName, p.Position, "PAYSLIP", "", "", "", "",
+			formatAmount(p.GrossPay), formatAmount(p.TotalAdjustments), formatAmount(p.NetPay),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, a := range p.Adjustments {
+			err := cw.Write([]string{
+				p.PayPeriod, employeeID, p.EmployeeName, p.Position, describeAdjustment(a), a.PayPeriod,
+				leave.FormatDays(a.Days), formatAmount(a.DailyRate), formatAmount(a.Amount), "", "", "",
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// describeAdjustment names an adjustment on a payslip, e.g. "LEAVE_DEDUCTION: ANNUAL leave ..."
+func describeAdjustment(a PayrollAdjustment) string {
+	if a.Description == "" {
+		return string(a.Kind)
+	}
+	return fmt.Sprintf("%s: %s", a.Kind, a.Description)
+}
+
+// formatAmount formats a money amount with two decimals
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(roundAmount(amount), 'f', 2, 64)
+}
+
+// lastDayOfMonth returns the last day of the month of date
+func lastDayOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// dateOnly drops the time of day from a date
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
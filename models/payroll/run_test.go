@@ -0,0 +1,210 @@
+package payroll_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+	"employee-service/models/payroll"
+)
+
+// TestGrossPay tests the salary earned in a pay period, pro-rated in the month of hiring or of a raise
+func TestGrossPay(t *testing.T) {
+	month := mustDate(t, "2026-04-01")
+
+	cases := []struct {
+		name     string
+		hired    string
+		expected float64
+		paid     bool
+	}{
+		{"Hired before the period", "2024-06-15", 30000, true},
+		{"Hired on the first day", "2026-04-01", 30000, true},
+		{"Hired mid-month", "2026-04-16", 15000, true},
+		{"Hired on the last day", "2026-04-30", 1000, true},
+		{"Hired after the period", "2026-05-04", 0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			emp := &employee.Employee{ID: 1, Salary: 30000, Hired: mustDate(t, c.hired)}
+			if gross := payroll.GrossPay(emp, nil, month); gross != c.expected {
+				t.Errorf("Expected gross pay of %v, got %v", c.expected, gross)
+			}
+			if paid := payroll.IsPaidIn(emp, month); paid != c.paid {
+				t.Errorf("Expected paid in the period to be %v, got %v", c.paid, paid)
+			}
+		})
+	}
+
+	t.Run("Raise mid-month", func(t *testing.T) {
+		emp := &employee.Employee{ID: 1, Salary: 36000, Hired: mustDate(t, "2024-06-15")}
+		before, after := 30000.0, 36000.0
+		history := employee.CompensationHistory{
+			{EmployeeID: 1, EffectiveDate: mustDate(t, "2024-06-15"), Salary: &before},
+			{EmployeeID: 1, EffectiveDate: mustDate(t, "2026-04-16"), Salary: &after},
+		}
+
+		// 15 days at 1000 a day and 15 days at 1200 a day
+		if gross := payroll.GrossPay(emp, history, month); gross != 33000 {
+			t.Errorf("Expected gross pay of 33000, got %v", gross)
+		}
+		if gross := payroll.GrossPay(emp, history, mustDate(t, "2026-03-01")); gross != 30000 {
+			t.Errorf("Expected gross pay of 30000 before the raise, got %v", gross)
+		}
+	})
+}
+
+// TestNewPayslip tests building payslips from gross pay and adjustments and totalling a run
+func TestNewPayslip(t *testing.T) {
+	asha := &employee.Employee{ID: 3, FirstName: "Asha", LastName: "Rao", Position: "Engineer", Salary: 31000, Hired: mustDate(t, "2024-01-08")}
+	ben := &employee.Employee{ID: 5, FirstName: "Ben", LastName: "Okafor", Position: "Analyst", Salary: 20000, Hired: mustDate(t, "2025-02-03")}
+
+	adjustments := []payroll.PayrollAdjustment{
+		{EmployeeID: 3, PayPeriod: "2026-03", Kind: payroll.KindLeaveDeduction, Days: 2, DailyRate: 1000, Amount: -2000, Description: "ANNUAL leave 2026-03-30 to 2026-04-03"},
+		{EmployeeID: 3, PayPeriod: "2026-02", Kind: payroll.KindCorrection, Amount: 500, Description: "Overtime missed in February"},
+	}
+
+	first := payroll.NewPayslip(asha, nil, "2026-03", adjustments)
+	if first.GrossPay != 31000 || first.TotalAdjustments != -1500 || first.NetPay != 29500 {
+		t.Errorf("Unexpected payslip totals: %+v", first)
+	}
+	if first.EmployeeName != "Asha Rao" || first.Position != "Engineer" {
+		t.Errorf("Unexpected payslip employee: %+v", first)
+	}
+
+	second := payroll.NewPayslip(ben, nil, "2026-03", nil)
+	if second.NetPay != 20000 {
+		t.Errorf("Expected net pay of 20000 without adjustments, got %v", second.NetPay)
+	}
+
+	run := payroll.PayrollRun{PayPeriod: "2026-03"}
+	run.Totals([]payroll.Payslip{first, second})
+	if run.EmployeeCount != 2 || run.TotalGross != 51000 || run.TotalAdjustments != -1500 || run.TotalNet != 49500 {
+		t.Errorf("Unexpected run totals: %+v", run)
+	}
+
+	lines := strings.Join(first.Lines(), "\n")
+	for _, want := range []string{"Payslip for 2026-03", "Gross pay: 31000.00", "2026-02  CORRECTION: Overtime missed in February  500.00", "Net pay: 29500.00"} {
+		if !strings.Contains(lines, want) {
+			t.Errorf("Expected the payslip to contain %q, got:\n%s", want, lines)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := payroll.WritePayslipsCSV(&buf, []payroll.Payslip{first, second}); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(rows) != 5 {
+		t.Fatalf("Expected a header, 2 payslip rows and 2 adjustment rows, got:\n%s", buf.String())
+	}
+	if rows[1] != "2026-03,3,Asha Rao,Engineer,PAYSLIP,,,,,31000.00,-1500.00,29500.00" {
+		t.Errorf("Unexpected payslip row: %s", rows[1])
+	}
+}
+
+// TestLeaveWithoutPay tests that approved UNPAID leave is taken out of the payslip
+func TestLeaveWithoutPay(t *testing.T) {
+	asha := &employee.Employee{ID: 3, FirstName: "Asha", LastName: "Rao", Salary: 31000, Hired: mustDate(t, "2024-01-08")}
+
+	// The seeded UNPAID policy is not paid leave, yet its days still come out of the salary
+	policy := &leave.LeavePolicy{LeaveType: leave.TypeUnpaid, IsPaid: false}
+	if !policy.DeductsSalary() {
+		t.Fatal("Expected UNPAID leave to deduct salary")
+	}
+	casual := &leave.LeavePolicy{LeaveType: leave.TypeCasual, IsPaid: false}
+	if casual.DeductsSalary() {
+		t.Error("Expected CASUAL leave to keep full pay")
+	}
+
+	// Monday 2026-03-09 to Wednesday 2026-03-11
+	lr := leave.LeaveRequest{
+		ID:         9,
+		EmployeeID: 3,
+		LeaveType:  leave.TypeUnpaid,
+		Status:     leave.StatusApproved,
+		StartDate:  mustDate(t, "2026-03-09"),
+		EndDate:    mustDate(t, "2026-03-11"),
+		DaysCount:  3,
+		DayPart:    leave.DayPartFull,
+	}
+	deductions := payroll.LeaveDeductions(&lr, asha, nil, payroll.DefaultRatePolicy)
+	if len(deductions) != 1 || deductions[0].Kind != payroll.KindLeaveWithoutPay || deductions[0].Amount != -3000 {
+		t.Fatalf("Expected 3000 deducted as leave without pay, got %+v", deductions)
+	}
+
+	payslip := payroll.NewPayslip(asha, nil, "2026-03", deductions)
+	if payslip.GrossPay != 31000 || payslip.NetPay != 28000 {
+		t.Errorf("Expected net pay of 28000 out of 31000 gross, got %+v", payslip)
+	}
+
+	report := payroll.NewDeductionReport("2026-03", payroll.DefaultRatePolicy, deductions)
+	if report.Employees[0].Days != 3 || report.TotalAmount != 3000 {
+		t.Errorf("Expected 3 days and 3000 in the deduction report, got %+v", report)
+	}
+}
+
+// TestCheckRunnable tests which pay periods can be run
+func TestCheckRunnable(t *testing.T) {
+	today := mustDate(t, "2026-04-10")
+
+	cases := []struct {
+		period string
+		field  string
+	}{
+		{"2026-03", ""},
+		{"2026-04", ""},
+		{"2026-05", "period"},
+		{"April 2026", "period"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.period, func(t *testing.T) {
+			assertValidationField(t, payroll.CheckRunnable(c.period, today), c.field)
+		})
+	}
+}
+
+// TestCreateCorrectionRequestValidate tests validation of pay corrections
+func TestCreateCorrectionRequestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		request payroll.CreateCorrectionRequest
+		field   string
+	}{
+		{"Valid", payroll.CreateCorrectionRequest{EmployeeID: 3, PayPeriod: "2026-03", Amount: -250.5, Description: "Overpaid allowance"}, ""},
+		{"Missing employee", payroll.CreateCorrectionRequest{PayPeriod: "2026-03", Amount: 100, Description: "Bonus"}, "employee_id"},
+		{"Invalid period", payroll.CreateCorrectionRequest{EmployeeID: 3, PayPeriod: "03/2026", Amount: 100, Description: "Bonus"}, "pay_period"},
+		{"Zero amount", payroll.CreateCorrectionRequest{EmployeeID: 3, PayPeriod: "2026-03", Description: "Bonus"}, "amount"},
+		{"Fractional cents", payroll.CreateCorrectionRequest{EmployeeID: 3, PayPeriod: "2026-03", Amount: 10.005, Description: "Bonus"}, "amount"},
+		{"Missing description", payroll.CreateCorrectionRequest{EmployeeID: 3, PayPeriod: "2026-03", Amount: 100, Description: " "}, "description"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertValidationField(t, c.request.Validate(), c.field)
+		})
+	}
+}
+
+func assertValidationField(t *testing.T, err error, field string) {
+	t.Helper()
+	if field == "" {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		return
+	}
+
+	validationErr, ok := err.(*errors.ValidationError)
+	if !ok {
+		t.Fatalf("Expected a validation error for %s, got %v", field, err)
+	}
+	if _, ok := validationErr.Fields[field]; !ok {
+		t.Errorf("Expected a validation error for %s, got %v", field, validationErr.Fields)
+	}
+}
@@ -6,9 +6,15 @@ import "time"
 const (
 	RoleAdmin    = "admin"
 	RoleEmployee = "employee"
+	RoleManager  = "manager" // An employee who decides leave for their reports
 	RoleUser     = "user"
 )
 
+// IsEmployeeRole reports whether users with the role have an employee record
+func IsEmployeeRole(role string) bool {
+	return role == RoleEmployee || role == RoleManager
+}
+
 // User represents a system user
 type User struct {
 	ID           int       `db:"id" json:"id"`
@@ -25,7 +31,7 @@ type CreateUserRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=50"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
-	Role     string `json:"role" validate:"required,oneof=admin employee user"`
+	Role     string `json:"role" validate:"required,oneof=admin employee manager user"`
 }
 
 // RegisterEmployeeRequest represents an employee registration request with both user and employee details
@@ -34,9 +40,9 @@ type RegisterEmployeeRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=50"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
-	Role     string `json:"role" validate:"required,oneof=employee user"`
+	Role     string `json:"role" validate:"required,oneof=employee manager user"`
 
-	// Employee fields (required when role="employee")
+	// Employee fields (required when role="employee" or "manager")
 	FirstName     string  `json:"first_name" validate:"required_if=Role employee,min=1,max=100"`
 	LastName      string  `json:"last_name" validate:"required_if=Role employee,min=1,max=100"`
 	Phone         string  `json:"phone" validate:"required_if=Role employee,min=5,max=20"`
@@ -44,7 +50,11 @@ type RegisterEmployeeRequest struct {
 	Salary        float64 `json:"salary" validate:"required_if=Role employee,min=0"`
 	Gender        string  `json:"gender" validate:"omitempty,oneof=Male Female"` // "Male" or "Female"
 	MaritalStatus bool    `json:"marital_status"` // true = Married, false = Not Married
+	Location      string  `json:"location"`
+	Department    string  `json:"department"`
+	Team          string  `json:"team"`
 	HiredDate     *time.Time `json:"hired_date,omitempty"`
+	ManagerID     *int       `json:"manager_id,omitempty"` // Employee ID of the line manager
 }
 
 // LoginRequest represents login credentials
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+const calendarFeedColumns = `id, user_id, nonce, created_at, revoked_at`
+
+// CreateCalendarFeed revokes a user's calendar feeds and creates a new one within a transaction,
+// so a user has at most one working feed link
+func (r *LeaveRepository) CreateCalendarFeed(tx *repositories.Transaction, userID int, nonce string) (*leave.CalendarFeed, error) {
+	now := time.Now()
+	if _, err := revokeCalendarFeeds(tx.GetTx(), userID, now); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO calendar_feeds (user_id, nonce, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	q := convertPlaceholders(query)
+
+	feed := &leave.CalendarFeed{UserID: userID, Nonce: nonce, CreatedAt: now}
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, userID, nonce, now)
+		if err != nil {
+			return nil, errors.WrapError("failed to create calendar feed", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		feed.ID = int(lastID)
+
+		return feed, nil
+	}
+
+	if err := tx.GetTx().QueryRow(q, userID, nonce, now).Scan(&feed.ID); err != nil {
+		return nil, errors.WrapError("failed to create calendar feed", err)
+	}
+
+	return feed, nil
+}
+
+// GetCalendarFeed retrieves a calendar feed by ID, revoked or not
+func (r *LeaveRepository) GetCalendarFeed(id int) (*leave.CalendarFeed, error) {
+	query := `
+		SELECT ` + calendarFeedColumns + `
+		FROM calendar_feeds
+		WHERE id = $1
+	`
+
+	feed, err := scanCalendarFeed(r.db.QueryRow(convertPlaceholders(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("calendar feed not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get calendar feed", err)
+	}
+
+	return feed, nil
+}
+
+// GetActiveCalendarFeed retrieves a user's calendar feed that has not been revoked
+func (r *LeaveRepository) GetActiveCalendarFeed(userID int) (*leave.CalendarFeed, error) {
+	query := `
+		SELECT ` + calendarFeedColumns + `
+		FROM calendar_feeds
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	feed, err := scanCalendarFeed(r.db.QueryRow(convertPlaceholders(query), userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("calendar feed not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get calendar feed", err)
+	}
+
+	return feed, nil
+}
+
+// RevokeCalendarFeeds revokes all of a user's calendar feeds; it reports whether there was one
+func (r *LeaveRepository) RevokeCalendarFeeds(userID int) (bool, error) {
+	revoked, err := revokeCalendarFeeds(r.db, userID, time.Now())
+	return revoked > 0, err
+}
+
+// revokeCalendarFeeds revokes all of a user's active calendar feeds and returns how many there were
+func revokeCalendarFeeds(ex dbExecutor, userID int, at time.Time) (int64, error) {
+	result, err := ex.Exec(convertPlaceholders(`
+		UPDATE calendar_feeds SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`), at, userID)
+	if err != nil {
+		return 0, errors.WrapError("failed to revoke calendar feeds", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.WrapError("failed to get rows affected", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// NextEventSequence raises the calendar event sequence of a leave request within a transaction
+// and returns the new value, so each invite sent for it replaces the one before
+func (r *LeaveRepository) NextEventSequence(tx *repositories.Transaction, leaveRequestID int) (int, error) {
+	result, err := tx.GetTx().Exec(convertPlaceholders(`
+		UPDATE leave_requests SET event_sequence = event_sequence + 1 WHERE id = $1
+	`), leaveRequestID)
+	if err != nil {
+		return 0, errors.WrapError("failed to update leave request event sequence", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return 0, errors.NewNotFoundError("leave request not found")
+	}
+
+	var sequence int
+	err = tx.GetTx().QueryRow(convertPlaceholders(`SELECT event_sequence FROM leave_requests WHERE id = $1`), leaveRequestID).Scan(&sequence)
+	if err != nil {
+		return 0, errors.WrapError("failed to get leave request event sequence", err)
+	}
+	return sequence, nil
+}
+
+// scanCalendarFeed scans a calendar feed row selected with calendarFeedColumns
+func scanCalendarFeed(row rowScanner) (*leave.CalendarFeed, error) {
+	var feed leave.CalendarFeed
+	err := row.Scan(
+		&feed.ID,
+		&feed.UserID,
+		&feed.Nonce,
+		&feed.CreatedAt,
+		&feed.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
@@ -0,0 +1,358 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// compOffColumns selects a comp-off credit from comp_off_credits co joined with its employee e
+const compOffColumns = `co.id, co.employee_id, e.first_name, e.last_name, co.requested_by, co.worked_date,
+		       co.day_part, co.days, co.reason, co.status, co.decided_by, co.decision_comment,
+		       co.decided_at, co.expires_on, co.days_expired, co.expired_at, co.created_at`
+
+// compOffFrom is the FROM clause for compOffColumns
+const compOffFrom = `comp_off_credits co JOIN employees e ON e.id = co.employee_id`
+
+// CreateCompOffCredit records a request to be credited comp-off within a transaction
+func (r *LeaveRepository) CreateCompOffCredit(tx *repositories.Transaction, c *leave.CompOffCredit) (*leave.CompOffCredit, error) {
+	query := `
+		INSERT INTO comp_off_credits (employee_id, requested_by, worked_date, day_part, days, reason, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	args := []interface{}{
+		c.EmployeeID,
+		c.RequestedBy,
+		c.WorkedDate,
+		c.DayPart,
+		c.Days,
+		c.Reason,
+		leave.CompOffPending,
+		now,
+		now,
+	}
+
+	c.Status = leave.CompOffPending
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, args...)
+		if err != nil {
+			return nil, errors.WrapError("failed to create comp-off credit", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		c.ID = int(lastID)
+		c.CreatedAt = now
+
+		return c, nil
+	}
+
+	err := tx.GetTx().QueryRow(q, args...).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create comp-off credit", err)
+	}
+
+	return c, nil
+}
+
+// HasCompOffCredit reports whether an employee already has a pending or approved comp-off
+// credit for a date, within a transaction
+func (r *LeaveRepository) HasCompOffCredit(tx *repositories.Transaction, employeeID int, workedDate time.Time) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM comp_off_credits
+		WHERE employee_id = $1 AND worked_date = $2 AND status IN ($3, $4)
+	`
+	q := convertPlaceholders(query)
+
+	var count int
+	err := tx.GetTx().QueryRow(q, employeeID, workedDate, leave.CompOffPending, leave.CompOffApproved).Scan(&count)
+	if err != nil {
+		return false, errors.WrapError("failed to check comp-off credits", err)
+	}
+
+	return count > 0, nil
+}
+
+// LockCompOffCredit retrieves a comp-off credit by ID and locks it until the transaction ends
+func (r *LeaveRepository) LockCompOffCredit(tx *repositories.Transaction, id int) (*leave.CompOffCredit, error) {
+	if err := lockRow(tx.GetTx(), "comp_off_credits", "id = $1", id); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + compOffColumns + `
+		FROM ` + compOffFrom + `
+		WHERE co.id = $1
+	`
+	q := convertPlaceholders(query)
+
+	c, err := scanCompOffCredit(tx.GetTx().QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("comp-off credit not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get comp-off credit", err)
+	}
+
+	return c, nil
+}
+
+// ListCompOffCredits retrieves comp-off credits, newest first, optionally filtered by status
+func (r *LeaveRepository) ListCompOffCredits(status string) ([]leave.CompOffCredit, error) {
+	query := `
+		SELECT ` + compOffColumns + `
+		FROM ` + compOffFrom + `
+	`
+	args := []interface{}{}
+	if status != "" {
+		query += ` WHERE co.status = $1`
+		args = append(args, status)
+	}
+	query += ` ORDER BY co.created_at DESC, co.id DESC`
+
+	return queryCompOffCredits(r.db, convertPlaceholders(query), args...)
+}
+
+// GetEmployeeCompOffCredits retrieves an employee's comp-off credits, latest day worked first
+func (r *LeaveRepository) GetEmployeeCompOffCredits(employeeID int) ([]leave.CompOffCredit, error) {
+	query := `
+		SELECT ` + compOffColumns + `
+		FROM ` + compOffFrom + `
+		WHERE co.employee_id = $1
+		ORDER BY co.worked_date DESC, co.id DESC
+	`
+
+	return queryCompOffCredits(r.db, convertPlaceholders(query), employeeID)
+}
+
+// DecideCompOffCredit records the decision on a comp-off credit within a transaction
+func (r *LeaveRepository) DecideCompOffCredit(tx *repositories.Transaction, c *leave.CompOffCredit) error {
+	query := `
+		UPDATE comp_off_credits
+		SET status = $1, decided_by = $2, decision_comment = $3, decided_at = $4,
+		    expires_on = $5, updated_at = $6
+		WHERE id = $7 AND status = $8
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	result, err := tx.GetTx().Exec(q,
+		c.Status,
+		c.DecidedBy,
+		c.DecisionComment,
+		now,
+		c.ExpiresOn,
+		now,
+		c.ID,
+		leave.CompOffPending,
+	)
+	if err != nil {
+		return errors.WrapError("failed to update comp-off credit", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewValidationError().AddField("status", "comp-off request has already been decided")
+	}
+
+	c.DecidedAt = &now
+	return nil
+}
+
+// CreditCompOff adds the days of an approved comp-off credit to the employee's COMP_OFF balance
+// within a transaction, creating the balance if the employee has none yet
+func (r *LeaveRepository) CreditCompOff(tx *repositories.Transaction, c *leave.CompOffCredit, actorUserID int) error {
+	ex := tx.GetTx()
+	now := time.Now()
+
+	_, err := ex.Exec(convertPlaceholders(`
+		INSERT INTO leave_balances (employee_id, leave_type, leave_year, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (employee_id, leave_type) DO NOTHING
+	`), c.EmployeeID, leave.TypeCompOff, now.Year(), now, now)
+	if err != nil {
+		return errors.WrapError("failed to create leave balance", err)
+	}
+
+	if err := lockLeaveBalance(ex, c.EmployeeID, leave.TypeCompOff); err != nil {
+		return err
+	}
+	return insertLedgerEntry(ex, leave.NewCompOffCreditEntry(c, actorUserID))
+}
+
+// GetDueCompOffEmployeeIDs retrieves the employees with approved comp-off credits that expire
+// on or before a date and have not been expired yet
+func (r *LeaveRepository) GetDueCompOffEmployeeIDs(asOf time.Time) ([]int, error) {
+	query := `
+		SELECT DISTINCT employee_id
+		FROM comp_off_credits
+		WHERE status = $1 AND expired_at IS NULL AND expires_on <= $2
+		ORDER BY employee_id
+	`
+
+	rows, err := r.db.Query(convertPlaceholders(query), leave.CompOffApproved, asOf)
+	if err != nil {
+		return nil, errors.WrapError("failed to query due comp-off credits", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.WrapError("failed to scan employee id", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating due comp-off credits", err)
+	}
+
+	return ids, nil
+}
+
+// LockCompOffBalance locks an employee's COMP_OFF balance and returns it with the approved
+// credits that have not been expired yet, within a transaction
+func (r *LeaveRepository) LockCompOffBalance(tx *repositories.Transaction, employeeID int) ([]leave.CompOffCredit, float64, error) {
+	ex := tx.GetTx()
+	if err := lockLeaveBalance(ex, employeeID, leave.TypeCompOff); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + compOffColumns + `
+		FROM ` + compOffFrom + `
+		WHERE co.employee_id = $1 AND co.status = $2 AND co.expired_at IS NULL
+		ORDER BY co.expires_on, co.id
+	`
+	credits, err := queryCompOffCredits(ex, convertPlaceholders(query), employeeID, leave.CompOffApproved)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	balance, err := ledgerBalance(ex, employeeID, leave.TypeCompOff)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return credits, balance, nil
+}
+
+// ExpireCompOffCredit marks a due comp-off credit as expired within a transaction, removing the
+// days of it that lapse from the COMP_OFF balance. The balance must already be locked.
+func (r *LeaveRepository) ExpireCompOffCredit(tx *repositories.Transaction, c *leave.CompOffCredit, days float64) error {
+	ex := tx.GetTx()
+	if days > 0 {
+		if err := insertLedgerEntry(ex, leave.NewCompOffExpiryEntry(c, days)); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	result, err := ex.Exec(convertPlaceholders(`
+		UPDATE comp_off_credits
+		SET days_expired = $1, expired_at = $2, updated_at = $3
+		WHERE id = $4 AND expired_at IS NULL
+	`), days, now, now, c.ID)
+	if err != nil {
+		return errors.WrapError("failed to expire comp-off credit", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewValidationError().AddField("status", "comp-off credit has already been expired")
+	}
+
+	c.DaysExpired = days
+	c.ExpiredAt = &now
+	return nil
+}
+
+// queryCompOffCredits runs a query selecting compOffColumns and scans its rows
+func queryCompOffCredits(q dbQuerier, query string, args ...interface{}) ([]leave.CompOffCredit, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query comp-off credits", err)
+	}
+	defer rows.Close()
+
+	credits := []leave.CompOffCredit{}
+
+	for rows.Next() {
+		c, err := scanCompOffCredit(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan comp-off credit", err)
+		}
+		credits = append(credits, *c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating comp-off credits", err)
+	}
+
+	return credits, nil
+}
+
+// scanCompOffCredit scans a comp-off credit row selected with compOffColumns
+func scanCompOffCredit(row rowScanner) (*leave.CompOffCredit, error) {
+	var c leave.CompOffCredit
+	var firstName, lastName string
+	var decidedBy sql.NullInt64
+	var decidedAt, expiresOn, expiredAt sql.NullTime
+	err := row.Scan(
+		&c.ID,
+		&c.EmployeeID,
+		&firstName,
+		&lastName,
+		&c.RequestedBy,
+		&c.WorkedDate,
+		&c.DayPart,
+		&c.Days,
+		&c.Reason,
+		&c.Status,
+		&decidedBy,
+		&c.DecisionComment,
+		&decidedAt,
+		&expiresOn,
+		&c.DaysExpired,
+		&expiredAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.EmployeeName = firstName + " " + lastName
+	if decidedBy.Valid {
+		id := int(decidedBy.Int64)
+		c.DecidedBy = &id
+	}
+	if decidedAt.Valid {
+		c.DecidedAt = &decidedAt.Time
+	}
+	if expiresOn.Valid {
+		c.ExpiresOn = &expiresOn.Time
+	}
+	if expiredAt.Valid {
+		c.ExpiredAt = &expiredAt.Time
+	}
+	return &c, nil
+}
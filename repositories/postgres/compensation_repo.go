@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// compensationColumns selects a compensation record from employee_compensation
+const compensationColumns = `id, employee_id, effective_date, salary, position, reason, created_by, applied_at, created_at`
+
+// CreateCompensationRecord records a salary or position change within a transaction
+func (r *EmployeeRepository) CreateCompensationRecord(tx *repositories.Transaction, rec *employee.CompensationRecord) error {
+	query := `
+		INSERT INTO employee_compensation (employee_id, effective_date, salary, position, reason, created_by, applied_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	q := convertPlaceholders(query)
+
+	rec.CreatedAt = time.Now()
+	args := []interface{}{rec.EmployeeID, rec.EffectiveDate, rec.Salary, rec.Position, rec.Reason, rec.CreatedBy, rec.AppliedAt, rec.CreatedAt}
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, args...)
+		if err != nil {
+			return errors.WrapError("failed to create compensation record", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return errors.WrapError("failed to get last insert id", err)
+		}
+		rec.ID = int(lastID)
+		return nil
+	}
+
+	if err := tx.GetTx().QueryRow(q, args...).Scan(&rec.ID); err != nil {
+		return errors.WrapError("failed to create compensation record", err)
+	}
+	return nil
+}
+
+// GetCompensationHistory retrieves the compensation records of an employee in effective date order
+func (r *EmployeeRepository) GetCompensationHistory(employeeID int) (employee.CompensationHistory, error) {
+	query := `
+		SELECT ` + compensationColumns + `
+		FROM employee_compensation
+		WHERE employee_id = $1
+		ORDER BY effective_date, id
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), employeeID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query compensation history", err)
+	}
+	return scanCompensationRecords(rows)
+}
+
+// LockCompensationHistory locks an employee's row until the transaction ends, so their
+// compensation changes are recorded and applied one at a time, and retrieves their records
+func (r *EmployeeRepository) LockCompensationHistory(tx *repositories.Transaction, employeeID int) (employee.CompensationHistory, error) {
+	if err := lockRow(tx.GetTx(), "employees", "id = $1", employeeID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + compensationColumns + `
+		FROM employee_compensation
+		WHERE employee_id = $1
+		ORDER BY effective_date, id
+	`
+	rows, err := tx.GetTx().Query(convertPlaceholders(query), employeeID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query compensation history", err)
+	}
+	return scanCompensationRecords(rows)
+}
+
+// GetCompensationHistories retrieves the compensation records of every employee, keyed by
+// employee ID
+func (r *EmployeeRepository) GetCompensationHistories() (map[int]employee.CompensationHistory, error) {
+	query := `SELECT ` + compensationColumns + ` FROM employee_compensation ORDER BY employee_id, effective_date, id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, errors.WrapError("failed to query compensation histories", err)
+	}
+	records, err := scanCompensationRecords(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := map[int]employee.CompensationHistory{}
+	for _, rec := range records {
+		histories[rec.EmployeeID] = append(histories[rec.EmployeeID], rec)
+	}
+	return histories, nil
+}
+
+// GetDueCompensationEmployees retrieves the IDs of employees with scheduled changes that take
+// effect on or before a date
+func (r *EmployeeRepository) GetDueCompensationEmployees(date time.Time) ([]int, error) {
+	query := `
+		SELECT DISTINCT employee_id
+		FROM employee_compensation
+		WHERE applied_at IS NULL AND effective_date <= $1
+		ORDER BY employee_id
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), date)
+	if err != nil {
+		return nil, errors.WrapError("failed to query due compensation changes", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.WrapError("failed to scan employee ID", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating due compensation changes", err)
+	}
+	return ids, nil
+}
+
+// ApplyCompensation sets the salary and position on an employee record within a transaction and
+// marks their changes that took effect by the given date as applied
+func (r *EmployeeRepository) ApplyCompensation(tx *repositories.Transaction, employeeID int, c employee.Compensation, date time.Time) error {
+	now := time.Now()
+
+	query := `UPDATE employees SET salary = $1, position = $2, updated_at = $3 WHERE id = $4`
+	if _, err := tx.GetTx().Exec(convertPlaceholders(query), c.Salary, c.Position, now, employeeID); err != nil {
+		return errors.WrapError("failed to update employee compensation", err)
+	}
+
+	query = `
+		UPDATE employee_compensation
+		SET applied_at = $1
+		WHERE employee_id = $2 AND applied_at IS NULL AND effective_date <= $3
+	`
+	if _, err := tx.GetTx().Exec(convertPlaceholders(query), now, employeeID, date); err != nil {
+		return errors.WrapError("failed to mark compensation changes applied", err)
+	}
+	return nil
+}
+
+// scanCompensationRecords scans compensation rows selected with compensationColumns
+func scanCompensationRecords(rows *sql.Rows) (employee.CompensationHistory, error) {
+	defer rows.Close()
+
+	history := employee.CompensationHistory{}
+	for rows.Next() {
+		var rec employee.CompensationRecord
+		var salary sql.NullFloat64
+		var position sql.NullString
+		var createdBy sql.NullInt64
+		var appliedAt sql.NullTime
+		err := rows.Scan(
+			&rec.ID,
+			&rec.EmployeeID,
+			&rec.EffectiveDate,
+			&salary,
+			&position,
+			&rec.Reason,
+			&createdBy,
+			&appliedAt,
+			&rec.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan compensation record", err)
+		}
+		if salary.Valid {
+			rec.Salary = &salary.Float64
+		}
+		if position.Valid {
+			rec.Position = &position.String
+		}
+		if createdBy.Valid {
+			id := int(createdBy.Int64)
+			rec.CreatedBy = &id
+		}
+		if appliedAt.Valid {
+			rec.AppliedAt = &appliedAt.Time
+		}
+		history = append(history, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating compensation records", err)
+	}
+	return history, nil
+}
@@ -31,8 +31,8 @@ func NewEmployeeRepository(db *sql.DB) *EmployeeRepository {
 // CreateEmployee creates a new employee in the database
 func (r *EmployeeRepository) CreateEmployee(emp *employee.Employee) (*employee.Employee, error) {
 	query := `
-		INSERT INTO employees (user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, hired_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO employees (user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, location, hired_date, created_at, updated_at, manager_id, department, team)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id, created_at, updated_at
 	`
 	q := convertPlaceholders(query)
@@ -51,9 +51,13 @@ func (r *EmployeeRepository) CreateEmployee(emp *employee.Employee) (*employee.E
 			emp.Salary,
 			emp.Gender,
 			emp.MaritalStatus,
+			emp.Location,
 			emp.Hired,
 			now,
 			now,
+			emp.ManagerID,
+			emp.Department,
+			emp.Team,
 		)
 		if err != nil {
 			return nil, errors.WrapError("failed to create employee", err)
@@ -86,9 +90,13 @@ func (r *EmployeeRepository) CreateEmployee(emp *employee.Employee) (*employee.E
 		emp.Salary,
 		emp.Gender,
 		emp.MaritalStatus,
+		emp.Location,
 		emp.Hired,
 		now,
 		now,
+		emp.ManagerID,
+		emp.Department,
+		emp.Team,
 	).Scan(&emp.ID, &emp.CreatedAt, &emp.UpdatedAt)
 
 	if err != nil {
@@ -101,7 +109,7 @@ func (r *EmployeeRepository) CreateEmployee(emp *employee.Employee) (*employee.E
 // GetEmployeeByID retrieves an employee by ID
 func (r *EmployeeRepository) GetEmployeeByID(id int) (*employee.Employee, error) {
 	query := `
-		SELECT id, user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, hired_date, created_at, updated_at
+		SELECT id, user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, location, hired_date, created_at, updated_at, manager_id, department, team
 		FROM employees
 		WHERE id = $1
 	`
@@ -119,9 +127,13 @@ func (r *EmployeeRepository) GetEmployeeByID(id int) (*employee.Employee, error)
 		&emp.Salary,
 		&emp.Gender,
 		&emp.MaritalStatus,
+		&emp.Location,
 		&emp.Hired,
 		&emp.CreatedAt,
 		&emp.UpdatedAt,
+		&emp.ManagerID,
+		&emp.Department,
+		&emp.Team,
 	)
 
 	if err == sql.ErrNoRows {
@@ -138,7 +150,7 @@ func (r *EmployeeRepository) GetEmployeeByID(id int) (*employee.Employee, error)
 // GetEmployeeByUserID retrieves an employee by user_id
 func (r *EmployeeRepository) GetEmployeeByUserID(userID int) (*employee.Employee, error) {
 	query := `
-		SELECT id, user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, hired_date, created_at, updated_at
+		SELECT id, user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, location, hired_date, created_at, updated_at, manager_id, department, team
 		FROM employees
 		WHERE user_id = $1
 	`
@@ -156,9 +168,13 @@ func (r *EmployeeRepository) GetEmployeeByUserID(userID int) (*employee.Employee
 		&emp.Salary,
 		&emp.Gender,
 		&emp.MaritalStatus,
+		&emp.Location,
 		&emp.Hired,
 		&emp.CreatedAt,
 		&emp.UpdatedAt,
+		&emp.ManagerID,
+		&emp.Department,
+		&emp.Team,
 	)
 
 	if err == sql.ErrNoRows {
@@ -175,7 +191,7 @@ func (r *EmployeeRepository) GetEmployeeByUserID(userID int) (*employee.Employee
 // GetAllEmployees retrieves all employees with pagination
 func (r *EmployeeRepository) GetAllEmployees(limit, offset int) ([]*employee.Employee, error) {
 	query := `
-		SELECT id, user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, hired_date, created_at, updated_at
+		SELECT id, user_id, first_name, last_name, email, phone, position, salary, gender, marital_status, location, hired_date, created_at, updated_at, manager_id, department, team
 		FROM employees
 		ORDER BY id DESC
 		LIMIT $1 OFFSET $2
@@ -204,9 +220,13 @@ func (r *EmployeeRepository) GetAllEmployees(limit, offset int) ([]*employee.Emp
 			&emp.Salary,
 			&emp.Gender,
 			&emp.MaritalStatus,
+			&emp.Location,
 			&emp.Hired,
 			&emp.CreatedAt,
 			&emp.UpdatedAt,
+			&emp.ManagerID,
+			&emp.Department,
+			&emp.Team,
 		)
 
 		if err != nil {
@@ -257,13 +277,29 @@ func (r *EmployeeRepository) UpdateEmployee(id int, updates *employee.UpdateEmpl
 	if updates.MaritalStatus != nil {
 		emp.MaritalStatus = *updates.MaritalStatus
 	}
+	if updates.Location != nil {
+		emp.Location = *updates.Location
+	}
+	if updates.Department != nil {
+		emp.Department = *updates.Department
+	}
+	if updates.Team != nil {
+		emp.Team = *updates.Team
+	}
+	if updates.ManagerID != nil {
+		if *updates.ManagerID == 0 {
+			emp.ManagerID = nil
+		} else {
+			emp.ManagerID = updates.ManagerID
+		}
+	}
 
 	emp.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE employees
-		SET first_name = $1, last_name = $2, email = $3, phone = $4, position = $5, salary = $6, gender = $7, marital_status = $8, updated_at = $9
-		WHERE id = $10
+		SET first_name = $1, last_name = $2, email = $3, phone = $4, position = $5, salary = $6, gender = $7, marital_status = $8, location = $9, updated_at = $10, manager_id = $11, department = $12, team = $13
+		WHERE id = $14
 		RETURNING updated_at
 	`
 	q := convertPlaceholders(query)
@@ -279,7 +315,11 @@ func (r *EmployeeRepository) UpdateEmployee(id int, updates *employee.UpdateEmpl
 			emp.Salary,
 			emp.Gender,
 			emp.MaritalStatus,
+			emp.Location,
 			emp.UpdatedAt,
+			emp.ManagerID,
+			emp.Department,
+			emp.Team,
 			id,
 		)
 		if err != nil {
@@ -299,7 +339,11 @@ func (r *EmployeeRepository) UpdateEmployee(id int, updates *employee.UpdateEmpl
 		emp.Salary,
 		emp.Gender,
 		emp.MaritalStatus,
+		emp.Location,
 		emp.UpdatedAt,
+		emp.ManagerID,
+		emp.Department,
+		emp.Team,
 		id,
 	).Scan(&emp.UpdatedAt)
 
@@ -466,32 +510,58 @@ func (r *EmployeeRepository) GetSearchEmployeeCount(query string) (int, error) {
 	return count, nil
 }
 
-// UpdateEmployeeSalary updates only the salary field of an employee
-func (r *EmployeeRepository) UpdateEmployeeSalary(id int, newSalary float64) error {
+
+
+// GetReportIDs returns the IDs of the direct and indirect reports of a manager
+func (r *EmployeeRepository) GetReportIDs(managerID int) ([]int, error) {
 	query := `
-		UPDATE employees
-		SET salary = $1, updated_at = $2
-		WHERE id = $3
+		WITH RECURSIVE reports(id) AS (
+			SELECT id FROM employees WHERE manager_id = $1
+			UNION
+			SELECT e.id FROM employees e JOIN reports rp ON e.manager_id = rp.id
+		)
+		SELECT id FROM reports ORDER BY id
 	`
 	q := convertPlaceholders(query)
 
-	now := time.Now()
-
-	result, err := r.db.Exec(q, newSalary, now, id)
+	rows, err := r.db.Query(q, managerID)
 	if err != nil {
-		return errors.WrapError("failed to update employee salary", err)
+		return nil, errors.WrapError("failed to query reports", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return errors.WrapError("failed to get rows affected", err)
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.WrapError("failed to scan report", err)
+		}
+		ids = append(ids, id)
 	}
 
-	if rowsAffected == 0 {
-		return errors.NewNotFoundError("employee not found")
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating reports", err)
 	}
 
-	return nil
+	return ids, nil
 }
 
+// IsReportOf reports whether an employee reports to a manager directly or indirectly
+func (r *EmployeeRepository) IsReportOf(employeeID, managerID int) (bool, error) {
+	query := `
+		WITH RECURSIVE line(id, manager_id) AS (
+			SELECT id, manager_id FROM employees WHERE id = $1
+			UNION
+			SELECT e.id, e.manager_id FROM employees e JOIN line l ON e.id = l.manager_id
+		)
+		SELECT COUNT(*) FROM line WHERE manager_id = $2
+	`
+	q := convertPlaceholders(query)
+
+	var count int
+	if err := r.db.QueryRow(q, employeeID, managerID).Scan(&count); err != nil {
+		return false, errors.WrapError("failed to check reporting line", err)
+	}
 
+	return count > 0, nil
+}
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/holiday"
+	"employee-service/utils/helpers"
+)
+
+// HolidayRepository handles database operations for the holiday calendar
+type HolidayRepository struct {
+	db *sql.DB
+}
+
+// NewHolidayRepository creates a new holiday repository
+func NewHolidayRepository(db *sql.DB) *HolidayRepository {
+	return &HolidayRepository{db: db}
+}
+
+// CreateHoliday creates a new holiday
+func (r *HolidayRepository) CreateHoliday(h *holiday.Holiday) (*holiday.Holiday, error) {
+	query := `
+		INSERT INTO holidays (name, holiday_date, year, location, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	h.Year = h.Date.Year()
+
+	if helpers.DBType == "sqlite" {
+		res, err := r.db.Exec(q, h.Name, h.Date, h.Year, h.Location, now, now)
+		if err != nil {
+			return nil, errors.WrapError("failed to create holiday", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		h.ID = int(lastID)
+		h.CreatedAt = now
+		h.UpdatedAt = now
+
+		return h, nil
+	}
+
+	err := r.db.QueryRow(q, h.Name, h.Date, h.Year, h.Location, now, now).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create holiday", err)
+	}
+
+	return h, nil
+}
+
+// GetHoliday retrieves a holiday by ID
+func (r *HolidayRepository) GetHoliday(id int) (*holiday.Holiday, error) {
+	query := `
+		SELECT id, name, holiday_date, year, location, created_at, updated_at
+		FROM holidays
+		WHERE id = $1
+	`
+	q := convertPlaceholders(query)
+
+	var h holiday.Holiday
+	err := r.db.QueryRow(q, id).Scan(
+		&h.ID,
+		&h.Name,
+		&h.Date,
+		&h.Year,
+		&h.Location,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("holiday not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get holiday", err)
+	}
+
+	return &h, nil
+}
+
+// ListHolidays retrieves holidays with optional year and location filters.
+// Filtering by location also returns holidays that apply to every location.
+func (r *HolidayRepository) ListHolidays(year int, location string) ([]holiday.Holiday, error) {
+	query := `
+		SELECT id, name, holiday_date, year, location, created_at, updated_at
+		FROM holidays
+		WHERE 1 = 1
+	`
+	var args []interface{}
+
+	if year > 0 {
+		args = append(args, year)
+		query += fmt.Sprintf(" AND year = $%d", len(args))
+	}
+	if location != "" {
+		args = append(args, location)
+		query += fmt.Sprintf(" AND (location = '' OR location = $%d)", len(args))
+	}
+	query += " ORDER BY holiday_date"
+
+	return r.queryHolidays(convertPlaceholders(query), args...)
+}
+
+// GetHolidaysInRange retrieves the holidays between two dates (inclusive)
+// that apply to the given location
+func (r *HolidayRepository) GetHolidaysInRange(startDate, endDate time.Time, location string) ([]holiday.Holiday, error) {
+	query := `
+		SELECT id, name, holiday_date, year, location, created_at, updated_at
+		FROM holidays
+		WHERE holiday_date >= $1 AND holiday_date <= $2
+		  AND (location = '' OR location = $3)
+		ORDER BY holiday_date
+	`
+	q := convertPlaceholders(query)
+
+	return r.queryHolidays(q, startDate, endDate, location)
+}
+
+// UpdateHoliday updates an existing holiday
+func (r *HolidayRepository) UpdateHoliday(h *holiday.Holiday) error {
+	query := `
+		UPDATE holidays
+		SET name = $1, holiday_date = $2, year = $3, location = $4, updated_at = $5
+		WHERE id = $6
+	`
+	q := convertPlaceholders(query)
+
+	h.Year = h.Date.Year()
+	h.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(q, h.Name, h.Date, h.Year, h.Location, h.UpdatedAt, h.ID)
+	if err != nil {
+		return errors.WrapError("failed to update holiday", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("holiday not found")
+	}
+
+	return nil
+}
+
+// DeleteHoliday deletes a holiday
+func (r *HolidayRepository) DeleteHoliday(id int) error {
+	query := "DELETE FROM holidays WHERE id = $1"
+	q := convertPlaceholders(query)
+
+	result, err := r.db.Exec(q, id)
+	if err != nil {
+		return errors.WrapError("failed to delete holiday", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("holiday not found")
+	}
+
+	return nil
+}
+
+// queryHolidays runs a holiday SELECT and scans the rows
+func (r *HolidayRepository) queryHolidays(query string, args ...interface{}) ([]holiday.Holiday, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query holidays", err)
+	}
+	defer rows.Close()
+
+	holidays := []holiday.Holiday{}
+
+	for rows.Next() {
+		var h holiday.Holiday
+
+		err := rows.Scan(
+			&h.ID,
+			&h.Name,
+			&h.Date,
+			&h.Year,
+			&h.Location,
+			&h.CreatedAt,
+			&h.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan holiday", err)
+		}
+
+		holidays = append(holidays, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating holidays", err)
+	}
+
+	return holidays, nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// LeaveAccrualRepository handles database operations for the leave accrual ledger
+type LeaveAccrualRepository struct {
+	db *sql.DB
+}
+
+// NewLeaveAccrualRepository creates a new leave accrual repository
+func NewLeaveAccrualRepository(db *sql.DB) *LeaveAccrualRepository {
+	return &LeaveAccrualRepository{db: db}
+}
+
+// CreditAccrual records an accrual and credits its days to the employee's leave ledger,
+// in a single transaction.
+// It returns false without changing anything if the period was already accrued.
+func (r *LeaveAccrualRepository) CreditAccrual(entry *leave.LeaveAccrual) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, errors.WrapError("failed to begin accrual transaction", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	// Make sure a balance row exists to credit
+	_, err = tx.Exec(convertPlaceholders(`
+		INSERT INTO leave_balances (employee_id, leave_type, leave_year, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (employee_id, leave_type) DO NOTHING
+	`), entry.EmployeeID, entry.LeaveType, entry.Year(), now, now)
+	if err != nil {
+		return false, errors.WrapError("failed to create leave balance", err)
+	}
+
+	entry.CreditedDays = entry.Days
+	entry.CreatedAt = now
+
+	result, err := tx.Exec(convertPlaceholders(`
+		INSERT INTO leave_accruals (employee_id, leave_type, period, days, credited_days, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (employee_id, leave_type, period) DO NOTHING
+	`), entry.EmployeeID, entry.LeaveType, entry.Period, entry.Days, entry.CreditedDays, now)
+	if err != nil {
+		return false, errors.WrapError("failed to create leave accrual", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if entry.CreditedDays > 0 {
+		err = insertLedgerEntry(tx, &leave.LeaveLedgerEntry{
+			EmployeeID:    entry.EmployeeID,
+			LeaveType:     entry.LeaveType,
+			EntryType:     leave.LedgerCredit,
+			Days:          entry.CreditedDays,
+			Reference:     "accrual:" + entry.Period,
+			Description:   "Accrual for " + entry.Period,
+			EffectiveDate: entry.PeriodStart(),
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.WrapError("failed to commit accrual transaction", err)
+	}
+
+	return true, nil
+}
+
+// GetEmployeeAccruals retrieves the accrual ledger for an employee, newest first
+func (r *LeaveAccrualRepository) GetEmployeeAccruals(employeeID int) ([]leave.LeaveAccrual, error) {
+	query := `
+		SELECT id, employee_id, leave_type, period, days, credited_days, created_at
+		FROM leave_accruals
+		WHERE employee_id = $1
+		ORDER BY period DESC, leave_type
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, employeeID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave accruals", err)
+	}
+	defer rows.Close()
+
+	accruals := []leave.LeaveAccrual{}
+
+	for rows.Next() {
+		var a leave.LeaveAccrual
+		err := rows.Scan(
+			&a.ID,
+			&a.EmployeeID,
+			&a.LeaveType,
+			&a.Period,
+			&a.Days,
+			&a.CreditedDays,
+			&a.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave accrual", err)
+		}
+		accruals = append(accruals, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave accruals", err)
+	}
+
+	return accruals, nil
+}
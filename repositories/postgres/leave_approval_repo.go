@@ -0,0 +1,736 @@
+package postgres
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// LeaveApprovalRepository handles database operations for approval rules and the
+// approval chains of leave requests
+type LeaveApprovalRepository struct {
+	db *sql.DB
+}
+
+// NewLeaveApprovalRepository creates a new leave approval repository
+func NewLeaveApprovalRepository(db *sql.DB) *LeaveApprovalRepository {
+	return &LeaveApprovalRepository{db: db}
+}
+
+const approvalRuleColumns = `id, step_order, approver_role, over_days, leave_types, is_active, created_at, updated_at`
+
+const leaveApprovalColumns = `id, leave_request_id, step, approver_role, approver_user_id, on_behalf_of_user_id, decision, comment, decided_at, reminded_at, escalated_at, info_requested_at, paused_seconds, created_at`
+
+const delegationColumns = `id, delegator_user_id, delegate_user_id, start_date, end_date, reason, source, leave_request_id, revoked_at, created_at`
+
+// ListRules retrieves the approval rules in step order, optionally only the active ones
+func (r *LeaveApprovalRepository) ListRules(activeOnly bool) ([]leave.ApprovalRule, error) {
+	query := `
+		SELECT ` + approvalRuleColumns + `
+		FROM leave_approval_rules
+	`
+	var args []interface{}
+	if activeOnly {
+		query += " WHERE is_active = $1"
+		args = append(args, true)
+	}
+	query += " ORDER BY step_order"
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query approval rules", err)
+	}
+	defer rows.Close()
+
+	rules := []leave.ApprovalRule{}
+
+	for rows.Next() {
+		rule, err := scanApprovalRule(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan approval rule", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating approval rules", err)
+	}
+
+	return rules, nil
+}
+
+// GetRule retrieves an approval rule by ID
+func (r *LeaveApprovalRepository) GetRule(id int) (*leave.ApprovalRule, error) {
+	query := `
+		SELECT ` + approvalRuleColumns + `
+		FROM leave_approval_rules
+		WHERE id = $1
+	`
+	q := convertPlaceholders(query)
+
+	rule, err := scanApprovalRule(r.db.QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("approval rule not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get approval rule", err)
+	}
+
+	return rule, nil
+}
+
+// CreateRule creates a new approval rule
+func (r *LeaveApprovalRepository) CreateRule(rule *leave.ApprovalRule) (*leave.ApprovalRule, error) {
+	query := `
+		INSERT INTO leave_approval_rules (step_order, approver_role, over_days, leave_types, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	args := []interface{}{
+		rule.StepOrder,
+		rule.ApproverRole,
+		rule.OverDays,
+		encodeLeaveTypes(rule.LeaveTypes),
+		rule.IsActive,
+		now,
+		now,
+	}
+
+	if helpers.DBType == "sqlite" {
+		res, err := r.db.Exec(q, args...)
+		if err != nil {
+			return nil, errors.WrapError("failed to create approval rule", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		rule.ID = int(lastID)
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+
+		return rule, nil
+	}
+
+	err := r.db.QueryRow(q, args...).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create approval rule", err)
+	}
+
+	return rule, nil
+}
+
+// UpdateRule replaces an existing approval rule
+func (r *LeaveApprovalRepository) UpdateRule(rule *leave.ApprovalRule) error {
+	query := `
+		UPDATE leave_approval_rules
+		SET step_order = $1, approver_role = $2, over_days = $3, leave_types = $4, is_active = $5, updated_at = $6
+		WHERE id = $7
+	`
+	q := convertPlaceholders(query)
+
+	rule.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(q,
+		rule.StepOrder,
+		rule.ApproverRole,
+		rule.OverDays,
+		encodeLeaveTypes(rule.LeaveTypes),
+		rule.IsActive,
+		rule.UpdatedAt,
+		rule.ID,
+	)
+	if err != nil {
+		return errors.WrapError("failed to update approval rule", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("approval rule not found")
+	}
+
+	return nil
+}
+
+// DeleteRule deletes an approval rule. Chains already built for leave requests are kept.
+func (r *LeaveApprovalRepository) DeleteRule(id int) error {
+	query := "DELETE FROM leave_approval_rules WHERE id = $1"
+	q := convertPlaceholders(query)
+
+	result, err := r.db.Exec(q, id)
+	if err != nil {
+		return errors.WrapError("failed to delete approval rule", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("approval rule not found")
+	}
+
+	return nil
+}
+
+// GetApprovals retrieves the approval chain of a leave request in step order
+func (r *LeaveApprovalRepository) GetApprovals(leaveRequestID int) ([]leave.LeaveApproval, error) {
+	query := `
+		SELECT ` + leaveApprovalColumns + `
+		FROM leave_approvals
+		WHERE leave_request_id = $1
+		ORDER BY step
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, leaveRequestID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave approvals", err)
+	}
+	defer rows.Close()
+
+	approvals := []leave.LeaveApproval{}
+
+	for rows.Next() {
+		a, err := scanLeaveApproval(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave approval", err)
+		}
+		approvals = append(approvals, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave approvals", err)
+	}
+
+	return approvals, nil
+}
+
+// GetCurrentApprovals retrieves the step awaiting a decision for every pending leave request,
+// keyed by leave request ID
+func (r *LeaveApprovalRepository) GetCurrentApprovals() (map[int]leave.LeaveApproval, error) {
+	query := `
+		SELECT la.id, la.leave_request_id, la.step, la.approver_role, la.approver_user_id,
+		       la.on_behalf_of_user_id, la.decision, la.comment, la.decided_at, la.reminded_at, la.escalated_at,
+		       la.info_requested_at, la.paused_seconds, la.created_at
+		FROM leave_approvals la
+		JOIN leave_requests lr ON lr.id = la.leave_request_id
+		WHERE lr.status = $1 AND la.decision = $2
+		ORDER BY la.leave_request_id, la.step
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, leave.StatusPending, leave.DecisionPending)
+	if err != nil {
+		return nil, errors.WrapError("failed to query pending approvals", err)
+	}
+	defer rows.Close()
+
+	current := map[int]leave.LeaveApproval{}
+
+	for rows.Next() {
+		a, err := scanLeaveApproval(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave approval", err)
+		}
+		if _, ok := current[a.LeaveRequestID]; !ok {
+			current[a.LeaveRequestID] = *a
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating pending approvals", err)
+	}
+
+	return current, nil
+}
+
+// DecideApproval records the decision of a pending approval step within a transaction. onBehalfOfUserID is the
+// delegator when the approver acts as their delegate. It fails if the step was decided concurrently.
+func (r *LeaveApprovalRepository) DecideApproval(tx *repositories.Transaction, approvalID int, approverUserID int, onBehalfOfUserID *int, decision leave.ApprovalDecision, comment string) error {
+	query := `
+		UPDATE leave_approvals
+		SET approver_user_id = $1, on_behalf_of_user_id = $2, decision = $3, comment = $4, decided_at = $5
+		WHERE id = $6 AND decision = $7
+	`
+	q := convertPlaceholders(query)
+
+	result, err := tx.GetTx().Exec(q, approverUserID, onBehalfOfUserID, decision, comment, time.Now(), approvalID, leave.DecisionPending)
+	if err != nil {
+		return errors.WrapError("failed to record approval decision", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewValidationError().AddField("status", "this approval step has already been decided")
+	}
+
+	return nil
+}
+
+// MarkReminded records that the approvers of a pending step were reminded. It returns false if the
+// step was already reminded, escalated or decided, so concurrent runs never remind twice.
+func (r *LeaveApprovalRepository) MarkReminded(approvalID int, at time.Time) (bool, error) {
+	query := `
+		UPDATE leave_approvals
+		SET reminded_at = $1
+		WHERE id = $2 AND decision = $3 AND reminded_at IS NULL AND escalated_at IS NULL
+	`
+	return r.claimApprovalNotice(query, at, approvalID)
+}
+
+// MarkEscalated records that a pending step was escalated. It returns false if the step was
+// already escalated or decided, so concurrent runs never escalate twice.
+func (r *LeaveApprovalRepository) MarkEscalated(approvalID int, at time.Time) (bool, error) {
+	query := `
+		UPDATE leave_approvals
+		SET escalated_at = $1
+		WHERE id = $2 AND decision = $3 AND escalated_at IS NULL
+	`
+	return r.claimApprovalNotice(query, at, approvalID)
+}
+
+// claimApprovalNotice runs a conditional reminder update and reports whether it applied
+func (r *LeaveApprovalRepository) claimApprovalNotice(query string, at time.Time, approvalID int) (bool, error) {
+	q := convertPlaceholders(query)
+
+	result, err := r.db.Exec(q, at, approvalID, leave.DecisionPending)
+	if err != nil {
+		return false, errors.WrapError("failed to record approval reminder", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.WrapError("failed to get rows affected", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RequestInfo pauses a pending approval step until the employee answers a request for more
+// information, within a transaction. It returns false if the step is already paused or was decided.
+func (r *LeaveApprovalRepository) RequestInfo(tx *repositories.Transaction, approvalID int, at time.Time) (bool, error) {
+	query := `
+		UPDATE leave_approvals
+		SET info_requested_at = $1
+		WHERE id = $2 AND decision = $3 AND info_requested_at IS NULL
+	`
+	result, err := tx.GetTx().Exec(convertPlaceholders(query), at, approvalID, leave.DecisionPending)
+	if err != nil {
+		return false, errors.WrapError("failed to request more information", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.WrapError("failed to get rows affected", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ResumeApproval ends the pause of an approval step waiting for more information within a
+// transaction, adding the time it was paused to its paused total
+func (r *LeaveApprovalRepository) ResumeApproval(tx *repositories.Transaction, approvalID int, paused time.Duration) error {
+	query := `
+		UPDATE leave_approvals
+		SET info_requested_at = NULL, paused_seconds = paused_seconds + $1
+		WHERE id = $2 AND info_requested_at IS NOT NULL
+	`
+	if _, err := tx.GetTx().Exec(convertPlaceholders(query), int64(paused/time.Second), approvalID); err != nil {
+		return errors.WrapError("failed to resume approval step", err)
+	}
+	return nil
+}
+
+// SkipPendingApprovals marks the undecided steps of a leave request as skipped within a transaction
+func (r *LeaveApprovalRepository) SkipPendingApprovals(tx *repositories.Transaction, leaveRequestID int) error {
+	query := `
+		UPDATE leave_approvals
+		SET decision = $1
+		WHERE leave_request_id = $2 AND decision = $3
+	`
+	q := convertPlaceholders(query)
+
+	if _, err := tx.GetTx().Exec(q, leave.DecisionSkipped, leaveRequestID, leave.DecisionPending); err != nil {
+		return errors.WrapError("failed to skip pending approvals", err)
+	}
+	return nil
+}
+
+// ReplaceApprovals discards the approval chain of a leave request and writes a new one within
+// a transaction, e.g. after the request was edited
+func (r *LeaveApprovalRepository) ReplaceApprovals(tx *repositories.Transaction, leaveRequestID int, approvals []leave.LeaveApproval) error {
+	q := convertPlaceholders("DELETE FROM leave_approvals WHERE leave_request_id = $1")
+	if _, err := tx.GetTx().Exec(q, leaveRequestID); err != nil {
+		return errors.WrapError("failed to delete leave approvals", err)
+	}
+	return insertApprovals(tx.GetTx(), leaveRequestID, approvals, time.Now())
+}
+
+// CreateDelegation creates a new approval delegation
+func (r *LeaveApprovalRepository) CreateDelegation(d *leave.ApprovalDelegation) (*leave.ApprovalDelegation, error) {
+	query := `
+		INSERT INTO approval_delegations (delegator_user_id, delegate_user_id, start_date, end_date, reason, source, leave_request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	args := []interface{}{
+		d.DelegatorUserID,
+		d.DelegateUserID,
+		d.StartDate,
+		d.EndDate,
+		d.Reason,
+		d.Source,
+		d.LeaveRequestID,
+		now,
+	}
+
+	if helpers.DBType == "sqlite" {
+		res, err := r.db.Exec(q, args...)
+		if err != nil {
+			return nil, errors.WrapError("failed to create approval delegation", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		d.ID = int(lastID)
+		d.CreatedAt = now
+
+		return d, nil
+	}
+
+	err := r.db.QueryRow(q, args...).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create approval delegation", err)
+	}
+
+	return d, nil
+}
+
+// GetDelegation retrieves an approval delegation by ID
+func (r *LeaveApprovalRepository) GetDelegation(id int) (*leave.ApprovalDelegation, error) {
+	query := `
+		SELECT ` + delegationColumns + `
+		FROM approval_delegations
+		WHERE id = $1
+	`
+	q := convertPlaceholders(query)
+
+	d, err := scanDelegation(r.db.QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("approval delegation not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get approval delegation", err)
+	}
+
+	return d, nil
+}
+
+// ListDelegations retrieves the delegations a user has given or received, newest first
+func (r *LeaveApprovalRepository) ListDelegations(userID int) ([]leave.ApprovalDelegation, error) {
+	query := `
+		SELECT ` + delegationColumns + `
+		FROM approval_delegations
+		WHERE delegator_user_id = $1 OR delegate_user_id = $2
+		ORDER BY start_date DESC, id DESC
+	`
+	return r.queryDelegations(query, userID, userID)
+}
+
+// GetActiveDelegations retrieves the unrevoked delegations a user has received that cover a date
+func (r *LeaveApprovalRepository) GetActiveDelegations(delegateUserID int, date time.Time) ([]leave.ApprovalDelegation, error) {
+	query := `
+		SELECT ` + delegationColumns + `
+		FROM approval_delegations
+		WHERE delegate_user_id = $1 AND revoked_at IS NULL
+		  AND start_date <= $2 AND end_date >= $3
+		ORDER BY id
+	`
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return r.queryDelegations(query, delegateUserID, day, day)
+}
+
+// GetActiveDelegates retrieves the unrevoked delegations a user has given that cover a date
+func (r *LeaveApprovalRepository) GetActiveDelegates(delegatorUserID int, date time.Time) ([]leave.ApprovalDelegation, error) {
+	query := `
+		SELECT ` + delegationColumns + `
+		FROM approval_delegations
+		WHERE delegator_user_id = $1 AND revoked_at IS NULL
+		  AND start_date <= $2 AND end_date >= $3
+		ORDER BY id
+	`
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return r.queryDelegations(query, delegatorUserID, day, day)
+}
+
+// RevokeDelegation ends an approval delegation early
+func (r *LeaveApprovalRepository) RevokeDelegation(id int) error {
+	query := "UPDATE approval_delegations SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL"
+	q := convertPlaceholders(query)
+
+	result, err := r.db.Exec(q, time.Now(), id)
+	if err != nil {
+		return errors.WrapError("failed to revoke approval delegation", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewValidationError().AddField("status", "this delegation has already been revoked")
+	}
+
+	return nil
+}
+
+// CurtailLeaveDelegations cuts the delegations created for a leave request back to the days
+// before fromDate within a transaction, revoking those that had not started by then
+func (r *LeaveApprovalRepository) CurtailLeaveDelegations(tx *repositories.Transaction, leaveRequestID int, fromDate time.Time) error {
+	revoke := convertPlaceholders(`
+		UPDATE approval_delegations
+		SET revoked_at = $1
+		WHERE leave_request_id = $2 AND source = $3 AND revoked_at IS NULL AND start_date >= $4
+	`)
+	if _, err := tx.GetTx().Exec(revoke, time.Now(), leaveRequestID, leave.DelegationLeave, fromDate); err != nil {
+		return errors.WrapError("failed to revoke leave delegations", err)
+	}
+
+	shorten := convertPlaceholders(`
+		UPDATE approval_delegations
+		SET end_date = $1
+		WHERE leave_request_id = $2 AND source = $3 AND revoked_at IS NULL AND end_date >= $4
+	`)
+	if _, err := tx.GetTx().Exec(shorten, fromDate.AddDate(0, 0, -1), leaveRequestID, leave.DelegationLeave, fromDate); err != nil {
+		return errors.WrapError("failed to shorten leave delegations", err)
+	}
+	return nil
+}
+
+// GetDefaultDelegate retrieves the user who receives an approver's rights while they are on leave
+func (r *LeaveApprovalRepository) GetDefaultDelegate(userID int) (*int, error) {
+	query := "SELECT delegate_user_id FROM approval_delegate_defaults WHERE user_id = $1"
+	q := convertPlaceholders(query)
+
+	var delegateUserID int
+	err := r.db.QueryRow(q, userID).Scan(&delegateUserID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get default delegate", err)
+	}
+
+	return &delegateUserID, nil
+}
+
+// SetDefaultDelegate sets or, when delegateUserID is nil, removes an approver's default delegate
+func (r *LeaveApprovalRepository) SetDefaultDelegate(userID int, delegateUserID *int) error {
+	if delegateUserID == nil {
+		q := convertPlaceholders("DELETE FROM approval_delegate_defaults WHERE user_id = $1")
+		if _, err := r.db.Exec(q, userID); err != nil {
+			return errors.WrapError("failed to remove default delegate", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO approval_delegate_defaults (user_id, delegate_user_id, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET delegate_user_id = excluded.delegate_user_id, updated_at = excluded.updated_at
+	`
+	q := convertPlaceholders(query)
+
+	if _, err := r.db.Exec(q, userID, *delegateUserID, time.Now()); err != nil {
+		return errors.WrapError("failed to set default delegate", err)
+	}
+	return nil
+}
+
+// queryDelegations runs a query selecting delegationColumns
+func (r *LeaveApprovalRepository) queryDelegations(query string, args ...interface{}) ([]leave.ApprovalDelegation, error) {
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query approval delegations", err)
+	}
+	defer rows.Close()
+
+	delegations := []leave.ApprovalDelegation{}
+
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan approval delegation", err)
+		}
+		delegations = append(delegations, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating approval delegations", err)
+	}
+
+	return delegations, nil
+}
+
+// insertApprovals writes the approval chain of a new leave request
+func insertApprovals(ex dbExecutor, leaveRequestID int, approvals []leave.LeaveApproval, createdAt time.Time) error {
+	query := convertPlaceholders(`
+		INSERT INTO leave_approvals (leave_request_id, step, approver_role, decision, comment, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+
+	for i := range approvals {
+		a := &approvals[i]
+		a.LeaveRequestID = leaveRequestID
+		a.CreatedAt = createdAt
+		if _, err := ex.Exec(query, leaveRequestID, a.Step, a.ApproverRole, a.Decision, a.Comment, createdAt); err != nil {
+			return errors.WrapError("failed to create leave approval", err)
+		}
+	}
+	return nil
+}
+
+// scanApprovalRule scans an approval rule row selected with approvalRuleColumns
+func scanApprovalRule(row rowScanner) (*leave.ApprovalRule, error) {
+	var rule leave.ApprovalRule
+	var leaveTypes string
+	err := row.Scan(
+		&rule.ID,
+		&rule.StepOrder,
+		&rule.ApproverRole,
+		&rule.OverDays,
+		&leaveTypes,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rule.LeaveTypes = decodeLeaveTypes(leaveTypes)
+	return &rule, nil
+}
+
+// scanLeaveApproval scans an approval step row selected with leaveApprovalColumns
+func scanLeaveApproval(row rowScanner) (*leave.LeaveApproval, error) {
+	var a leave.LeaveApproval
+	var approverUserID, onBehalfOfUserID sql.NullInt64
+	var decidedAt, remindedAt, escalatedAt, infoRequestedAt sql.NullTime
+	err := row.Scan(
+		&a.ID,
+		&a.LeaveRequestID,
+		&a.Step,
+		&a.ApproverRole,
+		&approverUserID,
+		&onBehalfOfUserID,
+		&a.Decision,
+		&a.Comment,
+		&decidedAt,
+		&remindedAt,
+		&escalatedAt,
+		&infoRequestedAt,
+		&a.PausedSeconds,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if approverUserID.Valid {
+		id := int(approverUserID.Int64)
+		a.ApproverUserID = &id
+	}
+	if onBehalfOfUserID.Valid {
+		id := int(onBehalfOfUserID.Int64)
+		a.OnBehalfOfUserID = &id
+	}
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+	if remindedAt.Valid {
+		a.RemindedAt = &remindedAt.Time
+	}
+	if escalatedAt.Valid {
+		a.EscalatedAt = &escalatedAt.Time
+	}
+	if infoRequestedAt.Valid {
+		a.InfoRequestedAt = &infoRequestedAt.Time
+	}
+	return &a, nil
+}
+
+// encodeLeaveTypes stores a list of leave types as a comma-separated string
+func encodeLeaveTypes(types []leave.LeaveType) string {
+	values := make([]string, len(types))
+	for i, t := range types {
+		values[i] = string(t)
+	}
+	return strings.Join(values, ",")
+}
+
+// decodeLeaveTypes parses a comma-separated list of leave types
+func decodeLeaveTypes(value string) []leave.LeaveType {
+	types := []leave.LeaveType{}
+	for _, t := range strings.Split(value, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, leave.LeaveType(t))
+		}
+	}
+	return types
+}
+
+// scanDelegation scans a delegation row selected with delegationColumns
+func scanDelegation(row rowScanner) (*leave.ApprovalDelegation, error) {
+	var d leave.ApprovalDelegation
+	var leaveRequestID sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&d.ID,
+		&d.DelegatorUserID,
+		&d.DelegateUserID,
+		&d.StartDate,
+		&d.EndDate,
+		&d.Reason,
+		&d.Source,
+		&leaveRequestID,
+		&revokedAt,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if leaveRequestID.Valid {
+		id := int(leaveRequestID.Int64)
+		d.LeaveRequestID = &id
+	}
+	if revokedAt.Valid {
+		d.RevokedAt = &revokedAt.Time
+	}
+	return &d, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// leaveAttachmentColumns selects an attachment from leave_attachments
+const leaveAttachmentColumns = `id, leave_request_id, file_name, content_type, size, storage_key, COALESCE(uploaded_by, 0), created_at`
+
+// CreateAttachment records a stored attachment of a leave request within a transaction
+func (r *LeaveRepository) CreateAttachment(tx *repositories.Transaction, a *leave.LeaveAttachment) error {
+	query := `
+		INSERT INTO leave_attachments (leave_request_id, file_name, content_type, size, storage_key, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	q := convertPlaceholders(query)
+
+	a.CreatedAt = time.Now()
+	args := []interface{}{a.LeaveRequestID, a.FileName, a.ContentType, a.Size, a.StorageKey, a.UploadedBy, a.CreatedAt}
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, args...)
+		if err != nil {
+			return errors.WrapError("failed to create leave attachment", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return errors.WrapError("failed to get last insert id", err)
+		}
+		a.ID = int(lastID)
+		return nil
+	}
+
+	if err := tx.GetTx().QueryRow(q, args...).Scan(&a.ID); err != nil {
+		return errors.WrapError("failed to create leave attachment", err)
+	}
+	return nil
+}
+
+// GetAttachments retrieves the attachments of a leave request in upload order
+func (r *LeaveRepository) GetAttachments(leaveRequestID int) ([]leave.LeaveAttachment, error) {
+	query := `
+		SELECT ` + leaveAttachmentColumns + `
+		FROM leave_attachments
+		WHERE leave_request_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), leaveRequestID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave attachments", err)
+	}
+	defer rows.Close()
+
+	attachments := []leave.LeaveAttachment{}
+	for rows.Next() {
+		a, err := scanLeaveAttachment(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave attachment", err)
+		}
+		attachments = append(attachments, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave attachments", err)
+	}
+	return attachments, nil
+}
+
+// GetAttachment retrieves an attachment of a leave request
+func (r *LeaveRepository) GetAttachment(leaveRequestID, id int) (*leave.LeaveAttachment, error) {
+	query := `
+		SELECT ` + leaveAttachmentColumns + `
+		FROM leave_attachments
+		WHERE id = $1 AND leave_request_id = $2
+	`
+	a, err := scanLeaveAttachment(r.db.QueryRow(convertPlaceholders(query), id, leaveRequestID))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("leave attachment not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get leave attachment", err)
+	}
+	return a, nil
+}
+
+// CountAttachments counts the attachments of a leave request within a transaction
+func (r *LeaveRepository) CountAttachments(tx *repositories.Transaction, leaveRequestID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM leave_attachments WHERE leave_request_id = $1`
+	if err := tx.GetTx().QueryRow(convertPlaceholders(query), leaveRequestID).Scan(&count); err != nil {
+		return 0, errors.WrapError("failed to count leave attachments", err)
+	}
+	return count, nil
+}
+
+// scanLeaveAttachment scans an attachment row selected with leaveAttachmentColumns
+func scanLeaveAttachment(row rowScanner) (*leave.LeaveAttachment, error) {
+	var a leave.LeaveAttachment
+	err := row.Scan(&a.ID, &a.LeaveRequestID, &a.FileName, &a.ContentType, &a.Size, &a.StorageKey, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
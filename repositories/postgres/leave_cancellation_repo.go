@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// cancellationColumns selects a cancellation request from leave_cancellations lc joined with
+// its leave request lr
+const cancellationColumns = `lc.id, lc.leave_request_id, lr.employee_id, lc.requested_by, lc.from_date, lc.reason,
+		       lc.status, lc.days_refunded, lc.salary_refunded, lc.decided_by, lc.decision_comment,
+		       lc.decided_at, lc.created_at`
+
+// cancellationFrom is the FROM clause for cancellationColumns
+const cancellationFrom = `leave_cancellations lc JOIN leave_requests lr ON lr.id = lc.leave_request_id`
+
+// CreateCancellation records a request to cancel approved leave within a transaction
+func (r *LeaveRepository) CreateCancellation(tx *repositories.Transaction, c *leave.LeaveCancellation) (*leave.LeaveCancellation, error) {
+	query := `
+		INSERT INTO leave_cancellations (leave_request_id, requested_by, from_date, reason, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	args := []interface{}{
+		c.LeaveRequestID,
+		c.RequestedBy,
+		c.FromDate,
+		c.Reason,
+		leave.CancellationPending,
+		now,
+		now,
+	}
+
+	c.Status = leave.CancellationPending
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, args...)
+		if err != nil {
+			return nil, errors.WrapError("failed to create leave cancellation", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		c.ID = int(lastID)
+		c.CreatedAt = now
+
+		return c, nil
+	}
+
+	err := tx.GetTx().QueryRow(q, args...).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create leave cancellation", err)
+	}
+
+	return c, nil
+}
+
+// GetPendingCancellation retrieves the undecided cancellation request of a leave request within
+// a transaction. It returns nil if there is none.
+func (r *LeaveRepository) GetPendingCancellation(tx *repositories.Transaction, leaveRequestID int) (*leave.LeaveCancellation, error) {
+	query := `
+		SELECT ` + cancellationColumns + `
+		FROM ` + cancellationFrom + `
+		WHERE lc.leave_request_id = $1 AND lc.status = $2
+	`
+	q := convertPlaceholders(query)
+
+	c, err := scanCancellation(tx.GetTx().QueryRow(q, leaveRequestID, leave.CancellationPending))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get leave cancellation", err)
+	}
+
+	return c, nil
+}
+
+// LockCancellation retrieves a cancellation request by ID and locks it until the transaction ends
+func (r *LeaveRepository) LockCancellation(tx *repositories.Transaction, id int) (*leave.LeaveCancellation, error) {
+	if err := lockRow(tx.GetTx(), "leave_cancellations", "id = $1", id); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + cancellationColumns + `
+		FROM ` + cancellationFrom + `
+		WHERE lc.id = $1
+	`
+	q := convertPlaceholders(query)
+
+	c, err := scanCancellation(tx.GetTx().QueryRow(q, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("leave cancellation not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get leave cancellation", err)
+	}
+
+	return c, nil
+}
+
+// ListCancellations retrieves cancellation requests, newest first, optionally filtered by status
+func (r *LeaveRepository) ListCancellations(status string) ([]leave.LeaveCancellation, error) {
+	query := `
+		SELECT ` + cancellationColumns + `
+		FROM ` + cancellationFrom + `
+	`
+	args := []interface{}{}
+	if status != "" {
+		query += ` WHERE lc.status = $1`
+		args = append(args, status)
+	}
+	query += ` ORDER BY lc.created_at DESC, lc.id DESC`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave cancellations", err)
+	}
+	defer rows.Close()
+
+	cancellations := []leave.LeaveCancellation{}
+
+	for rows.Next() {
+		c, err := scanCancellation(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave cancellation", err)
+		}
+		cancellations = append(cancellations, *c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave cancellations", err)
+	}
+
+	return cancellations, nil
+}
+
+// DecideCancellation records the decision on a cancellation request within a transaction
+func (r *LeaveRepository) DecideCancellation(tx *repositories.Transaction, c *leave.LeaveCancellation) error {
+	query := `
+		UPDATE leave_cancellations
+		SET status = $1, days_refunded = $2, salary_refunded = $3, decided_by = $4,
+		    decision_comment = $5, decided_at = $6, updated_at = $7
+		WHERE id = $8 AND status = $9
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	result, err := tx.GetTx().Exec(q,
+		c.Status,
+		c.DaysRefunded,
+		c.SalaryRefunded,
+		c.DecidedBy,
+		c.DecisionComment,
+		now,
+		now,
+		c.ID,
+		leave.CancellationPending,
+	)
+	if err != nil {
+		return errors.WrapError("failed to update leave cancellation", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewValidationError().AddField("status", "cancellation request has already been decided")
+	}
+
+	c.DecidedAt = &now
+	return nil
+}
+
+// RefundLeaveBalance returns the unused days of a cancelled leave request to the balance
+// by appending a reversal entry to the ledger
+func (r *LeaveRepository) RefundLeaveBalance(tx *repositories.Transaction, lr *leave.LeaveRequest, days float64, fromDate time.Time, actorUserID int) error {
+	ex := tx.GetTx()
+	if err := lockLeaveBalance(ex, lr.EmployeeID, lr.LeaveType); err != nil {
+		return err
+	}
+	return insertLedgerEntry(ex, leave.NewReversalEntry(lr, days, fromDate, actorUserID))
+}
+
+// ShortenLeaveRequest stores the new end date, days and salary deduction of a partly cancelled
+// leave request within a transaction
+func (r *LeaveRepository) ShortenLeaveRequest(tx *repositories.Transaction, lr *leave.LeaveRequest) error {
+	query := `
+		UPDATE leave_requests
+		SET end_date = $1, days_count = $2, salary_deduction = $3, skipped_days = $4, updated_at = $5
+		WHERE id = $6
+	`
+	q := convertPlaceholders(query)
+
+	result, err := tx.GetTx().Exec(q,
+		lr.EndDate,
+		lr.DaysCount,
+		lr.SalaryDeduction,
+		encodeSkippedDays(lr.SkippedDays),
+		time.Now(),
+		lr.ID,
+	)
+	if err != nil {
+		return errors.WrapError("failed to shorten leave request", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("leave request not found")
+	}
+
+	return nil
+}
+
+// scanCancellation scans a cancellation row selected with cancellationColumns
+func scanCancellation(row rowScanner) (*leave.LeaveCancellation, error) {
+	var c leave.LeaveCancellation
+	var decidedBy sql.NullInt64
+	var decidedAt sql.NullTime
+	err := row.Scan(
+		&c.ID,
+		&c.LeaveRequestID,
+		&c.EmployeeID,
+		&c.RequestedBy,
+		&c.FromDate,
+		&c.Reason,
+		&c.Status,
+		&c.DaysRefunded,
+		&c.SalaryRefunded,
+		&decidedBy,
+		&c.DecisionComment,
+		&decidedAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if decidedBy.Valid {
+		id := int(decidedBy.Int64)
+		c.DecidedBy = &id
+	}
+	if decidedAt.Valid {
+		c.DecidedAt = &decidedAt.Time
+	}
+	return &c, nil
+}
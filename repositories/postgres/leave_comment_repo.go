@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// CreateComment adds a comment to the thread of a leave request within a transaction
+func (r *LeaveRepository) CreateComment(tx *repositories.Transaction, c *leave.LeaveComment) error {
+	query := `
+		INSERT INTO leave_comments (leave_request_id, author_user_id, author_name, body, requests_info, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	q := convertPlaceholders(query)
+
+	c.CreatedAt = time.Now()
+	args := []interface{}{c.LeaveRequestID, c.AuthorUserID, c.AuthorName, c.Body, c.RequestsInfo, c.CreatedAt}
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, args...)
+		if err != nil {
+			return errors.WrapError("failed to create leave comment", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return errors.WrapError("failed to get last insert id", err)
+		}
+		c.ID = int(lastID)
+		return nil
+	}
+
+	if err := tx.GetTx().QueryRow(q, args...).Scan(&c.ID); err != nil {
+		return errors.WrapError("failed to create leave comment", err)
+	}
+	return nil
+}
+
+// GetComments retrieves the comment thread of a leave request, oldest first
+func (r *LeaveRepository) GetComments(leaveRequestID int) ([]leave.LeaveComment, error) {
+	query := `
+		SELECT id, leave_request_id, author_user_id, author_name, body, requests_info, created_at
+		FROM leave_comments
+		WHERE leave_request_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), leaveRequestID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave comments", err)
+	}
+	defer rows.Close()
+
+	comments := []leave.LeaveComment{}
+	for rows.Next() {
+		var c leave.LeaveComment
+		var authorUserID sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.LeaveRequestID, &authorUserID, &c.AuthorName, &c.Body, &c.RequestsInfo, &c.CreatedAt); err != nil {
+			return nil, errors.WrapError("failed to scan leave comment", err)
+		}
+		if authorUserID.Valid {
+			id := int(authorUserID.Int64)
+			c.AuthorUserID = &id
+		}
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave comments", err)
+	}
+	return comments, nil
+}
@@ -0,0 +1,279 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+const coverageRuleColumns = `id, name, scope, group_name, max_absent, action, is_active, created_at, updated_at`
+
+// dbQuerier runs queries on the database or within a transaction
+type dbQuerier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// AbsenceFilter selects the leave requests shown on the team calendar or checked for coverage:
+// those with one of Statuses between From and To, optionally of one team or department or of
+// some employees
+type AbsenceFilter struct {
+	From        time.Time
+	To          time.Time
+	Statuses    []leave.LeaveStatus
+	Team        string // Matched case-insensitively; empty = any team
+	Department  string // Matched case-insensitively; empty = any department
+	EmployeeIDs []int  // Empty = any employee
+}
+
+// GetAbsences retrieves the leave requests matching a filter with their employees' names, teams
+// and departments, in start date order
+func (r *LeaveRepository) GetAbsences(filter AbsenceFilter) ([]leave.CalendarAbsence, error) {
+	return queryAbsences(r.db, filter)
+}
+
+// GetAbsencesTx retrieves absences as GetAbsences does within a transaction
+func (r *LeaveRepository) GetAbsencesTx(tx *repositories.Transaction, filter AbsenceFilter) ([]leave.CalendarAbsence, error) {
+	return queryAbsences(tx.GetTx(), filter)
+}
+
+// queryAbsences selects the absences matching a filter
+func queryAbsences(q dbQuerier, filter AbsenceFilter) ([]leave.CalendarAbsence, error) {
+	args := []interface{}{filter.To, filter.From}
+	conditions := []string{"lr.start_date <= $1", "lr.end_date >= $2"}
+
+	placeholders := make([]string, len(filter.Statuses))
+	for i, status := range filter.Statuses {
+		args = append(args, status)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	conditions = append(conditions, "lr.status IN ("+strings.Join(placeholders, ", ")+")")
+
+	if filter.Team != "" {
+		args = append(args, strings.ToLower(filter.Team))
+		conditions = append(conditions, fmt.Sprintf("LOWER(e.team) = $%d", len(args)))
+	}
+	if filter.Department != "" {
+		args = append(args, strings.ToLower(filter.Department))
+		conditions = append(conditions, fmt.Sprintf("LOWER(e.department) = $%d", len(args)))
+	}
+	if len(filter.EmployeeIDs) > 0 {
+		ids := make([]string, len(filter.EmployeeIDs))
+		for i, id := range filter.EmployeeIDs {
+			args = append(args, id)
+			ids[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "lr.employee_id IN ("+strings.Join(ids, ", ")+")")
+	}
+
+	query := `
+		SELECT lr.id, lr.employee_id, e.first_name, e.last_name, e.team, e.department, lr.leave_type, lr.status,
+		       lr.start_date, lr.end_date, lr.day_part, lr.hours, lr.days_count, lr.event_sequence
+		FROM leave_requests lr
+		JOIN employees e ON e.id = lr.employee_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY lr.start_date, lr.id
+	`
+
+	rows, err := q.Query(convertPlaceholders(query), args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query absences", err)
+	}
+	defer rows.Close()
+
+	absences := []leave.CalendarAbsence{}
+	for rows.Next() {
+		var a leave.CalendarAbsence
+		var firstName, lastName string
+		err := rows.Scan(&a.LeaveRequestID, &a.EmployeeID, &firstName, &lastName, &a.Team, &a.Department,
+			&a.LeaveType, &a.Status, &a.StartDate, &a.EndDate, &a.DayPart, &a.Hours, &a.DaysCount, &a.EventSequence)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan absence", err)
+		}
+		a.EmployeeName = firstName + " " + lastName
+		absences = append(absences, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating absences", err)
+	}
+
+	return absences, nil
+}
+
+// ListCoverageRules retrieves the coverage rules by scope and group, optionally only the active ones
+func (r *LeaveRepository) ListCoverageRules(activeOnly bool) ([]leave.CoverageRule, error) {
+	query := `
+		SELECT ` + coverageRuleColumns + `
+		FROM leave_coverage_rules
+	`
+	var args []interface{}
+	if activeOnly {
+		query += " WHERE is_active = $1"
+		args = append(args, true)
+	}
+	query += " ORDER BY scope, group_name"
+
+	rows, err := r.db.Query(convertPlaceholders(query), args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query coverage rules", err)
+	}
+	defer rows.Close()
+
+	rules := []leave.CoverageRule{}
+	for rows.Next() {
+		rule, err := scanCoverageRule(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan coverage rule", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating coverage rules", err)
+	}
+
+	return rules, nil
+}
+
+// GetCoverageRule retrieves a coverage rule by ID
+func (r *LeaveRepository) GetCoverageRule(id int) (*leave.CoverageRule, error) {
+	query := `
+		SELECT ` + coverageRuleColumns + `
+		FROM leave_coverage_rules
+		WHERE id = $1
+	`
+
+	rule, err := scanCoverageRule(r.db.QueryRow(convertPlaceholders(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("coverage rule not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get coverage rule", err)
+	}
+
+	return rule, nil
+}
+
+// CreateCoverageRule creates a new coverage rule
+func (r *LeaveRepository) CreateCoverageRule(rule *leave.CoverageRule) (*leave.CoverageRule, error) {
+	query := `
+		INSERT INTO leave_coverage_rules (name, scope, group_name, max_absent, action, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	args := []interface{}{rule.Name, rule.Scope, rule.Group, rule.MaxAbsent, rule.Action, rule.IsActive, now, now}
+
+	if helpers.DBType == "sqlite" {
+		res, err := r.db.Exec(q, args...)
+		if err != nil {
+			return nil, errors.WrapError("failed to create coverage rule", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		rule.ID = int(lastID)
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+
+		return rule, nil
+	}
+
+	err := r.db.QueryRow(q, args...).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create coverage rule", err)
+	}
+
+	return rule, nil
+}
+
+// UpdateCoverageRule replaces an existing coverage rule
+func (r *LeaveRepository) UpdateCoverageRule(rule *leave.CoverageRule) error {
+	query := `
+		UPDATE leave_coverage_rules
+		SET name = $1, scope = $2, group_name = $3, max_absent = $4, action = $5, is_active = $6, updated_at = $7
+		WHERE id = $8
+	`
+
+	rule.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(convertPlaceholders(query),
+		rule.Name,
+		rule.Scope,
+		rule.Group,
+		rule.MaxAbsent,
+		rule.Action,
+		rule.IsActive,
+		rule.UpdatedAt,
+		rule.ID,
+	)
+	if err != nil {
+		return errors.WrapError("failed to update coverage rule", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("coverage rule not found")
+	}
+
+	return nil
+}
+
+// DeleteCoverageRule deletes a coverage rule
+func (r *LeaveRepository) DeleteCoverageRule(id int) error {
+	result, err := r.db.Exec(convertPlaceholders("DELETE FROM leave_coverage_rules WHERE id = $1"), id)
+	if err != nil {
+		return errors.WrapError("failed to delete coverage rule", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("coverage rule not found")
+	}
+
+	return nil
+}
+
+// LockCoverageRule locks a coverage rule for the rest of the transaction, so approvals checked
+// against the same rule run one at a time
+func (r *LeaveRepository) LockCoverageRule(tx *repositories.Transaction, id int) error {
+	return lockRow(tx.GetTx(), "leave_coverage_rules", "id = $1", id)
+}
+
+// scanCoverageRule scans a coverage rule row selected with coverageRuleColumns
+func scanCoverageRule(row rowScanner) (*leave.CoverageRule, error) {
+	var rule leave.CoverageRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Scope,
+		&rule.Group,
+		&rule.MaxAbsent,
+		&rule.Action,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/utils/helpers"
+)
+
+// dbExecutor is implemented by both *sql.DB and *sql.Tx
+type dbExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// lockRow takes a row lock on a row selected by its WHERE clause for the rest of the transaction.
+// SQLite has no row locks, so there a no-op update takes the database write lock instead.
+func lockRow(ex dbExecutor, table, where string, args ...interface{}) error {
+	var err error
+	if helpers.DBType == "sqlite" {
+		_, err = ex.Exec(convertPlaceholders("UPDATE "+table+" SET updated_at = updated_at WHERE "+where), args...)
+	} else {
+		var id int
+		err = ex.QueryRow("SELECT id FROM "+table+" WHERE "+where+" FOR UPDATE", args...).Scan(&id)
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+	}
+	if err != nil {
+		return errors.WrapError("failed to lock "+table+" row", err)
+	}
+	return nil
+}
+
+// lockLeaveBalance locks an employee's balance row of a leave type so concurrent debits and
+// adjustments see each other's ledger entries
+func lockLeaveBalance(ex dbExecutor, employeeID int, leaveType leave.LeaveType) error {
+	return lockRow(ex, "leave_balances", "employee_id = $1 AND leave_type = $2", employeeID, leaveType)
+}
+
+// ledgerBalanceSQL derives the balance of the leave_balances row aliased lb from the ledger
+const ledgerBalanceSQL = `COALESCE((SELECT SUM(ll.days) FROM leave_ledger ll
+		        WHERE ll.employee_id = lb.employee_id AND ll.leave_type = lb.leave_type), 0)`
+
+// insertLedgerEntry appends an entry to the leave ledger
+func insertLedgerEntry(ex dbExecutor, entry *leave.LeaveLedgerEntry) error {
+	query := `
+		INSERT INTO leave_ledger (employee_id, leave_type, entry_type, days, leave_request_id,
+		                          actor_user_id, reference, description, effective_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.EffectiveDate.IsZero() {
+		// Adjustments and the like count from the day they are made
+		y, m, d := entry.CreatedAt.Date()
+		entry.EffectiveDate = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	_, err := ex.Exec(convertPlaceholders(query),
+		entry.EmployeeID,
+		entry.LeaveType,
+		entry.EntryType,
+		entry.Days,
+		entry.LeaveRequestID,
+		entry.ActorUserID,
+		entry.Reference,
+		entry.Description,
+		entry.EffectiveDate,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return errors.WrapError("failed to write leave ledger entry", err)
+	}
+	return nil
+}
+
+// ledgerBalance sums the ledger entries of an employee's leave type
+func ledgerBalance(ex dbExecutor, employeeID int, leaveType leave.LeaveType) (float64, error) {
+	var balance float64
+	err := ex.QueryRow(convertPlaceholders(`
+		SELECT COALESCE(SUM(days), 0)
+		FROM leave_ledger
+		WHERE employee_id = $1 AND leave_type = $2
+	`), employeeID, leaveType).Scan(&balance)
+	if err != nil {
+		return 0, errors.WrapError("failed to sum leave ledger", err)
+	}
+	return leave.RoundDays(balance), nil
+}
+
+// GetLedgerEntries retrieves the ledger of an employee's leave type in chronological
+// order, with the running balance after each entry
+func (r *LeaveRepository) GetLedgerEntries(employeeID int, leaveType leave.LeaveType) ([]leave.LeaveLedgerEntry, error) {
+	query := `
+		SELECT id, employee_id, leave_type, entry_type, days, leave_request_id,
+		       actor_user_id, reference, description, effective_date, created_at
+		FROM leave_ledger
+		WHERE employee_id = $1 AND leave_type = $2
+		ORDER BY created_at, id
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, employeeID, leaveType)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave ledger", err)
+	}
+	defer rows.Close()
+
+	entries := []leave.LeaveLedgerEntry{}
+
+	for rows.Next() {
+		var e leave.LeaveLedgerEntry
+		var leaveRequestID, actorUserID sql.NullInt64
+		err := rows.Scan(
+			&e.ID,
+			&e.EmployeeID,
+			&e.LeaveType,
+			&e.EntryType,
+			&e.Days,
+			&leaveRequestID,
+			&actorUserID,
+			&e.Reference,
+			&e.Description,
+			&e.EffectiveDate,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave ledger entry", err)
+		}
+
+		if leaveRequestID.Valid {
+			id := int(leaveRequestID.Int64)
+			e.LeaveRequestID = &id
+		}
+		if actorUserID.Valid {
+			id := int(actorUserID.Int64)
+			e.ActorUserID = &id
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave ledger", err)
+	}
+
+	leave.SetRunningBalances(entries)
+
+	return entries, nil
+}
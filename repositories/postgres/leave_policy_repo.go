@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/utils/helpers"
+)
+
+// LeavePolicyRepository handles database operations for leave policies
+type LeavePolicyRepository struct {
+	db *sql.DB
+}
+
+// NewLeavePolicyRepository creates a new leave policy repository
+func NewLeavePolicyRepository(db *sql.DB) *LeavePolicyRepository {
+	return &LeavePolicyRepository{db: db}
+}
+
+const leavePolicyColumns = `id, leave_type, annual_entitlement, is_paid, eligible_gender, requires_married,
+		       min_tenure_days, max_consecutive_days, notice_days, accrual_frequency,
+			       carry_forward_max, encash_excess, low_balance_threshold, attachment_required_after_days,
+			       is_active, created_at, updated_at`
+
+// CreatePolicy creates a new leave policy
+func (r *LeavePolicyRepository) CreatePolicy(p *leave.LeavePolicy) (*leave.LeavePolicy, error) {
+	query := `
+		INSERT INTO leave_policies (leave_type, annual_entitlement, is_paid, eligible_gender, requires_married,
+		                            min_tenure_days, max_consecutive_days, notice_days, accrual_frequency,
+		                            carry_forward_max, encash_excess, low_balance_threshold, attachment_required_after_days,
+		                            is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, created_at, updated_at
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	args := []interface{}{
+		p.LeaveType,
+		p.AnnualEntitlement,
+		p.IsPaid,
+		p.EligibleGender,
+		p.RequiresMarried,
+		p.MinTenureDays,
+		p.MaxConsecutiveDays,
+		p.NoticeDays,
+		p.AccrualFrequency,
+		p.CarryForwardMax,
+		p.EncashExcess,
+		p.LowBalanceThreshold,
+		p.AttachmentAfterDays,
+		p.IsActive,
+		now,
+		now,
+	}
+
+	if helpers.DBType == "sqlite" {
+		res, err := r.db.Exec(q, args...)
+		if err != nil {
+			return nil, errors.WrapError("failed to create leave policy", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return nil, errors.WrapError("failed to get last insert id", err)
+		}
+		p.ID = int(lastID)
+		p.CreatedAt = now
+		p.UpdatedAt = now
+
+		return p, nil
+	}
+
+	err := r.db.QueryRow(q, args...).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, errors.WrapError("failed to create leave policy", err)
+	}
+
+	return p, nil
+}
+
+// GetPolicy retrieves the policy for a leave type
+func (r *LeavePolicyRepository) GetPolicy(leaveType leave.LeaveType) (*leave.LeavePolicy, error) {
+	query := `
+		SELECT ` + leavePolicyColumns + `
+		FROM leave_policies
+		WHERE leave_type = $1
+	`
+	q := convertPlaceholders(query)
+
+	p, err := scanLeavePolicy(r.db.QueryRow(q, leaveType))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("leave policy not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get leave policy", err)
+	}
+
+	return p, nil
+}
+
+// ListPolicies retrieves all leave policies, optionally only the active ones
+func (r *LeavePolicyRepository) ListPolicies(activeOnly bool) ([]leave.LeavePolicy, error) {
+	query := `
+		SELECT ` + leavePolicyColumns + `
+		FROM leave_policies
+	`
+	var args []interface{}
+	if activeOnly {
+		query += " WHERE is_active = $1"
+		args = append(args, true)
+	}
+	query += " ORDER BY leave_type"
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave policies", err)
+	}
+	defer rows.Close()
+
+	policies := []leave.LeavePolicy{}
+
+	for rows.Next() {
+		p, err := scanLeavePolicy(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave policy", err)
+		}
+		policies = append(policies, *p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave policies", err)
+	}
+
+	return policies, nil
+}
+
+// UpdatePolicy updates an existing leave policy
+func (r *LeavePolicyRepository) UpdatePolicy(p *leave.LeavePolicy) error {
+	query := `
+		UPDATE leave_policies
+		SET annual_entitlement = $1, is_paid = $2, eligible_gender = $3, requires_married = $4,
+		    min_tenure_days = $5, max_consecutive_days = $6, notice_days = $7, accrual_frequency = $8,
+		    carry_forward_max = $9, encash_excess = $10, low_balance_threshold = $11,
+		    attachment_required_after_days = $12, is_active = $13, updated_at = $14
+		WHERE leave_type = $15
+	`
+	q := convertPlaceholders(query)
+
+	p.UpdatedAt = time.Now()
+
+	result, err := r.db.Exec(q,
+		p.AnnualEntitlement,
+		p.IsPaid,
+		p.EligibleGender,
+		p.RequiresMarried,
+		p.MinTenureDays,
+		p.MaxConsecutiveDays,
+		p.NoticeDays,
+		p.AccrualFrequency,
+		p.CarryForwardMax,
+		p.EncashExcess,
+		p.LowBalanceThreshold,
+		p.AttachmentAfterDays,
+		p.IsActive,
+		p.UpdatedAt,
+		p.LeaveType,
+	)
+	if err != nil {
+		return errors.WrapError("failed to update leave policy", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("leave policy not found")
+	}
+
+	return nil
+}
+
+// DeletePolicy deletes the policy for a leave type
+func (r *LeavePolicyRepository) DeletePolicy(leaveType leave.LeaveType) error {
+	query := "DELETE FROM leave_policies WHERE leave_type = $1"
+	q := convertPlaceholders(query)
+
+	result, err := r.db.Exec(q, leaveType)
+	if err != nil {
+		return errors.WrapError("failed to delete leave policy", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("leave policy not found")
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLeavePolicy scans a leave policy row selected with leavePolicyColumns
+func scanLeavePolicy(row rowScanner) (*leave.LeavePolicy, error) {
+	var p leave.LeavePolicy
+	err := row.Scan(
+		&p.ID,
+		&p.LeaveType,
+		&p.AnnualEntitlement,
+		&p.IsPaid,
+		&p.EligibleGender,
+		&p.RequiresMarried,
+		&p.MinTenureDays,
+		&p.MaxConsecutiveDays,
+		&p.NoticeDays,
+		&p.AccrualFrequency,
+		&p.CarryForwardMax,
+		&p.EncashExcess,
+		&p.LowBalanceThreshold,
+		&p.AttachmentAfterDays,
+		&p.IsActive,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -2,11 +2,14 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"employee-service/errors"
 	"employee-service/models/leave"
+	"employee-service/repositories"
 	"employee-service/utils/helpers"
 )
 
@@ -20,31 +23,38 @@ func NewLeaveRepository(db *sql.DB) *LeaveRepository {
 	return &LeaveRepository{db: db}
 }
 
-// CreateLeaveRequest creates a new leave request
-func (r *LeaveRepository) CreateLeaveRequest(lr *leave.LeaveRequest) (*leave.LeaveRequest, error) {
+// CreateLeaveRequest creates a new leave request together with its approval chain within a transaction
+func (r *LeaveRepository) CreateLeaveRequest(tx *repositories.Transaction, lr *leave.LeaveRequest) (*leave.LeaveRequest, error) {
 	query := `
-		INSERT INTO leave_requests (employee_id, leave_type, status, start_date, end_date, reason, days_count, notes, salary_deduction, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO leave_requests (employee_id, leave_type, status, start_date, end_date, reason, days_count, day_part, hours, notes, salary_deduction, skipped_days, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at, updated_at
 	`
 	q := convertPlaceholders(query)
 
 	now := time.Now()
+	skippedDays := encodeSkippedDays(lr.SkippedDays)
+	args := []interface{}{
+		lr.EmployeeID,
+		lr.LeaveType,
+		leave.StatusPending,
+		lr.StartDate,
+		lr.EndDate,
+		lr.Reason,
+		lr.DaysCount,
+		lr.DayPart,
+		lr.Hours,
+		lr.Notes,
+		lr.SalaryDeduction,
+		skippedDays,
+		now,
+		now,
+	}
+
+	ex := tx.GetTx()
 
 	if helpers.DBType == "sqlite" {
-		res, err := r.db.Exec(q,
-			lr.EmployeeID,
-			lr.LeaveType,
-			leave.StatusPending,
-			lr.StartDate,
-			lr.EndDate,
-			lr.Reason,
-			lr.DaysCount,
-			lr.Notes,
-			lr.SalaryDeduction,
-			now,
-			now,
-		)
+		res, err := ex.Exec(q, args...)
 		if err != nil {
 			return nil, errors.WrapError("failed to create leave request", err)
 		}
@@ -54,30 +64,17 @@ func (r *LeaveRepository) CreateLeaveRequest(lr *leave.LeaveRequest) (*leave.Lea
 			return nil, errors.WrapError("failed to get last insert id", err)
 		}
 		lr.ID = int(lastID)
-		lr.Status = leave.StatusPending
 		lr.CreatedAt = now
 		lr.UpdatedAt = now
-
-		return lr, nil
+	} else {
+		err := ex.QueryRow(q, args...).Scan(&lr.ID, &lr.CreatedAt, &lr.UpdatedAt)
+		if err != nil {
+			return nil, errors.WrapError("failed to create leave request", err)
+		}
 	}
 
-	err := r.db.QueryRow(
-		q,
-		lr.EmployeeID,
-		lr.LeaveType,
-		leave.StatusPending,
-		lr.StartDate,
-		lr.EndDate,
-		lr.Reason,
-		lr.DaysCount,
-		lr.Notes,
-		lr.SalaryDeduction,
-		now,
-		now,
-	).Scan(&lr.ID, &lr.CreatedAt, &lr.UpdatedAt)
-
-	if err != nil {
-		return nil, errors.WrapError("failed to create leave request", err)
+	if err := insertApprovals(ex, lr.ID, lr.Approvals, now); err != nil {
+		return nil, err
 	}
 
 	lr.Status = leave.StatusPending
@@ -86,9 +83,23 @@ func (r *LeaveRepository) CreateLeaveRequest(lr *leave.LeaveRequest) (*leave.Lea
 
 // GetLeaveRequest retrieves a leave request by ID
 func (r *LeaveRepository) GetLeaveRequest(id int) (*leave.LeaveRequest, error) {
+	return getLeaveRequest(r.db, id)
+}
+
+// LockLeaveRequest retrieves a leave request by ID and locks it until the transaction ends,
+// so concurrent decisions on the same request are applied one after the other
+func (r *LeaveRepository) LockLeaveRequest(tx *repositories.Transaction, id int) (*leave.LeaveRequest, error) {
+	if err := lockRow(tx.GetTx(), "leave_requests", "id = $1", id); err != nil {
+		return nil, err
+	}
+	return getLeaveRequest(tx.GetTx(), id)
+}
+
+// getLeaveRequest retrieves a leave request by ID
+func getLeaveRequest(ex dbExecutor, id int) (*leave.LeaveRequest, error) {
 	query := `
-		SELECT id, employee_id, leave_type, status, start_date, end_date, reason, days_count, 
-		       notes, approved_by, approval_date, salary_deduction, created_at, updated_at
+		SELECT id, employee_id, leave_type, status, start_date, end_date, reason, days_count, day_part, hours,
+		       notes, approved_by, approval_date, salary_deduction, skipped_days, created_at, updated_at
 		FROM leave_requests
 		WHERE id = $1
 	`
@@ -98,8 +109,9 @@ func (r *LeaveRepository) GetLeaveRequest(id int) (*leave.LeaveRequest, error) {
 	var approvedBy sql.NullInt64
 	var approvalDate sql.NullTime
 	var notes sql.NullString
+	var skippedDays sql.NullString
 
-	err := r.db.QueryRow(q, id).Scan(
+	err := ex.QueryRow(q, id).Scan(
 		&lr.ID,
 		&lr.EmployeeID,
 		&lr.LeaveType,
@@ -108,10 +120,13 @@ func (r *LeaveRepository) GetLeaveRequest(id int) (*leave.LeaveRequest, error) {
 		&lr.EndDate,
 		&lr.Reason,
 		&lr.DaysCount,
+		&lr.DayPart,
+		&lr.Hours,
 		&notes,
 		&approvedBy,
 		&approvalDate,
 		&lr.SalaryDeduction,
+		&skippedDays,
 		&lr.CreatedAt,
 		&lr.UpdatedAt,
 	)
@@ -133,6 +148,7 @@ func (r *LeaveRepository) GetLeaveRequest(id int) (*leave.LeaveRequest, error) {
 	if approvalDate.Valid {
 		lr.ApprovalDate = &approvalDate.Time
 	}
+	lr.SkippedDays = decodeSkippedDays(skippedDays)
 
 	return &lr, nil
 }
@@ -140,8 +156,8 @@ func (r *LeaveRepository) GetLeaveRequest(id int) (*leave.LeaveRequest, error) {
 // GetEmployeeLeaveRequests retrieves all leave requests for an employee
 func (r *LeaveRepository) GetEmployeeLeaveRequests(employeeID int) ([]leave.LeaveRequest, error) {
 	query := `
-		SELECT id, employee_id, leave_type, status, start_date, end_date, reason, days_count, 
-		       notes, approved_by, approval_date, salary_deduction, created_at, updated_at
+		SELECT id, employee_id, leave_type, status, start_date, end_date, reason, days_count, day_part, hours,
+		       notes, approved_by, approval_date, salary_deduction, skipped_days, created_at, updated_at
 		FROM leave_requests
 		WHERE employee_id = $1
 		ORDER BY created_at DESC
@@ -161,6 +177,7 @@ func (r *LeaveRepository) GetEmployeeLeaveRequests(employeeID int) ([]leave.Leav
 		var approvedBy sql.NullInt64
 		var approvalDate sql.NullTime
 		var notes sql.NullString
+		var skippedDays sql.NullString
 
 		err := rows.Scan(
 			&lr.ID,
@@ -171,10 +188,13 @@ func (r *LeaveRepository) GetEmployeeLeaveRequests(employeeID int) ([]leave.Leav
 			&lr.EndDate,
 			&lr.Reason,
 			&lr.DaysCount,
+			&lr.DayPart,
+			&lr.Hours,
 			&notes,
 			&approvedBy,
 			&approvalDate,
 			&lr.SalaryDeduction,
+			&skippedDays,
 			&lr.CreatedAt,
 			&lr.UpdatedAt,
 		)
@@ -193,7 +213,57 @@ func (r *LeaveRepository) GetEmployeeLeaveRequests(employeeID int) ([]leave.Leav
 		if approvalDate.Valid {
 			lr.ApprovalDate = &approvalDate.Time
 		}
+		lr.SkippedDays = decodeSkippedDays(skippedDays)
+
+		leaveRequests = append(leaveRequests, lr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave requests", err)
+	}
+
+	return leaveRequests, nil
+}
+
+// LockEmployeeLeave locks an employee's row until the transaction ends, so the leave requests of
+// one employee are applied and approved one at a time and overlap checks see each other's results
+func (r *LeaveRepository) LockEmployeeLeave(tx *repositories.Transaction, employeeID int) error {
+	return lockRow(tx.GetTx(), "employees", "id = $1", employeeID)
+}
+
+// GetLeaveRequestsInRange retrieves the leave requests of an employee in one of the given statuses
+// whose dates intersect the given period, within a transaction
+func (r *LeaveRepository) GetLeaveRequestsInRange(tx *repositories.Transaction, employeeID int, startDate, endDate time.Time, statuses []leave.LeaveStatus) ([]leave.LeaveRequest, error) {
+	args := []interface{}{employeeID}
+	placeholders := make([]string, len(statuses))
+	for i, status := range statuses {
+		args = append(args, status)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	args = append(args, endDate, startDate)
+
+	query := fmt.Sprintf(`
+		SELECT id, employee_id, leave_type, status, start_date, end_date, day_part, hours
+		FROM leave_requests
+		WHERE employee_id = $1 AND status IN (%s)
+		  AND start_date <= $%d AND end_date >= $%d
+		ORDER BY id
+	`, strings.Join(placeholders, ", "), len(args)-1, len(args))
+	q := convertPlaceholders(query)
 
+	rows, err := tx.GetTx().Query(q, args...)
+	if err != nil {
+		return nil, errors.WrapError("failed to query overlapping leave requests", err)
+	}
+	defer rows.Close()
+
+	var leaveRequests []leave.LeaveRequest
+	for rows.Next() {
+		var lr leave.LeaveRequest
+		err := rows.Scan(&lr.ID, &lr.EmployeeID, &lr.LeaveType, &lr.Status, &lr.StartDate, &lr.EndDate, &lr.DayPart, &lr.Hours)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave request", err)
+		}
 		leaveRequests = append(leaveRequests, lr)
 	}
 
@@ -213,7 +283,7 @@ func (r *LeaveRepository) GetAllLeaveRequests(status string) ([]leave.LeaveReque
 	if helpers.DBType == "sqlite" {
 		query = `
 			SELECT lr.id, lr.employee_id, lr.leave_type, lr.status, lr.start_date, lr.end_date, 
-			       lr.reason, lr.days_count, lr.notes, lr.approved_by, lr.approval_date, lr.created_at, lr.updated_at,
+			       lr.reason, lr.days_count, lr.day_part, lr.hours, lr.notes, lr.approved_by, lr.approval_date, lr.skipped_days, lr.created_at, lr.updated_at,
 			       (e.first_name || ' ' || e.last_name)
 			FROM leave_requests lr
 			JOIN employees e ON lr.employee_id = e.id
@@ -221,7 +291,7 @@ func (r *LeaveRepository) GetAllLeaveRequests(status string) ([]leave.LeaveReque
 	} else {
 		query = `
 			SELECT lr.id, lr.employee_id, lr.leave_type, lr.status, lr.start_date, lr.end_date, 
-			       lr.reason, lr.days_count, lr.notes, lr.approved_by, lr.approval_date, lr.created_at, lr.updated_at,
+			       lr.reason, lr.days_count, lr.day_part, lr.hours, lr.notes, lr.approved_by, lr.approval_date, lr.skipped_days, lr.created_at, lr.updated_at,
 			       CONCAT(e.first_name, ' ', e.last_name)
 			FROM leave_requests lr
 			JOIN employees e ON lr.employee_id = e.id
@@ -254,6 +324,7 @@ func (r *LeaveRepository) GetAllLeaveRequests(status string) ([]leave.LeaveReque
 		var approvedBy sql.NullInt64
 		var approvalDate sql.NullTime
 		var notes sql.NullString
+		var skippedDays sql.NullString
 
 		err := rows.Scan(
 			&lrd.ID,
@@ -264,9 +335,12 @@ func (r *LeaveRepository) GetAllLeaveRequests(status string) ([]leave.LeaveReque
 			&lrd.EndDate,
 			&lrd.Reason,
 			&lrd.DaysCount,
+			&lrd.DayPart,
+			&lrd.Hours,
 			&notes,
 			&approvedBy,
 			&approvalDate,
+			&skippedDays,
 			&lrd.CreatedAt,
 			&lrd.UpdatedAt,
 			&lrd.EmployeeName,
@@ -286,6 +360,7 @@ func (r *LeaveRepository) GetAllLeaveRequests(status string) ([]leave.LeaveReque
 		if approvalDate.Valid {
 			lrd.ApprovalDate = &approvalDate.Time
 		}
+		lrd.SkippedDays = decodeSkippedDays(skippedDays)
 
 		leaveRequests = append(leaveRequests, lrd)
 	}
@@ -297,8 +372,8 @@ func (r *LeaveRepository) GetAllLeaveRequests(status string) ([]leave.LeaveReque
 	return leaveRequests, nil
 }
 
-// UpdateLeaveRequestStatus updates the status of a leave request
-func (r *LeaveRepository) UpdateLeaveRequestStatus(id int, status string, approvedBy *int) error {
+// UpdateLeaveRequestStatus updates the status of a leave request within a transaction
+func (r *LeaveRepository) UpdateLeaveRequestStatus(tx *repositories.Transaction, id int, status string, approvedBy *int) error {
 	query := `
 		UPDATE leave_requests
 		SET status = $1, approved_by = $2, approval_date = $3, updated_at = $4
@@ -316,7 +391,7 @@ func (r *LeaveRepository) UpdateLeaveRequestStatus(id int, status string, approv
 		approvalDate = nil
 	}
 
-	result, err := r.db.Exec(
+	result, err := tx.GetTx().Exec(
 		q,
 		status,
 		approvedBy,
@@ -341,8 +416,8 @@ func (r *LeaveRepository) UpdateLeaveRequestStatus(id int, status string, approv
 	return nil
 }
 
-// CancelLeaveRequest cancels a leave request
-func (r *LeaveRepository) CancelLeaveRequest(id int) error {
+// CancelLeaveRequest cancels a leave request that is still in the given status within a transaction
+func (r *LeaveRepository) CancelLeaveRequest(tx *repositories.Transaction, id int, current leave.LeaveStatus) error {
 	query := `
 		UPDATE leave_requests
 		SET status = $1, updated_at = $2
@@ -352,12 +427,12 @@ func (r *LeaveRepository) CancelLeaveRequest(id int) error {
 
 	now := time.Now()
 
-	result, err := r.db.Exec(
+	result, err := tx.GetTx().Exec(
 		q,
 		leave.StatusCancelled,
 		now,
 		id,
-		leave.StatusPending,
+		current,
 	)
 
 	if err != nil {
@@ -371,7 +446,7 @@ func (r *LeaveRepository) CancelLeaveRequest(id int) error {
 
 	if rowsAffected == 0 {
 		validationErr := errors.NewValidationError()
-		validationErr.AddField("status", "only pending leave requests can be cancelled")
+		validationErr.AddField("status", fmt.Sprintf("only %s leave requests can be cancelled", strings.ToLower(string(current))))
 		return validationErr
 	}
 
@@ -381,9 +456,9 @@ func (r *LeaveRepository) CancelLeaveRequest(id int) error {
 // GetLeaveBalance retrieves the leave balance for an employee by leave type
 func (r *LeaveRepository) GetLeaveBalance(employeeID int, leaveType leave.LeaveType) (*leave.LeaveBalance, error) {
 	query := `
-		SELECT id, employee_id, leave_type, balance, created_at, updated_at
-		FROM leave_balances
-		WHERE employee_id = $1 AND leave_type = $2
+		SELECT lb.id, lb.employee_id, lb.leave_type, ` + ledgerBalanceSQL + `, lb.leave_year, lb.created_at, lb.updated_at
+		FROM leave_balances lb
+		WHERE lb.employee_id = $1 AND lb.leave_type = $2
 	`
 	q := convertPlaceholders(query)
 
@@ -394,6 +469,7 @@ func (r *LeaveRepository) GetLeaveBalance(employeeID int, leaveType leave.LeaveT
 		&lb.EmployeeID,
 		&lb.LeaveType,
 		&lb.Balance,
+		&lb.LeaveYear,
 		&lb.CreatedAt,
 		&lb.UpdatedAt,
 	)
@@ -404,6 +480,7 @@ func (r *LeaveRepository) GetLeaveBalance(employeeID int, leaveType leave.LeaveT
 	if err != nil {
 		return nil, errors.WrapError("failed to get leave balance", err)
 	}
+	lb.Balance = leave.RoundDays(lb.Balance)
 
 	return &lb, nil
 }
@@ -411,10 +488,10 @@ func (r *LeaveRepository) GetLeaveBalance(employeeID int, leaveType leave.LeaveT
 // GetEmployeeLeaveBalances retrieves all leave balances for an employee
 func (r *LeaveRepository) GetEmployeeLeaveBalances(employeeID int) ([]leave.LeaveBalance, error) {
 	query := `
-		SELECT id, employee_id, leave_type, balance, created_at, updated_at
-		FROM leave_balances
-		WHERE employee_id = $1
-		ORDER BY leave_type
+		SELECT lb.id, lb.employee_id, lb.leave_type, ` + ledgerBalanceSQL + `, lb.leave_year, lb.created_at, lb.updated_at
+		FROM leave_balances lb
+		WHERE lb.employee_id = $1
+		ORDER BY lb.leave_type
 	`
 	q := convertPlaceholders(query)
 
@@ -434,6 +511,7 @@ func (r *LeaveRepository) GetEmployeeLeaveBalances(employeeID int) ([]leave.Leav
 			&lb.EmployeeID,
 			&lb.LeaveType,
 			&lb.Balance,
+			&lb.LeaveYear,
 			&lb.CreatedAt,
 			&lb.UpdatedAt,
 		)
@@ -441,6 +519,7 @@ func (r *LeaveRepository) GetEmployeeLeaveBalances(employeeID int) ([]leave.Leav
 		if err != nil {
 			return nil, errors.WrapError("failed to scan leave balance", err)
 		}
+		lb.Balance = leave.RoundDays(lb.Balance)
 
 		balances = append(balances, lb)
 	}
@@ -452,8 +531,9 @@ func (r *LeaveRepository) GetEmployeeLeaveBalances(employeeID int) ([]leave.Leav
 	return balances, nil
 }
 
-// InitializeLeaveBalances initializes default leave balances for a new employee
-func (r *LeaveRepository) InitializeLeaveBalances(employeeID int) error {
+// InitializeLeaveBalances creates empty balances for a new employee for each given policy.
+// Days are credited afterwards through the leave ledger.
+func (r *LeaveRepository) InitializeLeaveBalances(employeeID int, policies []leave.LeavePolicy) error {
 	// First verify employee exists
 	var empExists int
 	err := r.db.QueryRow("SELECT 1 FROM employees WHERE id = $1", employeeID).Scan(&empExists)
@@ -464,22 +544,21 @@ func (r *LeaveRepository) InitializeLeaveBalances(employeeID int) error {
 		return errors.WrapError("failed to verify employee", err)
 	}
 
-	defaultBalances := leave.DefaultLeaveBalances()
 	now := time.Now()
 
-	for leaveType, balance := range defaultBalances {
+	for _, policy := range policies {
 		// Try to insert - if it already exists (ON CONFLICT), skip it silently
 		query := `
-			INSERT INTO leave_balances (employee_id, leave_type, balance, created_at, updated_at)
+			INSERT INTO leave_balances (employee_id, leave_type, leave_year, created_at, updated_at)
 			VALUES ($1, $2, $3, $4, $5)
-			ON CONFLICT (employee_id, leave_type) DO UPDATE SET updated_at = $5
+			ON CONFLICT (employee_id, leave_type) DO UPDATE SET updated_at = excluded.updated_at
 		`
 		q := convertPlaceholders(query)
 
-		_, err := r.db.Exec(q, employeeID, leaveType, balance, now, now)
+		_, err := r.db.Exec(q, employeeID, policy.LeaveType, now.Year(), now, now)
 		if err != nil {
 			// Log error but continue with other types
-			errors.LogError(fmt.Sprintf("Failed to initialize %s balance for employee %d", leaveType, employeeID), err)
+			errors.LogError(fmt.Sprintf("Failed to initialize %s balance for employee %d", policy.LeaveType, employeeID), err)
 			// Don't return - continue with other leave types
 		}
 	}
@@ -487,48 +566,73 @@ func (r *LeaveRepository) InitializeLeaveBalances(employeeID int) error {
 	return nil
 }
 
-// UpdateLeaveBalance updates the leave balance for an employee
-func (r *LeaveRepository) UpdateLeaveBalance(employeeID int, leaveType leave.LeaveType, newBalance int) error {
-	query := `
-		UPDATE leave_balances
-		SET balance = $1, updated_at = $2
-		WHERE employee_id = $3 AND leave_type = $4
-	`
-	q := convertPlaceholders(query)
+// AdjustLeaveBalance records a manual adjustment of an employee's leave balance.
+// Adjustments that would make the balance negative are rejected.
+func (r *LeaveRepository) AdjustLeaveBalance(employeeID int, leaveType leave.LeaveType, days float64, actorUserID int, reason string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.WrapError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
 
-	now := time.Now()
+	if err := lockLeaveBalance(tx, employeeID, leaveType); err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(q, newBalance, now, employeeID, leaveType)
+	balance, err := ledgerBalance(tx, employeeID, leaveType)
 	if err != nil {
-		return errors.WrapError("failed to update leave balance", err)
+		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	entry, err := leave.NewAdjustmentEntry(employeeID, leaveType, days, balance, actorUserID, reason)
 	if err != nil {
-		return errors.WrapError("failed to get rows affected", err)
+		return err
+	}
+	if err := insertLedgerEntry(tx, entry); err != nil {
+		return err
 	}
 
-	if rowsAffected == 0 {
-		return errors.NewNotFoundError("leave balance not found")
+	if err := tx.Commit(); err != nil {
+		return errors.WrapError("failed to commit transaction", err)
 	}
 
 	return nil
 }
 
-// DeductLeaveBalance deducts the specified days from the employee's leave balance
-func (r *LeaveRepository) DeductLeaveBalance(employeeID int, leaveType leave.LeaveType, days int) error {
+// DeductLeaveBalance debits the days of an approved leave request from the employee's leave balance
+// within a transaction. The balance row stays locked until the transaction ends.
+func (r *LeaveRepository) DeductLeaveBalance(tx *repositories.Transaction, lr *leave.LeaveRequest, approvedByUserID int) error {
+	ex := tx.GetTx()
+	if err := lockLeaveBalance(ex, lr.EmployeeID, lr.LeaveType); err != nil {
+		return err
+	}
+
+	balance, err := ledgerBalance(ex, lr.EmployeeID, lr.LeaveType)
+	if err != nil {
+		return err
+	}
+
+	entry, err := leave.NewDebitEntry(lr, balance, approvedByUserID)
+	if err != nil {
+		return err
+	}
+	return insertLedgerEntry(ex, entry)
+}
+
+// UpdateLeaveRequestNotes updates the notes field of a leave request within a transaction
+func (r *LeaveRepository) UpdateLeaveRequestNotes(tx *repositories.Transaction, id int, notes string) error {
 	query := `
-		UPDATE leave_balances
-		SET balance = balance - $1, updated_at = $2
-		WHERE employee_id = $3 AND leave_type = $4 AND balance >= $1
+		UPDATE leave_requests
+		SET notes = $1, updated_at = $2
+		WHERE id = $3
 	`
 	q := convertPlaceholders(query)
 
 	now := time.Now()
 
-	result, err := r.db.Exec(q, days, now, employeeID, leaveType)
+	result, err := tx.GetTx().Exec(q, notes, now, id)
 	if err != nil {
-		return errors.WrapError("failed to deduct leave balance", err)
+		return errors.WrapError("failed to update leave request notes", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -537,25 +641,24 @@ func (r *LeaveRepository) DeductLeaveBalance(employeeID int, leaveType leave.Lea
 	}
 
 	if rowsAffected == 0 {
-		return errors.NewValidationError().AddField("balance", "insufficient leave balance")
+		return errors.NewNotFoundError("leave request not found")
 	}
 
 	return nil
 }
-// UpdateLeaveRequestNotes updates the notes field of a leave request
-func (r *LeaveRepository) UpdateLeaveRequestNotes(id int, notes string) error {
+
+// UpdateLeaveRequestDeduction sets the salary deduction of a leave request within a transaction
+func (r *LeaveRepository) UpdateLeaveRequestDeduction(tx *repositories.Transaction, id int, amount float64) error {
 	query := `
 		UPDATE leave_requests
-		SET notes = $1, updated_at = $2
+		SET salary_deduction = $1, updated_at = $2
 		WHERE id = $3
 	`
 	q := convertPlaceholders(query)
 
-	now := time.Now()
-
-	result, err := r.db.Exec(q, notes, now, id)
+	result, err := tx.GetTx().Exec(q, amount, time.Now(), id)
 	if err != nil {
-		return errors.WrapError("failed to update leave request notes", err)
+		return errors.WrapError("failed to update leave request salary deduction", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -568,4 +671,30 @@ func (r *LeaveRepository) UpdateLeaveRequestNotes(id int, notes string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// encodeSkippedDays serializes the skipped day breakdown for storage
+func encodeSkippedDays(days []leave.SkippedDay) string {
+	if len(days) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(days)
+	if err != nil {
+		errors.LogError("Failed to encode skipped days", err)
+		return "[]"
+	}
+	return string(data)
+}
+
+// decodeSkippedDays deserializes the stored skipped day breakdown
+func decodeSkippedDays(value sql.NullString) []leave.SkippedDay {
+	days := []leave.SkippedDay{}
+	if !value.Valid || value.String == "" {
+		return days
+	}
+	if err := json.Unmarshal([]byte(value.String), &days); err != nil {
+		errors.LogError("Failed to decode skipped days", err)
+		return []leave.SkippedDay{}
+	}
+	return days
+}
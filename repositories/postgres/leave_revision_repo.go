@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+)
+
+// UpdateLeaveRequest stores the edited fields of a pending leave request within a transaction
+func (r *LeaveRepository) UpdateLeaveRequest(tx *repositories.Transaction, lr *leave.LeaveRequest) error {
+	query := `
+		UPDATE leave_requests
+		SET leave_type = $1, start_date = $2, end_date = $3, reason = $4, days_count = $5, day_part = $6,
+		    hours = $7, notes = $8, salary_deduction = $9, skipped_days = $10, updated_at = $11
+		WHERE id = $12 AND status = $13
+	`
+	q := convertPlaceholders(query)
+
+	now := time.Now()
+	result, err := tx.GetTx().Exec(q,
+		lr.LeaveType,
+		lr.StartDate,
+		lr.EndDate,
+		lr.Reason,
+		lr.DaysCount,
+		lr.DayPart,
+		lr.Hours,
+		lr.Notes,
+		lr.SalaryDeduction,
+		encodeSkippedDays(lr.SkippedDays),
+		now,
+		lr.ID,
+		leave.StatusPending,
+	)
+	if err != nil {
+		return errors.WrapError("failed to update leave request", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewValidationError().AddField("status", "only pending leave requests can be edited")
+	}
+
+	lr.UpdatedAt = now
+	return nil
+}
+
+// CreateRevision records an edit of a leave request within a transaction, numbering it after
+// the request's previous revisions
+func (r *LeaveRepository) CreateRevision(tx *repositories.Transaction, rev *leave.LeaveRequestRevision) error {
+	ex := tx.GetTx()
+
+	err := ex.QueryRow(convertPlaceholders(`
+		SELECT COALESCE(MAX(revision), 0) + 1 FROM leave_request_revisions WHERE leave_request_id = $1
+	`), rev.LeaveRequestID).Scan(&rev.Revision)
+	if err != nil {
+		return errors.WrapError("failed to number leave request revision", err)
+	}
+
+	changes, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return errors.WrapError("failed to encode leave request changes", err)
+	}
+
+	rev.CreatedAt = time.Now()
+	_, err = ex.Exec(convertPlaceholders(`
+		INSERT INTO leave_request_revisions (leave_request_id, revision, edited_by, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`), rev.LeaveRequestID, rev.Revision, rev.EditedBy, string(changes), rev.CreatedAt)
+	if err != nil {
+		return errors.WrapError("failed to create leave request revision", err)
+	}
+	return nil
+}
+
+// GetRevisions retrieves the edits of a leave request, oldest first
+func (r *LeaveRepository) GetRevisions(leaveRequestID int) ([]leave.LeaveRequestRevision, error) {
+	query := `
+		SELECT id, leave_request_id, revision, edited_by, changes, created_at
+		FROM leave_request_revisions
+		WHERE leave_request_id = $1
+		ORDER BY revision
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, leaveRequestID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave request revisions", err)
+	}
+	defer rows.Close()
+
+	revisions := []leave.LeaveRequestRevision{}
+
+	for rows.Next() {
+		var rev leave.LeaveRequestRevision
+		var changes string
+		if err := rows.Scan(&rev.ID, &rev.LeaveRequestID, &rev.Revision, &rev.EditedBy, &changes, &rev.CreatedAt); err != nil {
+			return nil, errors.WrapError("failed to scan leave request revision", err)
+		}
+
+		rev.Changes = []leave.FieldChange{}
+		if err := json.Unmarshal([]byte(changes), &rev.Changes); err != nil {
+			errors.LogError("Failed to decode leave request changes", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave request revisions", err)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/payroll"
+)
+
+// LeaveRolloverRepository handles database operations for year-end leave rollovers
+type LeaveRolloverRepository struct {
+	db *sql.DB
+}
+
+// NewLeaveRolloverRepository creates a new leave rollover repository
+func NewLeaveRolloverRepository(db *sql.DB) *LeaveRolloverRepository {
+	return &LeaveRolloverRepository{db: db}
+}
+
+// GetYearEndBalances retrieves the balances still belonging to the given leave year as they
+// stood at its last day. Ledger entries effective after the year end, such as accruals for
+// the next year or leave taken in January, are left out however early they were recorded.
+func (r *LeaveRolloverRepository) GetYearEndBalances(year int) ([]leave.YearEndBalance, error) {
+	query := `
+		SELECT lb.employee_id, e.first_name, e.last_name, lb.leave_type,
+		       COALESCE((SELECT SUM(ll.days) FROM leave_ledger ll
+		                 WHERE ll.employee_id = lb.employee_id AND ll.leave_type = lb.leave_type
+		                   AND ll.effective_date <= $1), 0)
+		FROM leave_balances lb
+		JOIN employees e ON e.id = lb.employee_id
+		WHERE lb.leave_year = $2
+		ORDER BY lb.employee_id, lb.leave_type
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, leave.YearEndDate(year), year)
+	if err != nil {
+		return nil, errors.WrapError("failed to query year-end balances", err)
+	}
+	defer rows.Close()
+
+	balances := []leave.YearEndBalance{}
+
+	for rows.Next() {
+		var b leave.YearEndBalance
+		var firstName, lastName string
+		err := rows.Scan(
+			&b.EmployeeID,
+			&firstName,
+			&lastName,
+			&b.LeaveType,
+			&b.Balance,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan year-end balance", err)
+		}
+		b.EmployeeName = firstName + " " + lastName
+		b.Balance = leave.RoundDays(b.Balance)
+		balances = append(balances, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating year-end balances", err)
+	}
+
+	return balances, nil
+}
+
+// ApplyRollover records the rollover, writes the lapsed and encashed days to the leave
+// ledger, credits the encashment to payroll when there is one and moves the balance into
+// the next leave year in a single transaction. It returns false without changing anything
+// if the balance was already rolled over.
+func (r *LeaveRolloverRepository) ApplyRollover(rollover *leave.LeaveRollover, encashment *payroll.PayrollAdjustment) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, errors.WrapError("failed to begin rollover transaction", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	result, err := tx.Exec(convertPlaceholders(`
+		UPDATE leave_balances
+		SET leave_year = $1, updated_at = $2
+		WHERE employee_id = $3 AND leave_type = $4 AND leave_year = $5
+	`), rollover.Year+1, now, rollover.EmployeeID, rollover.LeaveType, rollover.Year)
+	if err != nil {
+		return false, errors.WrapError("failed to roll over leave balance", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.WrapError("failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	rollover.CreatedAt = now
+	query := `
+		INSERT INTO leave_rollovers (employee_id, leave_type, year, closing_balance, carried_forward,
+		                             lapsed, encashed, encash_amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err = tx.Exec(convertPlaceholders(query),
+		rollover.EmployeeID,
+		rollover.LeaveType,
+		rollover.Year,
+		rollover.ClosingBalance,
+		rollover.CarriedForward,
+		rollover.Lapsed,
+		rollover.Encashed,
+		rollover.EncashAmount,
+		now,
+	)
+	if err != nil {
+		return false, errors.WrapError("failed to record leave rollover", err)
+	}
+
+	reference := fmt.Sprintf("rollover:%d", rollover.Year)
+	yearEnd := leave.YearEndDate(rollover.Year)
+	if rollover.Lapsed > 0 {
+		err = insertLedgerEntry(tx, &leave.LeaveLedgerEntry{
+			EmployeeID:    rollover.EmployeeID,
+			LeaveType:     rollover.LeaveType,
+			EntryType:     leave.LedgerExpiry,
+			Days:          -rollover.Lapsed,
+			Reference:     reference,
+			Description:   fmt.Sprintf("Lapsed at the end of %d", rollover.Year),
+			EffectiveDate: yearEnd,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	if rollover.Encashed > 0 {
+		err = insertLedgerEntry(tx, &leave.LeaveLedgerEntry{
+			EmployeeID:    rollover.EmployeeID,
+			LeaveType:     rollover.LeaveType,
+			EntryType:     leave.LedgerDebit,
+			Days:          -rollover.Encashed,
+			Reference:     reference,
+			Description:   fmt.Sprintf("Encashed at the end of %d (%.2f)", rollover.Year, rollover.EncashAmount),
+			EffectiveDate: yearEnd,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	if encashment != nil {
+		encashment.CreatedAt = now
+		if err := insertAdjustment(tx, encashment); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, errors.WrapError("failed to commit rollover transaction", err)
+	}
+
+	return true, nil
+}
+
+// GetRollovers retrieves the recorded rollovers for a leave year
+func (r *LeaveRolloverRepository) GetRollovers(year int) ([]leave.LeaveRollover, error) {
+	query := `
+		SELECT lr.id, lr.employee_id, e.first_name, e.last_name, lr.leave_type, lr.year, lr.closing_balance,
+		       lr.carried_forward, lr.lapsed, lr.encashed, lr.encash_amount, lr.created_at
+		FROM leave_rollovers lr
+		JOIN employees e ON e.id = lr.employee_id
+		WHERE lr.year = $1
+		ORDER BY lr.employee_id, lr.leave_type
+	`
+	q := convertPlaceholders(query)
+
+	rows, err := r.db.Query(q, year)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave rollovers", err)
+	}
+	defer rows.Close()
+
+	rollovers := []leave.LeaveRollover{}
+
+	for rows.Next() {
+		var lr leave.LeaveRollover
+		var firstName, lastName string
+		err := rows.Scan(
+			&lr.ID,
+			&lr.EmployeeID,
+			&firstName,
+			&lastName,
+			&lr.LeaveType,
+			&lr.Year,
+			&lr.ClosingBalance,
+			&lr.CarriedForward,
+			&lr.Lapsed,
+			&lr.Encashed,
+			&lr.EncashAmount,
+			&lr.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan leave rollover", err)
+		}
+		lr.EmployeeName = firstName + " " + lastName
+		rollovers = append(rollovers, lr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating leave rollovers", err)
+	}
+
+	return rollovers, nil
+}
+
+// CountRollovers returns how many balances were already rolled over for a leave year
+func (r *LeaveRolloverRepository) CountRollovers(year int) (int, error) {
+	var count int
+	err := r.db.QueryRow(convertPlaceholders("SELECT COUNT(*) FROM leave_rollovers WHERE year = $1"), year).Scan(&count)
+	if err != nil {
+		return 0, errors.WrapError("failed to count leave rollovers", err)
+	}
+	return count, nil
+}
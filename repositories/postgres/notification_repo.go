@@ -35,8 +35,9 @@ func (r *NotificationRepository) CreateNotification(n *notification.Notification
 			leave_request_id, recipient_email, recipient_name, event_type, 
 			template_name, delivery_channel, status, subject, body, 
 			retry_count, max_retries, error_message, sent_at, next_retry_at, 
+			attachment_name, attachment_type, attachment_content,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -56,6 +57,9 @@ func (r *NotificationRepository) CreateNotification(n *notification.Notification
 		n.ErrorMessage,
 		n.SentAt,
 		n.NextRetryAt,
+		n.AttachmentName,
+		n.AttachmentType,
+		n.AttachmentContent,
 		n.CreatedAt,
 		n.UpdatedAt,
 	).Scan(&n.ID, &n.CreatedAt, &n.UpdatedAt)
@@ -75,6 +79,7 @@ func (r *NotificationRepository) GetNotification(id int) (*notification.Notifica
 		SELECT id, leave_request_id, recipient_email, recipient_name, event_type, 
 		       template_name, delivery_channel, status, subject, body, 
 		       retry_count, max_retries, error_message, sent_at, next_retry_at, 
+		       attachment_name, attachment_type, attachment_content,
 		       created_at, updated_at
 		FROM notifications WHERE id = $1
 	`
@@ -84,6 +89,7 @@ func (r *NotificationRepository) GetNotification(id int) (*notification.Notifica
 		&n.ID, &n.LeaveRequestID, &n.RecipientEmail, &n.RecipientName, &n.EventType,
 		&n.TemplateName, &n.DeliveryChannel, &n.Status, &n.Subject, &n.Body,
 		&n.RetryCount, &n.MaxRetries, &n.ErrorMessage, &n.SentAt, &n.NextRetryAt,
+		&n.AttachmentName, &n.AttachmentType, &n.AttachmentContent,
 		&n.CreatedAt, &n.UpdatedAt,
 	)
 
@@ -103,6 +109,7 @@ func (r *NotificationRepository) GetPendingNotifications() ([]notification.Notif
 		SELECT id, leave_request_id, recipient_email, recipient_name, event_type, 
 		       template_name, delivery_channel, status, subject, body, 
 		       retry_count, max_retries, error_message, sent_at, next_retry_at, 
+		       attachment_name, attachment_type, attachment_content,
 		       created_at, updated_at
 		FROM notifications 
 		WHERE status IN ($1, $2)
@@ -125,6 +132,7 @@ func (r *NotificationRepository) GetPendingNotifications() ([]notification.Notif
 			&n.ID, &n.LeaveRequestID, &n.RecipientEmail, &n.RecipientName, &n.EventType,
 			&n.TemplateName, &n.DeliveryChannel, &n.Status, &n.Subject, &n.Body,
 			&n.RetryCount, &n.MaxRetries, &n.ErrorMessage, &n.SentAt, &n.NextRetryAt,
+			&n.AttachmentName, &n.AttachmentType, &n.AttachmentContent,
 			&n.CreatedAt, &n.UpdatedAt,
 		)
 		if err != nil {
@@ -198,6 +206,7 @@ func (r *NotificationRepository) GetNotificationsByEvent(eventType notification.
 		SELECT id, leave_request_id, recipient_email, recipient_name, event_type, 
 		       template_name, delivery_channel, status, subject, body, 
 		       retry_count, max_retries, error_message, sent_at, next_retry_at, 
+		       attachment_name, attachment_type, attachment_content,
 		       created_at, updated_at
 		FROM notifications 
 		WHERE event_type = $1
@@ -218,6 +227,7 @@ func (r *NotificationRepository) GetNotificationsByEvent(eventType notification.
 			&n.ID, &n.LeaveRequestID, &n.RecipientEmail, &n.RecipientName, &n.EventType,
 			&n.TemplateName, &n.DeliveryChannel, &n.Status, &n.Subject, &n.Body,
 			&n.RetryCount, &n.MaxRetries, &n.ErrorMessage, &n.SentAt, &n.NextRetryAt,
+			&n.AttachmentName, &n.AttachmentType, &n.AttachmentContent,
 			&n.CreatedAt, &n.UpdatedAt,
 		)
 		if err != nil {
@@ -235,6 +245,7 @@ func (r *NotificationRepository) GetFailedNotifications() ([]notification.Notifi
 		SELECT id, leave_request_id, recipient_email, recipient_name, event_type, 
 		       template_name, delivery_channel, status, subject, body, 
 		       retry_count, max_retries, error_message, sent_at, next_retry_at, 
+		       attachment_name, attachment_type, attachment_content,
 		       created_at, updated_at
 		FROM notifications 
 		WHERE status = $1 OR (retry_count >= max_retries AND status != $2)
@@ -255,6 +266,7 @@ func (r *NotificationRepository) GetFailedNotifications() ([]notification.Notifi
 			&n.ID, &n.LeaveRequestID, &n.RecipientEmail, &n.RecipientName, &n.EventType,
 			&n.TemplateName, &n.DeliveryChannel, &n.Status, &n.Subject, &n.Body,
 			&n.RetryCount, &n.MaxRetries, &n.ErrorMessage, &n.SentAt, &n.NextRetryAt,
+			&n.AttachmentName, &n.AttachmentType, &n.AttachmentContent,
 			&n.CreatedAt, &n.UpdatedAt,
 		)
 		if err != nil {
@@ -265,3 +277,25 @@ func (r *NotificationRepository) GetFailedNotifications() ([]notification.Notifi
 
 	return notifications, nil
 }
+
+// ClaimLowBalanceNotice records a low-balance warning for an employee, leave type and period.
+// It returns false if one was already recorded, so each employee is warned at most once per period.
+func (r *NotificationRepository) ClaimLowBalanceNotice(employeeID int, leaveType string, period string, balance float64) (bool, error) {
+	query := `
+		INSERT INTO low_balance_notices (employee_id, leave_type, period, balance, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (employee_id, leave_type, period) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, employeeID, leaveType, period, balance, time.Now())
+	if err != nil {
+		return false, errors.WrapError("failed to record low balance notice", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.WrapError("failed to get rows affected", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/payroll"
+	"employee-service/repositories"
+)
+
+// PayrollRepository handles database operations for payroll adjustments
+type PayrollRepository struct {
+	db *sql.DB
+}
+
+// NewPayrollRepository creates a new payroll repository
+func NewPayrollRepository(db *sql.DB) *PayrollRepository {
+	return &PayrollRepository{db: db}
+}
+
+// adjustmentColumns selects a payroll adjustment from payroll_adjustments pa joined with its
+// employee e
+const adjustmentColumns = `pa.id, pa.employee_id, e.first_name || ' ' || e.last_name, pa.pay_period, pa.kind,
+		       pa.days, pa.daily_rate, pa.amount, pa.leave_request_id, pa.description, pa.created_by, pa.payroll_run_id, pa.created_at`
+
+// adjustmentFrom is the FROM clause for adjustmentColumns
+const adjustmentFrom = `payroll_adjustments pa JOIN employees e ON e.id = pa.employee_id`
+
+// CreateAdjustments records payroll adjustments within a transaction
+func (r *PayrollRepository) CreateAdjustments(tx *repositories.Transaction, adjustments []payroll.PayrollAdjustment) error {
+	now := time.Now()
+	for i := range adjustments {
+		adjustments[i].CreatedAt = now
+		if err := insertAdjustment(tx.GetTx(), &adjustments[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertAdjustment records a payroll adjustment with the given executor
+func insertAdjustment(ex dbExecutor, a *payroll.PayrollAdjustment) error {
+	query := convertPlaceholders(`
+		INSERT INTO payroll_adjustments (employee_id, pay_period, kind, days, daily_rate, amount,
+		                                 leave_request_id, description, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`)
+
+	_, err := ex.Exec(query,
+		a.EmployeeID,
+		a.PayPeriod,
+		a.Kind,
+		a.Days,
+		a.DailyRate,
+		a.Amount,
+		a.LeaveRequestID,
+		a.Description,
+		a.CreatedBy,
+		a.CreatedAt,
+	)
+	if err != nil {
+		return errors.WrapError("failed to create payroll adjustment", err)
+	}
+	return nil
+}
+
+// GetLeaveAdjustments retrieves the payroll adjustments of a leave request within a transaction
+func (r *PayrollRepository) GetLeaveAdjustments(tx *repositories.Transaction, leaveRequestID int) ([]payroll.PayrollAdjustment, error) {
+	query := `
+		SELECT ` + adjustmentColumns + `
+		FROM ` + adjustmentFrom + `
+		WHERE pa.leave_request_id = $1
+		ORDER BY pa.pay_period, pa.id
+	`
+	rows, err := tx.GetTx().Query(convertPlaceholders(query), leaveRequestID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query leave payroll adjustments", err)
+	}
+	return scanAdjustments(rows)
+}
+
+// ListAdjustments retrieves the payroll adjustments of a pay period, grouped by employee
+func (r *PayrollRepository) ListAdjustments(period string) ([]payroll.PayrollAdjustment, error) {
+	query := `
+		SELECT ` + adjustmentColumns + `
+		FROM ` + adjustmentFrom + `
+		WHERE pa.pay_period = $1
+		ORDER BY e.last_name, e.first_name, pa.employee_id, pa.id
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), period)
+	if err != nil {
+		return nil, errors.WrapError("failed to query payroll adjustments", err)
+	}
+	return scanAdjustments(rows)
+}
+
+// scanAdjustments scans and closes rows selected with adjustmentColumns
+func scanAdjustments(rows *sql.Rows) ([]payroll.PayrollAdjustment, error) {
+	defer rows.Close()
+
+	adjustments := []payroll.PayrollAdjustment{}
+	for rows.Next() {
+		var a payroll.PayrollAdjustment
+		var leaveRequestID, createdBy, payrollRunID sql.NullInt64
+		err := rows.Scan(
+			&a.ID,
+			&a.EmployeeID,
+			&a.EmployeeName,
+			&a.PayPeriod,
+			&a.Kind,
+			&a.Days,
+			&a.DailyRate,
+			&a.Amount,
+			&leaveRequestID,
+			&a.Description,
+			&createdBy,
+			&payrollRunID,
+			&a.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan payroll adjustment", err)
+		}
+		if leaveRequestID.Valid {
+			id := int(leaveRequestID.Int64)
+			a.LeaveRequestID = &id
+		}
+		if createdBy.Valid {
+			id := int(createdBy.Int64)
+			a.CreatedBy = &id
+		}
+		if payrollRunID.Valid {
+			id := int(payrollRunID.Int64)
+			a.PayrollRunID = &id
+		}
+		adjustments = append(adjustments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating payroll adjustments", err)
+	}
+	return adjustments, nil
+}
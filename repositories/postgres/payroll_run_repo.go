@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/payroll"
+	"employee-service/repositories"
+	"employee-service/utils/helpers"
+)
+
+// payrollRunColumns selects a payroll run from payroll_runs
+const payrollRunColumns = `id, pay_period, COALESCE(run_by, 0), employee_count, total_gross, total_adjustments, total_net, created_at`
+
+// payslipColumns selects a payslip from payslips
+const payslipColumns = `id, payroll_run_id, employee_id, employee_name, position, pay_period, gross_pay,
+		       total_adjustments, net_pay, created_at`
+
+// CreateRun records the payroll run of a pay period within a transaction, which locks the period
+func (r *PayrollRepository) CreateRun(tx *repositories.Transaction, run *payroll.PayrollRun) error {
+	query := `
+		INSERT INTO payroll_runs (pay_period, run_by, created_at)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	q := convertPlaceholders(query)
+
+	run.CreatedAt = time.Now()
+
+	if helpers.DBType == "sqlite" {
+		res, err := tx.GetTx().Exec(q, run.PayPeriod, run.RunBy, run.CreatedAt)
+		if err != nil {
+			return errors.WrapError("failed to create payroll run", err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return errors.WrapError("failed to get last insert id", err)
+		}
+		run.ID = int(lastID)
+		return nil
+	}
+
+	if err := tx.GetTx().QueryRow(q, run.PayPeriod, run.RunBy, run.CreatedAt).Scan(&run.ID); err != nil {
+		return errors.WrapError("failed to create payroll run", err)
+	}
+	return nil
+}
+
+// UpdateRunTotals stores the totals of a payroll run within a transaction
+func (r *PayrollRepository) UpdateRunTotals(tx *repositories.Transaction, run *payroll.PayrollRun) error {
+	query := `
+		UPDATE payroll_runs
+		SET employee_count = $1, total_gross = $2, total_adjustments = $3, total_net = $4
+		WHERE id = $5
+	`
+	_, err := tx.GetTx().Exec(convertPlaceholders(query),
+		run.EmployeeCount, run.TotalGross, run.TotalAdjustments, run.TotalNet, run.ID)
+	if err != nil {
+		return errors.WrapError("failed to update payroll run totals", err)
+	}
+	return nil
+}
+
+// GetRun retrieves the payroll run of a pay period
+func (r *PayrollRepository) GetRun(period string) (*payroll.PayrollRun, error) {
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs WHERE pay_period = $1`
+
+	run, err := scanPayrollRun(r.db.QueryRow(convertPlaceholders(query), period))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("payroll run not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to get payroll run", err)
+	}
+	return run, nil
+}
+
+// ListRuns retrieves all payroll runs, latest period first
+func (r *PayrollRepository) ListRuns() ([]payroll.PayrollRun, error) {
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs ORDER BY pay_period DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, errors.WrapError("failed to query payroll runs", err)
+	}
+	defer rows.Close()
+
+	runs := []payroll.PayrollRun{}
+	for rows.Next() {
+		run, err := scanPayrollRun(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan payroll run", err)
+		}
+		runs = append(runs, *run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating payroll runs", err)
+	}
+	return runs, nil
+}
+
+// ClaimAdjustments marks the unpaid adjustments of a pay period and earlier periods as paid by a
+// payroll run within a transaction. Adjustments committed later stay unpaid for the next run.
+func (r *PayrollRepository) ClaimAdjustments(tx *repositories.Transaction, runID int, period string) error {
+	query := `
+		UPDATE payroll_adjustments
+		SET payroll_run_id = $1
+		WHERE payroll_run_id IS NULL AND pay_period <= $2
+	`
+	if _, err := tx.GetTx().Exec(convertPlaceholders(query), runID, period); err != nil {
+		return errors.WrapError("failed to claim payroll adjustments", err)
+	}
+	return nil
+}
+
+// GetRunAdjustments retrieves the adjustments paid by a payroll run within a transaction
+func (r *PayrollRepository) GetRunAdjustments(tx *repositories.Transaction, runID int) ([]payroll.PayrollAdjustment, error) {
+	query := `
+		SELECT ` + adjustmentColumns + `
+		FROM ` + adjustmentFrom + `
+		WHERE pa.payroll_run_id = $1
+		ORDER BY pa.employee_id, pa.pay_period, pa.id
+	`
+	rows, err := tx.GetTx().Query(convertPlaceholders(query), runID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query payroll run adjustments", err)
+	}
+	return scanAdjustments(rows)
+}
+
+// CreatePayslip records a payslip of a payroll run within a transaction
+func (r *PayrollRepository) CreatePayslip(tx *repositories.Transaction, p *payroll.Payslip) error {
+	query := `
+		INSERT INTO payslips (payroll_run_id, employee_id, employee_name, position, pay_period, gross_pay,
+		                      total_adjustments, net_pay, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	p.CreatedAt = time.Now()
+
+	_, err := tx.GetTx().Exec(convertPlaceholders(query),
+		p.PayrollRunID,
+		p.EmployeeID,
+		p.EmployeeName,
+		p.Position,
+		p.PayPeriod,
+		p.GrossPay,
+		p.TotalAdjustments,
+		p.NetPay,
+		p.CreatedAt,
+	)
+	if err != nil {
+		return errors.WrapError("failed to create payslip", err)
+	}
+	return nil
+}
+
+// GetPayslips retrieves the payslips of a payroll run with the adjustments each one paid,
+// optionally only the payslip of one employee (employeeID 0 for all)
+func (r *PayrollRepository) GetPayslips(runID int, employeeID int) ([]payroll.Payslip, error) {
+	query := `
+		SELECT ` + payslipColumns + `
+		FROM payslips
+		WHERE payroll_run_id = $1 AND ($2 = 0 OR employee_id = $3)
+		ORDER BY employee_name, employee_id
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), runID, employeeID, employeeID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query payslips", err)
+	}
+	defer rows.Close()
+
+	payslips := []payroll.Payslip{}
+	for rows.Next() {
+		p, err := scanPayslip(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan payslip", err)
+		}
+		p.Adjustments = []payroll.PayrollAdjustment{}
+		payslips = append(payslips, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating payslips", err)
+	}
+
+	adjustmentRows, err := r.db.Query(convertPlaceholders(`
+		SELECT `+adjustmentColumns+`
+		FROM `+adjustmentFrom+`
+		WHERE pa.payroll_run_id = $1 AND ($2 = 0 OR pa.employee_id = $3)
+		ORDER BY pa.pay_period, pa.id
+	`), runID, employeeID, employeeID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query payslip adjustments", err)
+	}
+	adjustments, err := scanAdjustments(adjustmentRows)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[int]int, len(payslips))
+	for i, p := range payslips {
+		index[p.EmployeeID] = i
+	}
+	for _, a := range adjustments {
+		if i, ok := index[a.EmployeeID]; ok {
+			payslips[i].Adjustments = append(payslips[i].Adjustments, a)
+		}
+	}
+
+	return payslips, nil
+}
+
+// GetEmployeePayslips retrieves the payslips of an employee without their adjustments, latest
+// period first
+func (r *PayrollRepository) GetEmployeePayslips(employeeID int) ([]payroll.Payslip, error) {
+	query := `
+		SELECT ` + payslipColumns + `
+		FROM payslips
+		WHERE employee_id = $1
+		ORDER BY pay_period DESC
+	`
+	rows, err := r.db.Query(convertPlaceholders(query), employeeID)
+	if err != nil {
+		return nil, errors.WrapError("failed to query employee payslips", err)
+	}
+	defer rows.Close()
+
+	payslips := []payroll.Payslip{}
+	for rows.Next() {
+		p, err := scanPayslip(rows)
+		if err != nil {
+			return nil, errors.WrapError("failed to scan payslip", err)
+		}
+		payslips = append(payslips, *p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WrapError("error iterating payslips", err)
+	}
+	return payslips, nil
+}
+
+// scanPayrollRun scans a payroll run row selected with payrollRunColumns
+func scanPayrollRun(row rowScanner) (*payroll.PayrollRun, error) {
+	var run payroll.PayrollRun
+	err := row.Scan(&run.ID, &run.PayPeriod, &run.RunBy, &run.EmployeeCount, &run.TotalGross,
+		&run.TotalAdjustments, &run.TotalNet, &run.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// scanPayslip scans a payslip row selected with payslipColumns
+func scanPayslip(row rowScanner) (*payroll.Payslip, error) {
+	var p payroll.Payslip
+	err := row.Scan(&p.ID, &p.PayrollRunID, &p.EmployeeID, &p.EmployeeName, &p.Position, &p.PayPeriod,
+		&p.GrossPay, &p.TotalAdjustments, &p.NetPay, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -2,6 +2,7 @@ package email
 
 import (
 	"fmt"
+	"net/mail"
 	"sync"
 	"time"
 
@@ -78,6 +79,11 @@ func (eq *EmailQueue) IsRunning() bool {
 	return eq.running
 }
 
+// Sender returns the address emails are sent from
+func (eq *EmailQueue) Sender() mail.Address {
+	return eq.emailService.Sender()
+}
+
 // Enqueue adds a notification to the queue
 func (eq *EmailQueue) Enqueue(n *notification.Notification) error {
 	eq.mu.Lock()
@@ -127,7 +133,11 @@ func (eq *EmailQueue) processNotification(n *notification.Notification) {
 
 	// Send email
 	errors.LogInfo(fmt.Sprintf("📤 ATTEMPTING TO SEND EMAIL to %s", n.RecipientEmail))
-	err := eq.emailService.SendEmail(n.RecipientEmail, n.Subject, n.Body)
+	var attachment *Attachment
+	if n.AttachmentName != "" {
+		attachment = &Attachment{Name: n.AttachmentName, ContentType: n.AttachmentType, Content: []byte(n.AttachmentContent)}
+	}
+	err := eq.emailService.SendEmail(n.RecipientEmail, n.Subject, n.Body, attachment)
 
 	if err == nil {
 		// Email sent successfully
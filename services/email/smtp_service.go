@@ -2,7 +2,10 @@ package email
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/mail"
 	"net/smtp"
@@ -29,6 +32,13 @@ type EmailService struct {
 	config SMTPConfig
 }
 
+// Attachment is a file sent along with an email
+type Attachment struct {
+	Name        string
+	ContentType string // e.g. "text/calendar; method=REQUEST"
+	Content     []byte
+}
+
 // NewEmailService creates a new email service
 func NewEmailService(host string, port int, username, password, fromAddr, fromName string) *EmailService {
 	return &EmailService{
@@ -43,8 +53,13 @@ func NewEmailService(host string, port int, username, password, fromAddr, fromNa
 	}
 }
 
-// SendEmail sends an email with retry support
-func (s *EmailService) SendEmail(to, subject, body string) error {
+// Sender returns the address emails are sent from
+func (s *EmailService) Sender() mail.Address {
+	return mail.Address{Name: s.config.FromName, Address: s.config.FromAddr}
+}
+
+// SendEmail sends an email with retry support, with an attachment when one is given
+func (s *EmailService) SendEmail(to, subject, body string, attachment *Attachment) error {
 	// Validate email address
 	if !isValidEmail(to) {
 		return fmt.Errorf("invalid recipient email address: %s", to)
@@ -67,6 +82,16 @@ func (s *EmailService) SendEmail(to, subject, body string) error {
 	headers["Content-Type"] = "text/plain; charset=\"utf-8\""
 	headers["Content-Transfer-Encoding"] = "base64"
 
+	if attachment != nil {
+		boundary, err := mimeBoundary()
+		if err != nil {
+			return err
+		}
+		headers["Content-Type"] = fmt.Sprintf("multipart/mixed; boundary=\"%s\"", boundary)
+		delete(headers, "Content-Transfer-Encoding")
+		body = multipartBody(boundary, body, attachment)
+	}
+
 	message := ""
 	for k, v := range headers {
 		message += fmt.Sprintf("%s: %s\r\n", k, v)
@@ -163,6 +188,39 @@ func (s *EmailService) SendEmail(to, subject, body string) error {
 	return nil
 }
 
+// multipartBody builds a multipart/mixed body holding the plain text email followed by the
+// attachment, base64 encoded in lines of 76 characters
+func multipartBody(boundary, body string, attachment *Attachment) string {
+	var b strings.Builder
+	b.WriteString("--" + boundary + "\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(body + "\r\n")
+
+	b.WriteString("--" + boundary + "\r\n")
+	b.WriteString(fmt.Sprintf("Content-Type: %s; name=\"%s\"\r\n", attachment.ContentType, attachment.Name))
+	b.WriteString(fmt.Sprintf("Content-Disposition: attachment; filename=\"%s\"\r\n", attachment.Name))
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+
+	b.WriteString("--" + boundary + "--\r\n")
+	return b.String()
+}
+
+// mimeBoundary returns a random boundary separating the parts of a multipart email
+func mimeBoundary() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.WrapError("failed to generate MIME boundary", err)
+	}
+	return "boundary-" + hex.EncodeToString(buf), nil
+}
+
 // RenderTemplate renders an email template with data
 func RenderTemplate(tmpl notification.EmailTemplate, data notification.TemplateData) (string, string, error) {
 	// Create a map from struct for easier template access
@@ -176,12 +234,20 @@ func RenderTemplate(tmpl notification.EmailTemplate, data notification.TemplateD
 		"start_date":           data.StartDate,
 		"end_date":             data.EndDate,
 		"total_days":           data.TotalDays,
+		"day_part":             data.DayPart,
 		"reason":               data.Reason,
 		"rejection_reason":     data.RejectionReason,
 		"total_deduction":      data.TotalDeduction,
 		"is_paid_leave":        data.IsPaidLeave,
 		"current_balance":      data.CurrentBalance,
 		"low_balance_threshold": data.LowBalanceThreshold,
+		"pending_since":        data.PendingSince,
+		"recipient_name":       data.RecipientName,
+		"comment_author":       data.CommentAuthor,
+		"comment":              data.Comment,
+		"info_requested":       data.InfoRequested,
+		"decision_summary":     data.DecisionSummary,
+		"decision_note":        data.DecisionNote,
 	}
 
 	// Render subject
@@ -0,0 +1,16 @@
+package employee
+
+import (
+	"time"
+
+	"employee-service/utils/scheduler"
+)
+
+// ScheduleCompensation registers the job that applies scheduled salary and position changes
+// once they have taken effect, once every interval
+func (s *Service) ScheduleCompensation(jobs *scheduler.Scheduler, interval time.Duration) error {
+	return jobs.Register("compensation changes", interval, func() error {
+		_, err := s.ApplyDueCompensation(today())
+		return err
+	})
+}
@@ -1,12 +1,15 @@
 package employee
 
 import (
+	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	"employee-service/errors"
 	"employee-service/models/employee"
 	usermodel "employee-service/models/user"
+	"employee-service/repositories"
 	"employee-service/repositories/postgres"
 	userService "employee-service/services/user"
 )
@@ -16,6 +19,7 @@ type Service struct {
 	repo        *postgres.EmployeeRepository
 	userRepo    *postgres.UserRepository
 	userService *userService.UserService
+	txManager   *repositories.TransactionManager
 }
 
 // NewService creates a new employee service
@@ -24,11 +28,12 @@ func NewService(repo *postgres.EmployeeRepository) *Service {
 }
 
 // NewServiceWithUser creates a new employee service with user repository and service
-func NewServiceWithUser(repo *postgres.EmployeeRepository, userRepo *postgres.UserRepository, userSvc *userService.UserService) *Service {
+func NewServiceWithUser(repo *postgres.EmployeeRepository, userRepo *postgres.UserRepository, userSvc *userService.UserService, txManager *repositories.TransactionManager) *Service {
 	return &Service{
 		repo:        repo,
 		userRepo:    userRepo,
 		userService: userSvc,
+		txManager:   txManager,
 	}
 }
 
@@ -39,6 +44,12 @@ func (s *Service) CreateEmployee(req *employee.CreateEmployeeRequest) (*employee
 		return nil, err
 	}
 
+	if req.ManagerID != nil {
+		if err := s.validateManager(0, *req.ManagerID); err != nil {
+			return nil, err
+		}
+	}
+
 	var userID *int
 
 	// Create user account if username and password are provided
@@ -73,10 +84,32 @@ func (s *Service) CreateEmployee(req *employee.CreateEmployeeRequest) (*employee
 		Salary:        req.Salary,
 		Gender:        req.Gender,
 		MaritalStatus: req.MaritalStatus,
+		Location:      req.Location,
+		Department:    req.Department,
+		Team:          req.Team,
 		Hired:         hiredDate,
+		ManagerID:     req.ManagerID,
+	}
+
+	emp, err := s.repo.CreateEmployee(emp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start the compensation history with the salary and position the employee is hired on
+	if s.txManager != nil {
+		hire := employee.HireRecord(emp)
+		now := time.Now()
+		hire.AppliedAt = &now
+		err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+			return s.repo.CreateCompensationRecord(tx, &hire)
+		})
+		if err != nil {
+			errors.LogError("Failed to record the hiring compensation of employee", err)
+		}
 	}
 
-	return s.repo.CreateEmployee(emp)
+	return emp, nil
 }
 
 // GetEmployee retrieves an employee by ID
@@ -114,8 +147,10 @@ func (s *Service) ListEmployees(limit, offset int) ([]*employee.Employee, int, e
 	return employees, total, nil
 }
 
-// UpdateEmployee updates an employee record
-func (s *Service) UpdateEmployee(id int, req *employee.UpdateEmployeeRequest) (*employee.Employee, error) {
+// UpdateEmployee updates an employee record. Salary and position changes are recorded in the
+// compensation history as of their effective date, today by default; changes dated in the future
+// reach the employee record when they take effect.
+func (s *Service) UpdateEmployee(id int, req *employee.UpdateEmployeeRequest, userID int) (*employee.Employee, error) {
 	if id <= 0 {
 		return nil, errors.BadRequestError("Invalid employee ID")
 	}
@@ -125,7 +160,162 @@ func (s *Service) UpdateEmployee(id int, req *employee.UpdateEmployeeRequest) (*
 		return nil, err
 	}
 
-	return s.repo.UpdateEmployee(id, req)
+	if req.ManagerID != nil && *req.ManagerID != 0 {
+		if err := s.validateManager(id, *req.ManagerID); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.repo.GetEmployeeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.GetCompensationHistory(id)
+	if err != nil {
+		return nil, err
+	}
+	change := req.CompensationChange(current, history, today())
+
+	// Salary and position are never overwritten in place
+	update := *req
+	update.Salary, update.Position = nil, nil
+	emp, err := s.repo.UpdateEmployee(id, &update)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		return emp, nil
+	}
+
+	change.CreatedBy = &userID
+	if err := s.recordCompensation(emp, change); err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Compensation change for employee %d effective %s recorded by user %d",
+		id, change.EffectiveDate.Format(employee.DateLayout), userID))
+
+	return s.repo.GetEmployeeByID(id)
+}
+
+// GetCompensationHistory retrieves the timeline of an employee's salary and position changes,
+// including scheduled ones. Admins can view any employee's; employees only their own.
+func (s *Service) GetCompensationHistory(employeeID, userID int, isAdmin bool) (*employee.CompensationTimeline, error) {
+	emp, err := s.GetEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && (emp.UserID == nil || *emp.UserID != userID) {
+		return nil, errors.NewForbiddenError("you can only view your own compensation history")
+	}
+
+	history, err := s.repo.GetCompensationHistory(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	date := today()
+	return &employee.CompensationTimeline{
+		EmployeeID: emp.ID,
+		Current:    history.On(emp, date),
+		Entries:    history.Timeline(emp, date),
+	}, nil
+}
+
+// ApplyDueCompensation copies the scheduled salary and position changes that have taken effect by
+// date onto the employee records, and returns how many employees were updated
+func (s *Service) ApplyDueCompensation(date time.Time) (int, error) {
+	ids, err := s.repo.GetDueCompensationEmployees(date)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, id := range ids {
+		emp, err := s.repo.GetEmployeeByID(id)
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to load employee %d for scheduled compensation", id), err)
+			continue
+		}
+
+		err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+			history, err := s.repo.LockCompensationHistory(tx, id)
+			if err != nil {
+				return err
+			}
+			return s.repo.ApplyCompensation(tx, id, history.On(emp, date), date)
+		})
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to apply scheduled compensation of employee %d", id), err)
+			continue
+		}
+		applied++
+	}
+
+	if applied > 0 {
+		errors.LogInfo(fmt.Sprintf("Applied scheduled compensation changes to %d employees", applied))
+	}
+	return applied, nil
+}
+
+// recordCompensation records a salary or position change and brings the employee record up to
+// date with the compensation in effect today
+func (s *Service) recordCompensation(emp *employee.Employee, change *employee.CompensationRecord) error {
+	date := today()
+
+	return s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		history, err := s.repo.LockCompensationHistory(tx, emp.ID)
+		if err != nil {
+			return err
+		}
+
+		// Employees created before the history was kept start it with their current compensation
+		if len(history) == 0 {
+			hire := employee.HireRecord(emp)
+			now := time.Now()
+			hire.AppliedAt = &now
+			if err := s.repo.CreateCompensationRecord(tx, &hire); err != nil {
+				return err
+			}
+			history = history.Add(hire)
+		}
+
+		if err := s.repo.CreateCompensationRecord(tx, change); err != nil {
+			return err
+		}
+		history = history.Add(*change)
+
+		return s.repo.ApplyCompensation(tx, emp.ID, history.On(emp, date), date)
+	})
+}
+
+// validateManager checks that a manager exists and that assigning them to the employee
+// does not create a loop in the reporting line. employeeID is 0 for a new employee.
+func (s *Service) validateManager(employeeID, managerID int) error {
+	if _, err := s.repo.GetEmployeeByID(managerID); err != nil {
+		return errors.NewValidationError().AddField("manager_id", "manager not found")
+	}
+
+	if employeeID == 0 {
+		return nil
+	}
+	if managerID == employeeID {
+		return errors.NewValidationError().AddField("manager_id", "an employee cannot be their own manager")
+	}
+
+	isReport, err := s.repo.IsReportOf(managerID, employeeID)
+	if err != nil {
+		return err
+	}
+	if isReport {
+		return errors.NewValidationError().AddField("manager_id", "the manager already reports to this employee")
+	}
+	return nil
+}
+
+// today returns the current date in UTC, the form effective dates are stored in
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
 }
 
 // DeleteEmployee deletes an employee record
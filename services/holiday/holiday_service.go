@@ -0,0 +1,91 @@
+package holiday
+
+import (
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/holiday"
+	"employee-service/repositories/postgres"
+)
+
+// Service handles business logic for the holiday calendar
+type Service struct {
+	repository *postgres.HolidayRepository
+}
+
+// NewService creates a new holiday service
+func NewService(repository *postgres.HolidayRepository) *Service {
+	return &Service{repository: repository}
+}
+
+// CreateHoliday adds a holiday to the calendar
+func (s *Service) CreateHoliday(req *holiday.CreateHolidayRequest) (*holiday.Holiday, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date)
+
+	h := &holiday.Holiday{
+		Name:     strings.TrimSpace(req.Name),
+		Date:     date,
+		Location: strings.TrimSpace(req.Location),
+	}
+
+	result, err := s.repository.CreateHoliday(h)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("holiday for this date and location")
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetHoliday retrieves a single holiday
+func (s *Service) GetHoliday(id int) (*holiday.Holiday, error) {
+	return s.repository.GetHoliday(id)
+}
+
+// ListHolidays retrieves holidays, optionally filtered by year and location
+func (s *Service) ListHolidays(year int, location string) ([]holiday.Holiday, error) {
+	return s.repository.ListHolidays(year, strings.TrimSpace(location))
+}
+
+// UpdateHoliday updates a holiday in the calendar
+func (s *Service) UpdateHoliday(id int, req *holiday.UpdateHolidayRequest) (*holiday.Holiday, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	h, err := s.repository.GetHoliday(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		h.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Date != nil {
+		h.Date, _ = time.Parse("2006-01-02", *req.Date)
+	}
+	if req.Location != nil {
+		h.Location = strings.TrimSpace(*req.Location)
+	}
+
+	if err := s.repository.UpdateHoliday(h); err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("holiday for this date and location")
+		}
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// DeleteHoliday removes a holiday from the calendar
+func (s *Service) DeleteHoliday(id int) error {
+	return s.repository.DeleteHoliday(id)
+}
@@ -0,0 +1,16 @@
+package leave
+
+import (
+	"time"
+
+	"employee-service/utils/scheduler"
+)
+
+// ScheduleAccrual registers the leave accrual job, which credits the accruals due by the current
+// date once every interval
+func (s *Service) ScheduleAccrual(jobs *scheduler.Scheduler, interval time.Duration) error {
+	return jobs.Register("leave accrual", interval, func() error {
+		_, err := s.RunAccrual(time.Now())
+		return err
+	})
+}
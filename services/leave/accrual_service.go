@@ -0,0 +1,105 @@
+package leave
+
+import (
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+)
+
+// accrualPageSize is the number of employees loaded per page during an accrual run
+const accrualPageSize = 100
+
+// RunAccrual credits every employee with the accruals due up to asOf under the active
+// leave policies. Periods that were already accrued are skipped, so the run is safe to repeat.
+func (s *Service) RunAccrual(asOf time.Time) (*leave.AccrualRunResult, error) {
+	policies, err := s.policyRepository.ListPolicies(true)
+	if err != nil {
+		return nil, errors.WrapError("failed to load leave policies", err)
+	}
+
+	result := &leave.AccrualRunResult{AsOf: asOf.Format("2006-01-02")}
+
+	for offset := 0; ; offset += accrualPageSize {
+		employees, err := s.employeeRepository.GetAllEmployees(accrualPageSize, offset)
+		if err != nil {
+			return nil, errors.WrapError("failed to load employees", err)
+		}
+
+		for _, emp := range employees {
+			s.accrueEmployee(emp, policies, asOf, result)
+			s.notifyLowBalances(emp, policies, asOf, result)
+			result.EmployeesProcessed++
+		}
+
+		if len(employees) < accrualPageSize {
+			break
+		}
+	}
+
+	errors.LogInfo(fmt.Sprintf("Leave accrual as of %s: %d employees, %d entries created, %d skipped, %.2f days, %d low balance notices, %d errors",
+		result.AsOf, result.EmployeesProcessed, result.EntriesCreated, result.EntriesSkipped, result.DaysAccrued, result.LowBalanceNotices, result.Errors))
+
+	return result, nil
+}
+
+// accrueEmployee credits the accruals due to one employee and adds the outcome to result
+func (s *Service) accrueEmployee(emp *employee.Employee, policies []leave.LeavePolicy, asOf time.Time, result *leave.AccrualRunResult) {
+	accrued, err := s.accrualRepository.GetEmployeeAccruals(emp.ID)
+	if err != nil {
+		errors.LogError(fmt.Sprintf("Failed to load accruals of employee %d", emp.ID), err)
+		result.Errors++
+		return
+	}
+
+	for i := range policies {
+		policy := &policies[i]
+
+		pending := policy.PendingAccruals(emp.Hired, asOf, accrued)
+		result.EntriesSkipped += len(policy.DueAccruals(emp.Hired, asOf)) - len(pending)
+
+		for _, due := range pending {
+			entry := &leave.LeaveAccrual{
+				EmployeeID: emp.ID,
+				LeaveType:  policy.LeaveType,
+				Period:     due.Period,
+				Days:       due.Days,
+			}
+
+			// A concurrent run may still have credited the period in the meantime
+			created, err := s.accrualRepository.CreditAccrual(entry)
+			if err != nil {
+				errors.LogError(fmt.Sprintf("Failed to accrue %s leave for employee %d (%s)", policy.LeaveType, emp.ID, due.Period), err)
+				result.Errors++
+				continue
+			}
+
+			if !created {
+				result.EntriesSkipped++
+				continue
+			}
+			result.EntriesCreated++
+			result.DaysAccrued += entry.Days
+		}
+	}
+}
+
+// notifyLowBalances warns an employee about every balance still at or below its threshold after accrual
+func (s *Service) notifyLowBalances(emp *employee.Employee, policies []leave.LeavePolicy, asOf time.Time, result *leave.AccrualRunResult) {
+	for i := range policies {
+		notified, err := s.notifyLowBalance(emp, &policies[i], asOf)
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to check %s balance of employee %d", policies[i].LeaveType, emp.ID), err)
+			result.Errors++
+		} else if notified {
+			result.LowBalanceNotices++
+		}
+	}
+}
+
+// GetEmployeeAccruals retrieves the accrual ledger for an employee
+func (s *Service) GetEmployeeAccruals(employeeID int) ([]leave.LeaveAccrual, error) {
+	return s.accrualRepository.GetEmployeeAccruals(employeeID)
+}
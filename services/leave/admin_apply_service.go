@@ -0,0 +1,81 @@
+package leave
+
+import (
+	"context"
+	"fmt"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// ApplyLeaveForEmployee records leave on behalf of any employee (admin only), e.g. one who is in
+// hospital or has no login. The leave is checked like the employee's own application unless the
+// policy is overridden. Auto-approved leave skips the approval chain and is approved by the admin
+// in a single HR step; otherwise it waits for the chain like any other request. The audit log
+// records who entered it and whether the policy was waived.
+func (s *Service) ApplyLeaveForEmployee(adminUserID int, role string, req *leave.AdminApplyLeaveRequest, uploads []leave.AttachmentUpload, ipAddress string) (*leave.LeaveRequest, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.attachmentLimits.Check(uploads, 0); err != nil {
+		return nil, err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByID(req.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveRequest, err := s.buildLeaveRequest(emp, &req.ApplyLeaveRequest, len(uploads), req.OverridePolicy)
+	if err != nil {
+		return nil, err
+	}
+	if req.AutoApprove {
+		leaveRequest.Approvals = []leave.LeaveApproval{{Step: 1, ApproverRole: leave.ApproverHR, Decision: leave.DecisionPending}}
+	}
+
+	result, err := s.createLeaveRequest(leaveRequest, adminUserID, uploads)
+	if err != nil {
+		return nil, err
+	}
+	errors.LogInfo(fmt.Sprintf("Leave request %d entered by user %d on behalf of employee %d (policy override: %t)",
+		result.ID, adminUserID, emp.ID, req.OverridePolicy))
+
+	if req.AutoApprove {
+		note := "Entered and approved by HR on behalf of the employee"
+		if req.OverridePolicy {
+			note += ". Policy overridden: " + req.OverrideReason
+		}
+
+		approved, err := s.ApproveLeave(result.ID, adminUserID, role, note)
+		if err != nil {
+			// The request was entered; it waits in the review queue like any other
+			errors.LogError(fmt.Sprintf("Failed to auto-approve leave request %d", result.ID), err)
+			s.auditLeaveEntry(req, result, adminUserID, ipAddress)
+
+			message := fmt.Sprintf("leave request %d was entered but could not be approved; it waits in the review queue", result.ID)
+			if validationErr, ok := err.(*errors.ValidationError); ok {
+				return nil, validationErr.AddField("auto_approve", message)
+			}
+			return nil, errors.WrapError(message, err)
+		}
+		result = approved
+	} else {
+		go s.queueLeaveAppliedNotifications(result, emp)
+	}
+
+	s.auditLeaveEntry(req, result, adminUserID, ipAddress)
+	return result, nil
+}
+
+// auditLeaveEntry records leave entered on behalf of an employee in the audit log
+func (s *Service) auditLeaveEntry(req *leave.AdminApplyLeaveRequest, leaveRequest *leave.LeaveRequest, adminUserID int, ipAddress string) {
+	if s.auditLogger == nil {
+		return
+	}
+	err := s.auditLogger.LogInsert(context.Background(), "leave_requests", int64(leaveRequest.ID),
+		req.AuditValues(leaveRequest, adminUserID), int64(adminUserID), ipAddress)
+	if err != nil {
+		errors.LogError(fmt.Sprintf("Failed to audit leave request %d entered by user %d", leaveRequest.ID, adminUserID), err)
+	}
+}
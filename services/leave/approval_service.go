@@ -0,0 +1,312 @@
+package leave
+
+import (
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/user"
+)
+
+// ListApprovalRules retrieves all approval rules in step order
+func (s *Service) ListApprovalRules() ([]leave.ApprovalRule, error) {
+	return s.approvalRepository.ListRules(false)
+}
+
+// CreateApprovalRule adds a step to the approval chains of future leave requests (admin only)
+func (s *Service) CreateApprovalRule(req *leave.ApprovalRuleRequest) (*leave.ApprovalRule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rule := &leave.ApprovalRule{}
+	applyApprovalRuleRequest(rule, req)
+
+	result, err := s.approvalRepository.CreateRule(rule)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("approval rule for this step_order")
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateApprovalRule replaces an approval rule (admin only).
+// Chains already built for pending requests are not changed.
+func (s *Service) UpdateApprovalRule(id int, req *leave.ApprovalRuleRequest) (*leave.ApprovalRule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.approvalRepository.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	applyApprovalRuleRequest(rule, req)
+
+	if err := s.approvalRepository.UpdateRule(rule); err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("approval rule for this step_order")
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteApprovalRule deletes an approval rule (admin only)
+func (s *Service) DeleteApprovalRule(id int) error {
+	return s.approvalRepository.DeleteRule(id)
+}
+
+// GetLeaveApprovals retrieves the approval chain of a leave request.
+// Employees can only see the chains of their own requests; approvers also see those they can review.
+func (s *Service) GetLeaveApprovals(id int, userID int, role string) ([]leave.LeaveApproval, error) {
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeView(leaveRequest, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.approvalRepository.GetApprovals(id)
+}
+
+// authorizeView checks that a user may see the details of a leave request: admins, the employee
+// who applied, and approvers who can review it with their own or delegated rights
+func (s *Service) authorizeView(leaveRequest *leave.LeaveRequest, userID int, role string) error {
+	if role == user.RoleAdmin {
+		return nil
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return errors.NotFoundError("employee record")
+	}
+	if leaveRequest.EmployeeID == emp.ID {
+		return nil
+	}
+
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return err
+	}
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return err
+	}
+	if !all && !team[leaveRequest.EmployeeID] {
+		return errors.NewForbiddenError("you don't have permission to view this leave request")
+	}
+	return nil
+}
+
+// approver is a set of approval rights a user can exercise: their own, or those of a
+// delegator they are standing in for
+type approver struct {
+	userID     int
+	role       string
+	onBehalfOf *int // Delegator user ID when the rights are delegated
+}
+
+// actingApprovers returns the approval rights a user can exercise today, their own first
+func (s *Service) actingApprovers(userID int, role string) ([]approver, error) {
+	// Only approvers can hold delegated rights, even if their role changed after the delegation
+	if !leave.CanReceiveDelegation(role) {
+		return []approver{}, nil
+	}
+	approvers := []approver{{userID: userID, role: role}}
+
+	delegations, err := s.approvalRepository.GetActiveDelegations(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int]bool{userID: true}
+	for _, d := range delegations {
+		if seen[d.DelegatorUserID] {
+			continue
+		}
+		seen[d.DelegatorUserID] = true
+
+		delegator, err := s.userRepository.GetUserByID(d.DelegatorUserID)
+		if err != nil {
+			return nil, errors.WrapError("failed to get delegator", err)
+		}
+		if delegator.Role != user.RoleAdmin && delegator.Role != user.RoleManager {
+			continue
+		}
+		delegatorID := d.DelegatorUserID
+		approvers = append(approvers, approver{userID: delegatorID, role: delegator.Role, onBehalfOf: &delegatorID})
+	}
+
+	return approvers, nil
+}
+
+// reviewableEmployees returns whose leave requests a set of approvers may review: everyone
+// when one of them is an admin, otherwise the direct and indirect reports of the managers
+func (s *Service) reviewableEmployees(approvers []approver) (bool, map[int]bool, error) {
+	team := map[int]bool{}
+	for _, a := range approvers {
+		if a.role == user.RoleAdmin {
+			return true, nil, nil
+		}
+
+		manager, err := s.employeeRepository.GetEmployeeByUserID(a.userID)
+		if err != nil {
+			continue // No employee record, so no reports
+		}
+		reportIDs, err := s.employeeRepository.GetReportIDs(manager.ID)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, id := range reportIDs {
+			team[id] = true
+		}
+	}
+	return false, team, nil
+}
+
+// authorizeDecision checks that a user may decide the current approval step of a leave request,
+// either with their own rights or as a delegate. It returns the delegator the decision is made
+// on behalf of, if any. A user who decided an earlier step of the request, themselves or through
+// a delegate, cannot decide a later one.
+func (s *Service) authorizeDecision(leaveRequest *leave.LeaveRequest, approvals []leave.LeaveApproval, step *leave.LeaveApproval, userID int, role string) (*int, error) {
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, errors.NewForbiddenError("admin or manager access required")
+	}
+
+	if emp, err := s.employeeRepository.GetEmployeeByUserID(userID); err == nil {
+		if err := leave.CheckNotOwnRequest(leaveRequest.EmployeeID, emp.ID, "leave request"); err != nil {
+			return nil, err
+		}
+	}
+
+	if step != nil && leave.DecidedEarlierStep(approvals, step, userID) {
+		return nil, errors.NewForbiddenError("you already decided an earlier approval step of this leave request")
+	}
+
+	var denied error
+	for _, a := range approvers {
+		err := s.canDecide(leaveRequest, approvals, step, a)
+		if err == nil {
+			return a.onBehalfOf, nil
+		}
+		if _, ok := err.(*errors.ForbiddenError); !ok {
+			return nil, err
+		}
+		if denied == nil {
+			denied = err
+		}
+	}
+	return nil, denied
+}
+
+// canDecide checks one set of approval rights against the current step of a leave request.
+// HR steps, and requests without an approval chain, are decided by admins. MANAGER steps are
+// decided by a manager of the employee, or by an admin standing in for them. A delegator who
+// decided an earlier step cannot decide a later one through their delegate.
+func (s *Service) canDecide(leaveRequest *leave.LeaveRequest, approvals []leave.LeaveApproval, step *leave.LeaveApproval, a approver) error {
+	if step != nil && a.onBehalfOf != nil && leave.DecidedEarlierStep(approvals, step, *a.onBehalfOf) {
+		return errors.NewForbiddenError("the delegator already decided an earlier approval step of this leave request")
+	}
+
+	if step == nil || step.ApproverRole == leave.ApproverHR {
+		if a.role != user.RoleAdmin {
+			return errors.NewForbiddenError("the current approval step must be decided by HR")
+		}
+		return nil
+	}
+
+	if step.ApproverRole != leave.ApproverManager {
+		return errors.NewForbiddenError("unsupported approval step")
+	}
+	if a.role == user.RoleAdmin {
+		return nil
+	}
+
+	// A manager without an employee record has no reports; other rights may still apply
+	manager, err := s.employeeRepository.GetEmployeeByUserID(a.userID)
+	if err != nil {
+		return errors.NewForbiddenError("you can only decide leave requests of your reports")
+	}
+	if err := leave.CheckNotOwnRequest(leaveRequest.EmployeeID, manager.ID, "leave request"); err != nil {
+		return err
+	}
+
+	isReport, err := s.employeeRepository.IsReportOf(leaveRequest.EmployeeID, manager.ID)
+	if err != nil {
+		return err
+	}
+	if !isReport {
+		return errors.NewForbiddenError("you can only decide leave requests of your reports")
+	}
+
+	return nil
+}
+
+// filterReviewableRequests keeps the leave requests a user may review with their own or delegated rights
+func (s *Service) filterReviewableRequests(userID int, role string, leaveRequests []leave.LeaveRequestDetail) ([]leave.LeaveRequestDetail, error) {
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, errors.NewForbiddenError("admin or manager access required")
+	}
+
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return leaveRequests, nil
+	}
+
+	reviewable := []leave.LeaveRequestDetail{}
+	for _, lr := range leaveRequests {
+		if team[lr.EmployeeID] {
+			reviewable = append(reviewable, lr)
+		}
+	}
+	return reviewable, nil
+}
+
+// buildApprovalChain attaches the approval chain from the active rules to a new leave request
+func (s *Service) buildApprovalChain(leaveRequest *leave.LeaveRequest) error {
+	rules, err := s.approvalRepository.ListRules(true)
+	if err != nil {
+		return err
+	}
+
+	leaveRequest.Approvals = leave.BuildApprovalChain(rules, leaveRequest)
+	return nil
+}
+
+// applyApprovalRuleRequest copies a validated rule request onto a rule
+func applyApprovalRuleRequest(rule *leave.ApprovalRule, req *leave.ApprovalRuleRequest) {
+	leaveTypes := make([]leave.LeaveType, len(req.LeaveTypes))
+	for i, leaveType := range req.LeaveTypes {
+		leaveTypes[i] = leave.LeaveType(strings.ToUpper(strings.TrimSpace(string(leaveType))))
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	rule.StepOrder = req.StepOrder
+	rule.ApproverRole = leave.ApproverRole(strings.ToUpper(string(req.ApproverRole)))
+	rule.OverDays = req.OverDays
+	rule.LeaveTypes = leaveTypes
+	rule.IsActive = isActive
+}
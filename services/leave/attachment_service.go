@@ -0,0 +1,168 @@
+package leave
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+)
+
+// AttachmentLimits returns the limits uploads are checked against
+func (s *Service) AttachmentLimits() leave.AttachmentLimits {
+	return s.attachmentLimits
+}
+
+// AddAttachments attaches supporting documents to a pending or approved leave request of the caller
+func (s *Service) AddAttachments(id int, userID int, uploads []leave.AttachmentUpload) ([]leave.LeaveAttachment, error) {
+	if len(uploads) == 0 {
+		return nil, errors.NewValidationError().AddField("attachments", "at least one file is required")
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+
+	var added []leave.LeaveAttachment
+	var stored []string
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		leaveRequest, err := s.repository.LockLeaveRequest(tx, id)
+		if err != nil {
+			return err
+		}
+		if leaveRequest.EmployeeID != emp.ID {
+			return errors.NewForbiddenError("you don't have permission to add attachments to this leave request")
+		}
+		if leaveRequest.Status != leave.StatusPending && leaveRequest.Status != leave.StatusApproved {
+			return errors.NewValidationError().AddField("status", "attachments can only be added to pending or approved leave requests")
+		}
+
+		existing, err := s.repository.CountAttachments(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.attachmentLimits.Check(uploads, existing); err != nil {
+			return err
+		}
+
+		added, stored, err = s.storeAttachments(tx, id, userID, uploads)
+		return err
+	})
+	if err != nil {
+		s.deleteStoredFiles(stored)
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("%d attachment(s) added to leave request %d by user %d", len(added), id, userID))
+	return added, nil
+}
+
+// GetAttachments lists the attachments of a leave request. Employees can only see the attachments
+// of their own requests; approvers also see those they can review.
+func (s *Service) GetAttachments(id int, userID int, role string) ([]leave.LeaveAttachment, error) {
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeView(leaveRequest, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetAttachments(id)
+}
+
+// OpenAttachment retrieves an attachment of a leave request with its content, for the employee who
+// applied and approvers who can review the request
+func (s *Service) OpenAttachment(id int, attachmentID int, userID int, role string) (*leave.LeaveAttachment, []byte, error) {
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.authorizeView(leaveRequest, userID, role); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.repository.GetAttachment(id, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.attachmentStorage == nil {
+		return nil, nil, errors.WrapError("failed to open attachment", fmt.Errorf("attachment storage is not configured"))
+	}
+	file, err := s.attachmentStorage.Open(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, errors.WrapError("failed to read attachment", err)
+	}
+	return attachment, content, nil
+}
+
+// storeAttachments saves uploads to attachment storage and records them within tx. The keys of the
+// files saved are returned even on error so the caller can delete them if the transaction fails.
+func (s *Service) storeAttachments(tx *repositories.Transaction, leaveRequestID int, userID int, uploads []leave.AttachmentUpload) ([]leave.LeaveAttachment, []string, error) {
+	if len(uploads) == 0 {
+		return nil, nil, nil
+	}
+	if s.attachmentStorage == nil {
+		return nil, nil, errors.WrapError("failed to store attachments", fmt.Errorf("attachment storage is not configured"))
+	}
+
+	attachments := make([]leave.LeaveAttachment, 0, len(uploads))
+	stored := make([]string, 0, len(uploads))
+	for _, u := range uploads {
+		key, err := attachmentKey(leaveRequestID)
+		if err != nil {
+			return nil, stored, err
+		}
+		if err := s.attachmentStorage.Save(key, bytes.NewReader(u.Content)); err != nil {
+			return nil, stored, err
+		}
+		stored = append(stored, key)
+
+		attachment := leave.LeaveAttachment{
+			LeaveRequestID: leaveRequestID,
+			FileName:       u.FileName,
+			ContentType:    u.ContentType,
+			Size:           int64(len(u.Content)),
+			StorageKey:     key,
+			UploadedBy:     userID,
+		}
+		if err := s.repository.CreateAttachment(tx, &attachment); err != nil {
+			return nil, stored, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, stored, nil
+}
+
+// deleteStoredFiles removes files saved for attachments that were not recorded
+func (s *Service) deleteStoredFiles(keys []string) {
+	for _, key := range keys {
+		if err := s.attachmentStorage.Delete(key); err != nil {
+			errors.LogError(fmt.Sprintf("Failed to delete orphaned attachment %s", key), err)
+		}
+	}
+}
+
+// attachmentKey generates an unguessable storage key for a file of a leave request
+func attachmentKey(leaveRequestID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WrapError("failed to generate attachment key", err)
+	}
+	return fmt.Sprintf("leave-requests/%d/%s", leaveRequestID, hex.EncodeToString(b)), nil
+}
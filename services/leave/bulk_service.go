@@ -0,0 +1,139 @@
+package leave
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/notification"
+	"employee-service/services/email"
+)
+
+// BulkDecide approves or rejects several leave requests with a shared note, with the same checks as
+// deciding them one at a time. Each request is decided in its own transaction, so one that fails,
+// e.g. for an insufficient balance, does not stop the rest. Each employee gets one email covering
+// all of their requests that reached a final decision.
+func (s *Service) BulkDecide(userID int, role string, req *leave.BulkDecisionRequest) (*leave.BulkDecisionResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &leave.BulkDecisionResult{Action: req.Action, Results: make([]leave.BulkDecisionItem, 0, len(req.IDs))}
+	decided := map[int][]*leave.LeaveRequest{}
+	var employees []int // In order of their first decided request
+
+	for _, id := range req.IDs {
+		var leaveRequest *leave.LeaveRequest
+		var err error
+		if req.Action == leave.BulkApprove {
+			leaveRequest, err = s.approveLeave(id, userID, role, req.Note)
+		} else {
+			leaveRequest, err = s.rejectLeave(id, userID, role, req.Note)
+		}
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Bulk %s of leave request %d failed", strings.ToLower(string(req.Action)), id), err)
+			result.Add(id, "", err)
+			continue
+		}
+		result.Add(id, leaveRequest.Status, nil)
+
+		// Approving an intermediate step tells the employee nothing yet
+		if leaveRequest.Status == leave.StatusPending {
+			continue
+		}
+		if _, ok := decided[leaveRequest.EmployeeID]; !ok {
+			employees = append(employees, leaveRequest.EmployeeID)
+		}
+		decided[leaveRequest.EmployeeID] = append(decided[leaveRequest.EmployeeID], leaveRequest)
+	}
+
+	errors.LogInfo(fmt.Sprintf("Bulk %s by user %d: %d succeeded, %d failed",
+		strings.ToLower(string(req.Action)), userID, result.Succeeded, result.Failed))
+
+	go s.queueBulkDecisionNotifications(employees, decided, userID, req.Note)
+
+	return result, nil
+}
+
+// queueBulkDecisionNotifications sends each employee one email about their decided requests: the
+// usual approval or rejection email for a single request, otherwise a summary of all of them
+func (s *Service) queueBulkDecisionNotifications(employees []int, decided map[int][]*leave.LeaveRequest, approvedByUserID int, note string) {
+	for _, employeeID := range employees {
+		requests := decided[employeeID]
+
+		if len(requests) == 1 {
+			if requests[0].Status == leave.StatusApproved {
+				s.queueLeaveApprovedNotification(requests[0], approvedByUserID)
+			} else {
+				s.queueLeaveRejectedNotification(requests[0], note)
+			}
+		} else {
+			s.queueLeaveDecisionsNotification(employeeID, requests, approvedByUserID, note)
+		}
+
+		// Check each leave type the approvals drew on once
+		checked := map[leave.LeaveType]bool{}
+		for _, lr := range requests {
+			if lr.Status != leave.StatusApproved || checked[lr.LeaveType] {
+				continue
+			}
+			checked[lr.LeaveType] = true
+			s.checkLowBalance(employeeID, lr.LeaveType, time.Now())
+		}
+	}
+}
+
+// queueLeaveDecisionsNotification queues one email telling an employee about several decided requests
+func (s *Service) queueLeaveDecisionsNotification(employeeID int, requests []*leave.LeaveRequest, approvedByUserID int, note string) {
+	emp, err := s.employeeRepository.GetEmployeeByID(employeeID)
+	if err != nil {
+		errors.LogError("Failed to get employee for decision summary notification", err)
+		return
+	}
+
+	approver, err := s.userRepository.GetUserByID(approvedByUserID)
+	if err != nil {
+		errors.LogError("Failed to get approver for decision summary notification", err)
+		return
+	}
+
+	templateData := notification.TemplateData{
+		EmployeeName:    fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		EmployeeEmail:   emp.Email,
+		EmployeeID:      emp.ID,
+		AdminName:       approver.Username,
+		AdminEmail:      approver.Email,
+		DecisionSummary: leave.DecisionSummary(requests),
+		DecisionNote:    note,
+	}
+
+	tmpl := notification.GetTemplate(notification.EventLeaveDecisions, false)
+	subject, body, err := email.RenderTemplate(tmpl, templateData)
+	if err != nil {
+		errors.LogError("Failed to render decision summary notification template", err)
+		return
+	}
+
+	notif := &notification.Notification{
+		RecipientEmail:  emp.Email,
+		RecipientName:   fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		EventType:       notification.EventLeaveDecisions,
+		TemplateName:    tmpl.Name,
+		DeliveryChannel: notification.ChannelSMTP,
+		Status:          notification.StatusPending,
+		Subject:         subject,
+		Body:            body,
+		MaxRetries:      3,
+	}
+
+	if _, err := s.notificationRepo.CreateNotification(notif); err != nil {
+		errors.LogError("Failed to create decision summary notification record", err)
+		return
+	}
+
+	if err := s.emailQueue.Enqueue(notif); err != nil {
+		errors.LogError("Failed to enqueue decision summary notification", err)
+	}
+}
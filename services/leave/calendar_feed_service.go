@@ -0,0 +1,173 @@
+package leave
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+	"employee-service/models/notification"
+	"employee-service/models/user"
+	"employee-service/repositories"
+	"employee-service/repositories/postgres"
+	"employee-service/utils/ical"
+)
+
+// CreateCalendarFeed creates a calendar feed link for a user, revoking the one they had, so a
+// leaked link can be replaced
+func (s *Service) CreateCalendarFeed(userID int) (*leave.CalendarFeed, error) {
+	nonce, err := feedNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	var feed *leave.CalendarFeed
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		feed, err = s.repository.CreateCalendarFeed(tx, userID, nonce)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	feed.Token = leave.SignFeedToken(s.feedSecret, feed.ID, feed.Nonce)
+	errors.LogInfo(fmt.Sprintf("Calendar feed %d created for user %d", feed.ID, userID))
+	return feed, nil
+}
+
+// GetCalendarFeed retrieves a user's active calendar feed with its token
+func (s *Service) GetCalendarFeed(userID int) (*leave.CalendarFeed, error) {
+	feed, err := s.repository.GetActiveCalendarFeed(userID)
+	if err != nil {
+		return nil, err
+	}
+	feed.Token = leave.SignFeedToken(s.feedSecret, feed.ID, feed.Nonce)
+	return feed, nil
+}
+
+// RevokeCalendarFeed stops a user's calendar feed link from working
+func (s *Service) RevokeCalendarFeed(userID int) error {
+	revoked, err := s.repository.RevokeCalendarFeeds(userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.NewNotFoundError("calendar feed not found")
+	}
+
+	errors.LogInfo(fmt.Sprintf("Calendar feeds of user %d revoked", userID))
+	return nil
+}
+
+// RenderCalendarFeed renders the calendar a feed token gives access to: the approved leave of its
+// user and, for managers and admins, of their direct and indirect reports, from a year ago to two
+// years ahead. Forged, revoked and replaced tokens are all reported as not found.
+func (s *Service) RenderCalendarFeed(token string) ([]byte, error) {
+	feedID, nonce, ok := leave.ParseFeedToken(s.feedSecret, token)
+	if !ok {
+		return nil, errors.NewNotFoundError("calendar feed not found")
+	}
+	feed, err := s.repository.GetCalendarFeed(feedID)
+	if err != nil {
+		return nil, err
+	}
+	if !feed.Matches(nonce) {
+		return nil, errors.NewNotFoundError("calendar feed not found")
+	}
+
+	// The user's current role decides whether team leave is shown, not their role when the feed was made
+	u, err := s.userRepository.GetUserByID(feed.UserID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("calendar feed not found")
+	}
+
+	calendar := &ical.Calendar{Name: "Leave – " + u.Username, Method: ical.MethodPublish, Events: []ical.Event{}}
+
+	emp, err := s.employeeRepository.GetEmployeeByUserID(feed.UserID)
+	if err != nil {
+		return calendar.Render(), nil // No employee record, so no leave
+	}
+	employeeIDs := []int{emp.ID}
+	if u.Role == user.RoleManager || u.Role == user.RoleAdmin {
+		reportIDs, err := s.employeeRepository.GetReportIDs(emp.ID)
+		if err != nil {
+			return nil, err
+		}
+		employeeIDs = append(employeeIDs, reportIDs...)
+	}
+
+	now := time.Now()
+	absences, err := s.repository.GetAbsences(postgres.AbsenceFilter{
+		From:        now.AddDate(0, 0, -leave.FeedPastDays),
+		To:          now.AddDate(0, 0, leave.FeedFutureDays),
+		Statuses:    []leave.LeaveStatus{leave.StatusApproved},
+		EmployeeIDs: employeeIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range absences {
+		calendar.Events = append(calendar.Events, leaveEvent(a, a.EmployeeID != emp.ID, a.EventSequence, now))
+	}
+	return calendar.Render(), nil
+}
+
+// attachLeaveInvite attaches a calendar invite for a leave request to an email to its employee:
+// a REQUEST adding or updating the event, or a CANCEL removing it. Each invite raises the
+// request's stored event sequence so calendar apps replace their copy. Without the sequence
+// the email is sent without an invite rather than with one clients might ignore.
+func (s *Service) attachLeaveInvite(notif *notification.Notification, lr *leave.LeaveRequest, emp *employee.Employee, method string) {
+	var sequence int
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		sequence, err = s.repository.NextEventSequence(tx, lr.ID)
+		return err
+	})
+	if err != nil {
+		errors.LogError(fmt.Sprintf("Failed to attach calendar invite for leave request %d", lr.ID), err)
+		return
+	}
+
+	absence := calendarAbsence(lr, emp)
+	event := leaveEvent(absence, false, sequence, time.Now())
+	if method == ical.MethodCancel {
+		event.Status = ical.StatusCancelled
+	}
+	event.Organizer = s.emailQueue.Sender()
+	event.Attendee = mail.Address{Name: absence.EmployeeName, Address: emp.Email}
+
+	calendar := &ical.Calendar{Method: method, Events: []ical.Event{event}}
+	notif.AttachmentName = "leave.ics"
+	notif.AttachmentType = fmt.Sprintf("text/calendar; method=%s; charset=utf-8", method)
+	notif.AttachmentContent = string(calendar.Render())
+}
+
+// leaveEvent describes an absence as an all-day calendar event, titled with the employee's name
+// when it is not the calendar owner's own leave
+func leaveEvent(a leave.CalendarAbsence, withName bool, sequence int, stamp time.Time) ical.Event {
+	return ical.Event{
+		UID:         leave.EventUID(a.LeaveRequestID),
+		Summary:     leave.EventSummary(a, withName),
+		Description: leave.EventDescription(a),
+		Start:       a.StartDate,
+		End:         a.EndDate,
+		Sequence:    sequence,
+		Status:      ical.StatusConfirmed,
+		Stamp:       stamp,
+	}
+}
+
+// feedNonce generates the random part of a calendar feed token
+func feedNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WrapError("failed to generate calendar feed token", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,310 @@
+package leave
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/notification"
+	"employee-service/models/payroll"
+	"employee-service/repositories"
+	"employee-service/services/email"
+	"employee-service/utils/ical"
+)
+
+// RequestCancellation asks for approved leave to be cancelled, in full or from the day the
+// employee returns. Nothing is refunded until an approver accepts the request.
+func (s *Service) RequestCancellation(id int, userID int, req *leave.CancelApprovedLeaveRequest) (*leave.LeaveCancellation, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+
+	var cancellation *leave.LeaveCancellation
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		leaveRequest, err := s.repository.LockLeaveRequest(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if leaveRequest.EmployeeID != emp.ID {
+			return errors.NewForbiddenError("you don't have permission to cancel this leave request")
+		}
+
+		fromDate := leaveRequest.StartDate
+		if req.FromDate != "" {
+			fromDate, _ = time.Parse("2006-01-02", req.FromDate)
+		}
+
+		// Check now that there is something to refund, so the approver isn't asked in vain
+		if _, err := leaveRequest.CancellationRefund(fromDate, time.Now()); err != nil {
+			return err
+		}
+
+		pending, err := s.repository.GetPendingCancellation(tx, id)
+		if err != nil {
+			return err
+		}
+		if pending != nil {
+			return errors.NewValidationError().AddField("status", "a cancellation of this leave request is already awaiting approval")
+		}
+
+		cancellation, err = s.repository.CreateCancellation(tx, &leave.LeaveCancellation{
+			LeaveRequestID: id,
+			EmployeeID:     emp.ID,
+			RequestedBy:    userID,
+			FromDate:       fromDate,
+			Reason:         req.Reason,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Leave request %d: cancellation from %s requested by user %d",
+		id, cancellation.FromDate.Format("2006-01-02"), userID))
+	return cancellation, nil
+}
+
+// ListCancellations retrieves the cancellation requests a user may decide, optionally filtered by status
+func (s *Service) ListCancellations(userID int, role string, status string) ([]leave.LeaveCancellation, error) {
+	cancellations, err := s.repository.ListCancellations(status)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, errors.NewForbiddenError("admin or manager access required")
+	}
+
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return cancellations, nil
+	}
+
+	reviewable := []leave.LeaveCancellation{}
+	for _, c := range cancellations {
+		if team[c.EmployeeID] {
+			reviewable = append(reviewable, c)
+		}
+	}
+	return reviewable, nil
+}
+
+// ApproveCancellation accepts a cancellation request. The unused days go back to the leave
+// balance as a ledger reversal, their salary deduction is refunded through payroll, and the
+// leave request is cancelled or, when the employee returned early, ends the day before.
+func (s *Service) ApproveCancellation(id int, userID int, role string, comment string) (*leave.LeaveCancellation, error) {
+	var cancellation *leave.LeaveCancellation
+	var leaveRequest *leave.LeaveRequest
+	var cancelledUntil time.Time
+
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		cancellation, err = s.repository.LockCancellation(tx, id)
+		if err != nil {
+			return err
+		}
+		if cancellation.Status != leave.CancellationPending {
+			return errors.NewValidationError().AddField("status", "cancellation request has already been decided")
+		}
+
+		leaveRequest, err = s.repository.LockLeaveRequest(tx, cancellation.LeaveRequestID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeCancellationDecision(leaveRequest, userID, role); err != nil {
+			return err
+		}
+
+		// Days up to today have been taken by now even if they weren't when the request was made
+		refund, err := leaveRequest.CancellationRefund(cancellation.FromDate, time.Now())
+		if err != nil {
+			return err
+		}
+		cancelledUntil = leaveRequest.EndDate
+
+		if err := s.repository.RefundLeaveBalance(tx, leaveRequest, refund, cancellation.FromDate, userID); err != nil {
+			return err
+		}
+
+		// Give back the deducted pay for the cancelled days at the rates it was deducted at
+		deductions, err := s.payrollRepository.GetLeaveAdjustments(tx, leaveRequest.ID)
+		if err != nil {
+			return err
+		}
+		refunds := payroll.LeaveRefunds(leaveRequest, cancellation.FromDate, deductions)
+		for i := range refunds {
+			refunds[i].CreatedBy = &userID
+		}
+		if err := s.payrollRepository.CreateAdjustments(tx, refunds); err != nil {
+			return errors.WrapError("failed to refund salary for cancelled leave", err)
+		}
+		salaryRefund := payroll.TotalAmount(refunds)
+
+		if cancellation.IsFull(leaveRequest) {
+			if err := s.repository.CancelLeaveRequest(tx, leaveRequest.ID, leave.StatusApproved); err != nil {
+				return err
+			}
+		} else {
+			leaveRequest.Shorten(cancellation.FromDate, refund, salaryRefund)
+			if err := s.repository.ShortenLeaveRequest(tx, leaveRequest); err != nil {
+				return err
+			}
+		}
+
+		// The approver is back, so the rights they handed over while away return to them
+		if err := s.approvalRepository.CurtailLeaveDelegations(tx, leaveRequest.ID, cancellation.FromDate); err != nil {
+			return err
+		}
+
+		cancellation.Status = leave.CancellationApproved
+		cancellation.DaysRefunded = refund
+		cancellation.SalaryRefunded = salaryRefund
+		cancellation.DecidedBy = &userID
+		cancellation.DecisionComment = comment
+		return s.repository.DecideCancellation(tx, cancellation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Leave request %d: cancellation %d approved by user %d, %s days and %.2f salary refunded",
+		leaveRequest.ID, id, userID, leave.FormatDays(cancellation.DaysRefunded), cancellation.SalaryRefunded))
+
+	go s.queueLeaveCancelledNotification(leaveRequest, cancellation, cancelledUntil)
+
+	return cancellation, nil
+}
+
+// RejectCancellation declines a cancellation request; the leave stays approved
+func (s *Service) RejectCancellation(id int, userID int, role string, comment string) (*leave.LeaveCancellation, error) {
+	var cancellation *leave.LeaveCancellation
+
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		cancellation, err = s.repository.LockCancellation(tx, id)
+		if err != nil {
+			return err
+		}
+		if cancellation.Status != leave.CancellationPending {
+			return errors.NewValidationError().AddField("status", "cancellation request has already been decided")
+		}
+
+		leaveRequest, err := s.repository.LockLeaveRequest(tx, cancellation.LeaveRequestID)
+		if err != nil {
+			return err
+		}
+		if err := s.authorizeCancellationDecision(leaveRequest, userID, role); err != nil {
+			return err
+		}
+
+		cancellation.Status = leave.CancellationRejected
+		cancellation.DecidedBy = &userID
+		cancellation.DecisionComment = comment
+		return s.repository.DecideCancellation(tx, cancellation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cancellation, nil
+}
+
+// authorizeCancellationDecision checks that a user may decide the cancellation of a leave
+// request: admins, and managers of the employee or their delegates, but never the employee
+func (s *Service) authorizeCancellationDecision(leaveRequest *leave.LeaveRequest, userID int, role string) error {
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return err
+	}
+	if len(approvers) == 0 {
+		return errors.NewForbiddenError("admin or manager access required")
+	}
+
+	if emp, err := s.employeeRepository.GetEmployeeByUserID(userID); err == nil {
+		if err := leave.CheckNotOwnRequest(leaveRequest.EmployeeID, emp.ID, "leave cancellation"); err != nil {
+			return err
+		}
+	}
+
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return err
+	}
+	if !all && !team[leaveRequest.EmployeeID] {
+		return errors.NewForbiddenError("you can only decide cancellations of your reports' leave")
+	}
+	return nil
+}
+
+// queueLeaveCancelledNotification queues email notification for approved leave being cancelled
+// from the cancellation's from date until cancelledUntil
+func (s *Service) queueLeaveCancelledNotification(leaveReq *leave.LeaveRequest, cancellation *leave.LeaveCancellation, cancelledUntil time.Time) {
+	emp, err := s.employeeRepository.GetEmployeeByID(leaveReq.EmployeeID)
+	if err != nil {
+		errors.LogError("Failed to get employee for cancellation notification", err)
+		return
+	}
+
+	// Describe the cancelled part of the leave
+	templateData := notification.TemplateData{
+		EmployeeName:  fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		EmployeeEmail: emp.Email,
+		EmployeeID:    emp.ID,
+		LeaveType:     string(leaveReq.LeaveType),
+		StartDate:     cancellation.FromDate.Format("2006-01-02"),
+		EndDate:       cancelledUntil.Format("2006-01-02"),
+		TotalDays:     cancellation.DaysRefunded,
+		DayPart:       leave.DayPartLabel(leaveReq.DayPart, leaveReq.Hours),
+	}
+
+	tmpl := notification.GetTemplate(notification.EventLeaveCancelled, false)
+	subject, body, err := email.RenderTemplate(tmpl, templateData)
+	if err != nil {
+		errors.LogError("Failed to render cancellation notification template", err)
+		return
+	}
+
+	notif := &notification.Notification{
+		LeaveRequestID:  &leaveReq.ID,
+		RecipientEmail:  emp.Email,
+		RecipientName:   fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		EventType:       notification.EventLeaveCancelled,
+		TemplateName:    tmpl.Name,
+		DeliveryChannel: notification.ChannelSMTP,
+		Status:          notification.StatusPending,
+		Subject:         subject,
+		Body:            body,
+		MaxRetries:      3,
+	}
+	// Remove the leave from the employee's calendar, or move its end when they came back early
+	if cancellation.IsFull(leaveReq) {
+		s.attachLeaveInvite(notif, leaveReq, emp, ical.MethodCancel)
+	} else {
+		s.attachLeaveInvite(notif, leaveReq, emp, ical.MethodRequest)
+	}
+
+	if _, err := s.notificationRepo.CreateNotification(notif); err != nil {
+		errors.LogError("Failed to create cancellation notification record", err)
+		return
+	}
+
+	if err := s.emailQueue.Enqueue(notif); err != nil {
+		errors.LogError("Failed to enqueue cancellation notification", err)
+	}
+}
@@ -0,0 +1,240 @@
+package leave
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/notification"
+	"employee-service/repositories"
+	"employee-service/services/email"
+)
+
+// AddComment adds a comment to the thread of a leave request. The employee who applied and the
+// approvers who can review the request may comment. An approver can ask for more information,
+// which pauses reminders for the step awaiting a decision; the employee's next comment answers it
+// and resumes them. Everyone else in the thread is emailed about the comment.
+func (s *Service) AddComment(id int, userID int, role string, req *leave.AddCommentRequest) (*leave.LeaveComment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeView(leaveRequest, userID, role); err != nil {
+		return nil, err
+	}
+
+	author, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("user")
+	}
+	authorName := author.Username
+	isOwner := false
+	if emp, err := s.employeeRepository.GetEmployeeByUserID(userID); err == nil {
+		authorName = fmt.Sprintf("%s %s", emp.FirstName, emp.LastName)
+		isOwner = emp.ID == leaveRequest.EmployeeID
+	}
+
+	if req.RequestInfo && isOwner {
+		return nil, errors.NewValidationError().AddField("request_info", "only approvers can ask for more information")
+	}
+
+	comment := &leave.LeaveComment{
+		LeaveRequestID: id,
+		AuthorUserID:   &userID,
+		AuthorName:     authorName,
+		Body:           req.Body,
+		RequestsInfo:   req.RequestInfo,
+	}
+
+	var resumed bool
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		current, err := s.repository.LockLeaveRequest(tx, id)
+		if err != nil {
+			return err
+		}
+
+		approvals, err := s.approvalRepository.GetApprovals(id)
+		if err != nil {
+			return err
+		}
+		step := leave.CurrentApproval(approvals)
+
+		switch {
+		case req.RequestInfo:
+			if current.Status != leave.StatusPending || step == nil {
+				return errors.NewValidationError().AddField("request_info", "more information can only be asked for on pending leave requests")
+			}
+			if _, err := s.approvalRepository.RequestInfo(tx, step.ID, time.Now()); err != nil {
+				return err
+			}
+		case isOwner && step != nil && step.InfoRequestedAt != nil:
+			// The employee's reply answers the request for information
+			if err := s.approvalRepository.ResumeApproval(tx, step.ID, time.Since(*step.InfoRequestedAt)); err != nil {
+				return err
+			}
+			resumed = true
+		}
+
+		return s.repository.CreateComment(tx, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RequestInfo {
+		errors.LogInfo(fmt.Sprintf("Leave request %d: user %d asked for more information", id, userID))
+	}
+	if resumed {
+		errors.LogInfo(fmt.Sprintf("Leave request %d: employee replied, approval reminders resumed", id))
+	}
+
+	go s.queueLeaveCommentNotifications(leaveRequest, comment)
+
+	return comment, nil
+}
+
+// GetComments retrieves the comment thread of a leave request. Employees can only see the threads
+// of their own requests; approvers also see those they can review.
+func (s *Service) GetComments(id int, userID int, role string) ([]leave.LeaveComment, error) {
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeView(leaveRequest, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetComments(id)
+}
+
+// queueLeaveCommentNotifications emails a new comment to everyone in the thread but its author: the
+// employee, the approvers of the step awaiting a decision and anyone else who has commented
+func (s *Service) queueLeaveCommentNotifications(leaveReq *leave.LeaveRequest, comment *leave.LeaveComment) {
+	recipients, err := s.commentRecipients(leaveReq, *comment.AuthorUserID)
+	if err != nil {
+		errors.LogError(fmt.Sprintf("Failed to get recipients for comment on leave request %d", leaveReq.ID), err)
+		return
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByID(leaveReq.EmployeeID)
+	if err != nil {
+		errors.LogError("Failed to get employee for comment notification", err)
+		return
+	}
+
+	templateData := notification.TemplateData{
+		EmployeeName:  fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		EmployeeEmail: emp.Email,
+		EmployeeID:    emp.ID,
+		LeaveType:     string(leaveReq.LeaveType),
+		StartDate:     leaveReq.StartDate.Format("2006-01-02"),
+		EndDate:       leaveReq.EndDate.Format("2006-01-02"),
+		TotalDays:     leaveReq.DaysCount,
+		DayPart:       leave.DayPartLabel(leaveReq.DayPart, leaveReq.Hours),
+		CommentAuthor: comment.AuthorName,
+		Comment:       comment.Body,
+		InfoRequested: comment.RequestsInfo,
+	}
+	tmpl := notification.GetTemplate(notification.EventLeaveComment, false)
+
+	for _, recipient := range recipients {
+		templateData.RecipientName = recipient.name
+
+		subject, body, err := email.RenderTemplate(tmpl, templateData)
+		if err != nil {
+			errors.LogError("Failed to render comment notification template", err)
+			continue
+		}
+
+		notif, err := s.notificationRepo.CreateNotification(&notification.Notification{
+			LeaveRequestID:  &leaveReq.ID,
+			RecipientEmail:  recipient.email,
+			RecipientName:   recipient.name,
+			EventType:       notification.EventLeaveComment,
+			TemplateName:    tmpl.Name,
+			DeliveryChannel: notification.ChannelSMTP,
+			Status:          notification.StatusPending,
+			Subject:         subject,
+			Body:            body,
+			MaxRetries:      3,
+		})
+		if err != nil {
+			errors.LogError("Failed to create comment notification", err)
+			continue
+		}
+
+		if err := s.emailQueue.Enqueue(notif); err != nil {
+			errors.LogError("Failed to enqueue comment notification", err)
+		}
+	}
+
+	errors.LogInfo(fmt.Sprintf("Leave request %d: comment %d sent to %d recipient(s)", leaveReq.ID, comment.ID, len(recipients)))
+}
+
+// commentRecipients returns who is emailed about a comment, leaving out its author
+func (s *Service) commentRecipients(leaveReq *leave.LeaveRequest, authorUserID int) ([]reminderRecipient, error) {
+	var recipients []reminderRecipient
+
+	emp, err := s.employeeRepository.GetEmployeeByID(leaveReq.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	if emp.UserID != nil {
+		recipients = append(recipients, reminderRecipient{
+			userID: *emp.UserID,
+			name:   fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+			email:  emp.Email,
+		})
+	}
+
+	approvals, err := s.approvalRepository.GetApprovals(leaveReq.ID)
+	if err != nil {
+		return nil, err
+	}
+	if step := leave.CurrentApproval(approvals); step != nil {
+		approvers, err := s.stepRecipients(leaveReq.EmployeeID, step)
+		if err != nil {
+			return nil, err
+		}
+		approvers, err = s.withDelegates(approvers, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, approvers...)
+	}
+
+	comments, err := s.repository.GetComments(leaveReq.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		if c.AuthorUserID == nil {
+			continue
+		}
+		commenter, err := s.userRepository.GetUserByID(*c.AuthorUserID)
+		if err != nil {
+			continue
+		}
+		recipients = append(recipients, reminderRecipient{userID: commenter.ID, name: commenter.Username, email: commenter.Email})
+	}
+
+	// Each user once, never the author
+	seen := map[int]bool{authorUserID: true}
+	unique := make([]reminderRecipient, 0, len(recipients))
+	for _, r := range recipients {
+		if seen[r.userID] || r.email == "" {
+			continue
+		}
+		seen[r.userID] = true
+		unique = append(unique, r)
+	}
+	return unique, nil
+}
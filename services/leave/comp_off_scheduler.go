@@ -0,0 +1,16 @@
+package leave
+
+import (
+	"time"
+
+	"employee-service/utils/scheduler"
+)
+
+// ScheduleCompOffExpiry registers the comp-off expiry job, which lapses comp-off left unused
+// when it expires once every interval
+func (s *Service) ScheduleCompOffExpiry(jobs *scheduler.Scheduler, interval time.Duration) error {
+	return jobs.Register("comp-off expiry", interval, func() error {
+		_, err := s.RunCompOffExpiry(time.Now())
+		return err
+	})
+}
@@ -0,0 +1,255 @@
+package leave
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+)
+
+// RequestCompOff asks for comp-off to be credited for working on a weekend or holiday. Nothing
+// is added to the COMP_OFF balance until an approver accepts the request.
+func (s *Service) RequestCompOff(userID int, req *leave.RequestCompOffRequest) (*leave.CompOffCredit, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+
+	workedDate, _ := time.Parse("2006-01-02", req.WorkedDate)
+	holidays, err := s.holidayRepository.GetHolidaysInRange(workedDate, workedDate, emp.Location)
+	if err != nil {
+		return nil, errors.WrapError("failed to load holiday calendar", err)
+	}
+	if err := s.compOffPolicy.CheckWorkedDate(workedDate, holidays, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var credit *leave.CompOffCredit
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		exists, err := s.repository.HasCompOffCredit(tx, emp.ID, workedDate)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.NewValidationError().AddField("worked_date", "comp-off for this date has already been requested")
+		}
+
+		credit, err = s.repository.CreateCompOffCredit(tx, &leave.CompOffCredit{
+			EmployeeID:   emp.ID,
+			EmployeeName: fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+			RequestedBy:  userID,
+			WorkedDate:   workedDate,
+			DayPart:      req.DayPart,
+			Days:         leave.LeaveDays(1, req.DayPart, 0),
+			Reason:       req.Reason,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Comp-off %d for %s requested by user %d",
+		credit.ID, credit.WorkedDate.Format("2006-01-02"), userID))
+	return credit, nil
+}
+
+// GetMyCompOffCredits retrieves the comp-off credits of the user's employee record
+func (s *Service) GetMyCompOffCredits(userID int) ([]leave.CompOffCredit, error) {
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+	return s.repository.GetEmployeeCompOffCredits(emp.ID)
+}
+
+// ListCompOffCredits retrieves the comp-off credits a user may decide, optionally filtered by status
+func (s *Service) ListCompOffCredits(userID int, role string, status string) ([]leave.CompOffCredit, error) {
+	credits, err := s.repository.ListCompOffCredits(status)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, errors.NewForbiddenError("admin or manager access required")
+	}
+
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return credits, nil
+	}
+
+	reviewable := []leave.CompOffCredit{}
+	for _, c := range credits {
+		if team[c.EmployeeID] {
+			reviewable = append(reviewable, c)
+		}
+	}
+	return reviewable, nil
+}
+
+// ApproveCompOff accepts a comp-off request, crediting its days to the employee's COMP_OFF
+// balance until they expire the configured number of days after the date worked
+func (s *Service) ApproveCompOff(id int, userID int, role string, comment string) (*leave.CompOffCredit, error) {
+	var credit *leave.CompOffCredit
+
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		credit, err = s.repository.LockCompOffCredit(tx, id)
+		if err != nil {
+			return err
+		}
+		if credit.Status != leave.CompOffPending {
+			return errors.NewValidationError().AddField("status", "comp-off request has already been decided")
+		}
+		if err := s.authorizeCompOffDecision(credit, userID, role); err != nil {
+			return err
+		}
+
+		// A request left undecided for too long would be credited only to lapse straight away
+		expiresOn := s.compOffPolicy.ExpiresOn(credit.WorkedDate)
+		if !time.Now().Before(expiresOn) {
+			return errors.NewValidationError().AddField("worked_date", "comp-off for this date has already expired")
+		}
+
+		credit.Status = leave.CompOffApproved
+		credit.DecidedBy = &userID
+		credit.DecisionComment = comment
+		credit.ExpiresOn = &expiresOn
+		if err := s.repository.DecideCompOffCredit(tx, credit); err != nil {
+			return err
+		}
+		return s.repository.CreditCompOff(tx, credit, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Comp-off %d approved by user %d, %s days credited to employee %d until %s",
+		id, userID, leave.FormatDays(credit.Days), credit.EmployeeID, credit.ExpiresOn.Format("2006-01-02")))
+	return credit, nil
+}
+
+// RejectCompOff declines a comp-off request; nothing is credited
+func (s *Service) RejectCompOff(id int, userID int, role string, comment string) (*leave.CompOffCredit, error) {
+	var credit *leave.CompOffCredit
+
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		credit, err = s.repository.LockCompOffCredit(tx, id)
+		if err != nil {
+			return err
+		}
+		if credit.Status != leave.CompOffPending {
+			return errors.NewValidationError().AddField("status", "comp-off request has already been decided")
+		}
+		if err := s.authorizeCompOffDecision(credit, userID, role); err != nil {
+			return err
+		}
+
+		credit.Status = leave.CompOffRejected
+		credit.DecidedBy = &userID
+		credit.DecisionComment = comment
+		return s.repository.DecideCompOffCredit(tx, credit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return credit, nil
+}
+
+// RunCompOffExpiry lapses the unused days of approved comp-off credits that have reached their
+// expiry date. Each employee is processed in their own transaction, so one failure does not
+// hold back the others, and expired credits are marked so a later run skips them.
+func (s *Service) RunCompOffExpiry(asOf time.Time) (*leave.CompOffExpiryResult, error) {
+	employeeIDs, err := s.repository.GetDueCompOffEmployeeIDs(asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &leave.CompOffExpiryResult{AsOf: asOf.Format("2006-01-02")}
+
+	for _, employeeID := range employeeIDs {
+		var expired int
+		var lapsed float64
+
+		err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+			expired, lapsed = 0, 0
+
+			credits, balance, err := s.repository.LockCompOffBalance(tx, employeeID)
+			if err != nil {
+				return err
+			}
+
+			byID := map[int]*leave.CompOffCredit{}
+			for i := range credits {
+				byID[credits[i].ID] = &credits[i]
+			}
+
+			for _, e := range leave.ExpireCompOff(credits, balance, asOf) {
+				if err := s.repository.ExpireCompOffCredit(tx, byID[e.CreditID], e.Days); err != nil {
+					return err
+				}
+				expired++
+				lapsed += e.Days
+			}
+			return nil
+		})
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to expire comp-off of employee %d", employeeID), err)
+			result.Errors++
+			continue
+		}
+
+		result.EmployeesProcessed++
+		result.CreditsExpired += expired
+		result.DaysLapsed = leave.RoundDays(result.DaysLapsed + lapsed)
+	}
+
+	errors.LogInfo(fmt.Sprintf("Comp-off expiry as of %s: %d employees, %d credits expired, %.2f days lapsed, %d errors",
+		result.AsOf, result.EmployeesProcessed, result.CreditsExpired, result.DaysLapsed, result.Errors))
+
+	return result, nil
+}
+
+// authorizeCompOffDecision checks that a user may decide a comp-off request: admins, and
+// managers of the employee or their delegates, but never the employee themselves
+func (s *Service) authorizeCompOffDecision(credit *leave.CompOffCredit, userID int, role string) error {
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return err
+	}
+	if len(approvers) == 0 {
+		return errors.NewForbiddenError("admin or manager access required")
+	}
+
+	if emp, err := s.employeeRepository.GetEmployeeByUserID(userID); err == nil {
+		if err := leave.CheckNotOwnRequest(credit.EmployeeID, emp.ID, "comp-off request"); err != nil {
+			return err
+		}
+	}
+
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return err
+	}
+	if !all && !team[credit.EmployeeID] {
+		return errors.NewForbiddenError("you can only decide comp-off requests of your reports")
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package leave
+
+import (
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+	"employee-service/repositories/postgres"
+)
+
+// GetTeamCalendar returns the approved and pending absences between two dates, grouped by
+// employee, team or department (admins and managers). Managers see their own leave and that of
+// their direct and indirect reports, including those of managers they stand in for; admins see
+// everyone.
+func (s *Service) GetTeamCalendar(userID int, role string, query *leave.CalendarQuery) ([]leave.CalendarGroup, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	approvers, err := s.actingApprovers(userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, errors.NewForbiddenError("admin or manager access required")
+	}
+	all, team, err := s.reviewableEmployees(approvers)
+	if err != nil {
+		return nil, err
+	}
+	if !all {
+		if self, err := s.employeeRepository.GetEmployeeByUserID(userID); err == nil {
+			team[self.ID] = true
+		}
+	}
+
+	from, to := query.Dates()
+	absences, err := s.repository.GetAbsences(postgres.AbsenceFilter{
+		From:       from,
+		To:         to,
+		Statuses:   []leave.LeaveStatus{leave.StatusApproved, leave.StatusPending},
+		Team:       query.Team,
+		Department: query.Department,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !all {
+		visible := []leave.CalendarAbsence{}
+		for _, a := range absences {
+			if team[a.EmployeeID] {
+				visible = append(visible, a)
+			}
+		}
+		absences = visible
+	}
+
+	return leave.GroupAbsences(absences, query.GroupBy), nil
+}
+
+// ListCoverageRules retrieves all coverage rules
+func (s *Service) ListCoverageRules() ([]leave.CoverageRule, error) {
+	return s.repository.ListCoverageRules(false)
+}
+
+// CreateCoverageRule adds a limit on how many of a team or department may be out at once (admin only)
+func (s *Service) CreateCoverageRule(req *leave.CoverageRuleRequest) (*leave.CoverageRule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rule := &leave.CoverageRule{}
+	applyCoverageRuleRequest(rule, req)
+
+	result, err := s.repository.CreateCoverageRule(rule)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("coverage rule for this group")
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateCoverageRule replaces a coverage rule (admin only). Leave already approved is not revisited.
+func (s *Service) UpdateCoverageRule(id int, req *leave.CoverageRuleRequest) (*leave.CoverageRule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repository.GetCoverageRule(id)
+	if err != nil {
+		return nil, err
+	}
+	applyCoverageRuleRequest(rule, req)
+
+	if err := s.repository.UpdateCoverageRule(rule); err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("coverage rule for this group")
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteCoverageRule deletes a coverage rule (admin only)
+func (s *Service) DeleteCoverageRule(id int) error {
+	return s.repository.DeleteCoverageRule(id)
+}
+
+// checkCoverage checks a leave request about to be approved against the active coverage rules of
+// the employee's team and department, counting the leave already approved. Each rule is locked
+// first, so two approvals in the same group cannot both take its last free place.
+func (s *Service) checkCoverage(tx *repositories.Transaction, leaveRequest *leave.LeaveRequest) ([]leave.CoverageConflict, error) {
+	rules, err := s.repository.ListCoverageRules(true)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByID(leaveRequest.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+	absence := calendarAbsence(leaveRequest, emp)
+
+	conflicts := []leave.CoverageConflict{}
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Applies(emp.Team, emp.Department) {
+			continue
+		}
+		if err := s.repository.LockCoverageRule(tx, rule.ID); err != nil {
+			return nil, err
+		}
+
+		others, err := s.repository.GetAbsencesTx(tx, coverageFilter(rule, leaveRequest.StartDate, leaveRequest.EndDate))
+		if err != nil {
+			return nil, err
+		}
+		if conflict := rule.Check(absence, others); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+	return conflicts, nil
+}
+
+// flagCoverageConflicts shows reviewers which pending requests would break a coverage rule if
+// approved now, counting the leave already approved
+func (s *Service) flagCoverageConflicts(leaveRequests []leave.LeaveRequestDetail) error {
+	rules, err := s.repository.ListCoverageRules(true)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	// Load each group's approved leave once, over the dates of all the pending requests
+	var from, to time.Time
+	for _, lr := range leaveRequests {
+		if lr.Status != leave.StatusPending {
+			continue
+		}
+		if from.IsZero() || lr.StartDate.Before(from) {
+			from = lr.StartDate
+		}
+		if lr.EndDate.After(to) {
+			to = lr.EndDate
+		}
+	}
+	if from.IsZero() {
+		return nil
+	}
+
+	employees := map[int]*employee.Employee{}
+	approved := map[int][]leave.CalendarAbsence{}
+	for i := range leaveRequests {
+		lr := &leaveRequests[i]
+		if lr.Status != leave.StatusPending {
+			continue
+		}
+
+		emp, ok := employees[lr.EmployeeID]
+		if !ok {
+			emp, err = s.employeeRepository.GetEmployeeByID(lr.EmployeeID)
+			if err != nil {
+				return err
+			}
+			employees[lr.EmployeeID] = emp
+		}
+
+		absence := leave.CalendarAbsence{
+			LeaveRequestID: lr.ID,
+			EmployeeID:     lr.EmployeeID,
+			EmployeeName:   lr.EmployeeName,
+			Team:           emp.Team,
+			Department:     emp.Department,
+			StartDate:      lr.StartDate,
+			EndDate:        lr.EndDate,
+		}
+		for j := range rules {
+			rule := &rules[j]
+			if !rule.Applies(emp.Team, emp.Department) {
+				continue
+			}
+			others, ok := approved[rule.ID]
+			if !ok {
+				others, err = s.repository.GetAbsences(coverageFilter(rule, from, to))
+				if err != nil {
+					return err
+				}
+				approved[rule.ID] = others
+			}
+			if conflict := rule.Check(absence, others); conflict != nil {
+				lr.CoverageConflicts = append(lr.CoverageConflicts, *conflict)
+			}
+		}
+	}
+	return nil
+}
+
+// coverageFilter selects the approved leave of a coverage rule's group between two dates
+func coverageFilter(rule *leave.CoverageRule, from, to time.Time) postgres.AbsenceFilter {
+	filter := postgres.AbsenceFilter{From: from, To: to, Statuses: []leave.LeaveStatus{leave.StatusApproved}}
+	if rule.Scope == leave.CoverageDepartment {
+		filter.Department = rule.Group
+	} else {
+		filter.Team = rule.Group
+	}
+	return filter
+}
+
+// calendarAbsence describes a leave request of an employee as it would appear on the team calendar
+func calendarAbsence(lr *leave.LeaveRequest, emp *employee.Employee) leave.CalendarAbsence {
+	return leave.CalendarAbsence{
+		LeaveRequestID: lr.ID,
+		EmployeeID:     emp.ID,
+		EmployeeName:   emp.FirstName + " " + emp.LastName,
+		Team:           emp.Team,
+		Department:     emp.Department,
+		LeaveType:      lr.LeaveType,
+		Status:         lr.Status,
+		StartDate:      lr.StartDate,
+		EndDate:        lr.EndDate,
+		DayPart:        lr.DayPart,
+		Hours:          lr.Hours,
+		DaysCount:      lr.DaysCount,
+	}
+}
+
+// applyCoverageRuleRequest copies a validated rule request onto a rule
+func applyCoverageRuleRequest(rule *leave.CoverageRule, req *leave.CoverageRuleRequest) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	rule.Name = req.Name
+	rule.Scope = req.Scope
+	rule.Group = req.Group
+	rule.MaxAbsent = req.MaxAbsent
+	rule.Action = req.Action
+	rule.IsActive = isActive
+}
@@ -0,0 +1,170 @@
+package leave
+
+import (
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/user"
+)
+
+// ListDelegations retrieves the approval delegations a user has given or received
+func (s *Service) ListDelegations(userID int) ([]leave.ApprovalDelegation, error) {
+	return s.approvalRepository.ListDelegations(userID)
+}
+
+// CreateDelegation hands the caller's approval rights to another user for a date range (admin or manager)
+func (s *Service) CreateDelegation(userID int, role string, req *leave.CreateDelegationRequest) (*leave.ApprovalDelegation, error) {
+	if role != user.RoleAdmin && role != user.RoleManager {
+		return nil, errors.NewForbiddenError("only approvers can delegate approval rights")
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateDelegate(userID, req.DelegateUserID); err != nil {
+		return nil, err
+	}
+
+	startDate, _ := time.Parse("2006-01-02", req.StartDate)
+	endDate, _ := time.Parse("2006-01-02", req.EndDate)
+
+	delegation := &leave.ApprovalDelegation{
+		DelegatorUserID: userID,
+		DelegateUserID:  req.DelegateUserID,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		Reason:          req.Reason,
+		Source:          leave.DelegationManual,
+	}
+
+	result, err := s.approvalRepository.CreateDelegation(delegation)
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("User %d delegated approvals to user %d from %s to %s",
+		userID, req.DelegateUserID, req.StartDate, req.EndDate))
+	return result, nil
+}
+
+// RevokeDelegation ends an approval delegation early. Only the delegator or an admin can revoke it.
+func (s *Service) RevokeDelegation(id int, userID int, role string) error {
+	delegation, err := s.approvalRepository.GetDelegation(id)
+	if err != nil {
+		return err
+	}
+
+	if role != user.RoleAdmin && delegation.DelegatorUserID != userID {
+		return errors.NewForbiddenError("you don't have permission to revoke this delegation")
+	}
+
+	return s.approvalRepository.RevokeDelegation(id)
+}
+
+// SetDefaultDelegate sets the user who receives the caller's approval rights whenever their own
+// leave is approved (admin or manager)
+func (s *Service) SetDefaultDelegate(userID int, role string, req *leave.DefaultDelegateRequest) error {
+	if role != user.RoleAdmin && role != user.RoleManager {
+		return errors.NewForbiddenError("only approvers can delegate approval rights")
+	}
+
+	if req.DelegateUserID != nil {
+		if err := s.validateDelegate(userID, *req.DelegateUserID); err != nil {
+			return err
+		}
+	}
+
+	return s.approvalRepository.SetDefaultDelegate(userID, req.DelegateUserID)
+}
+
+// validateDelegate checks that a delegate exists, is an approver and is not the delegator
+func (s *Service) validateDelegate(delegatorUserID, delegateUserID int) error {
+	if delegateUserID == delegatorUserID {
+		return errors.NewValidationError().AddField("delegate_user_id", "you cannot delegate to yourself")
+	}
+
+	delegate, err := s.userRepository.GetUserByID(delegateUserID)
+	if err != nil {
+		return errors.NewValidationError().AddField("delegate_user_id", "delegate user does not exist")
+	}
+	if !leave.CanReceiveDelegation(delegate.Role) {
+		return errors.NewValidationError().AddField("delegate_user_id", "delegate must be an admin or manager")
+	}
+
+	return nil
+}
+
+// delegateDuringLeave hands an approver's approval rights to their default delegate, or failing
+// that to their own manager, for the dates of their approved leave
+func (s *Service) delegateDuringLeave(leaveRequest *leave.LeaveRequest) error {
+	emp, err := s.employeeRepository.GetEmployeeByID(leaveRequest.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if emp.UserID == nil {
+		return nil
+	}
+
+	requester, err := s.userRepository.GetUserByID(*emp.UserID)
+	if err != nil {
+		return err
+	}
+	if requester.Role != user.RoleAdmin && requester.Role != user.RoleManager {
+		return nil
+	}
+
+	delegateUserID, err := s.approvalRepository.GetDefaultDelegate(requester.ID)
+	if err != nil {
+		return err
+	}
+	if delegateUserID == nil && emp.ManagerID != nil {
+		manager, err := s.employeeRepository.GetEmployeeByID(*emp.ManagerID)
+		if err != nil {
+			return err
+		}
+		delegateUserID = manager.UserID
+	}
+	if delegateUserID != nil {
+		delegate, err := s.userRepository.GetUserByID(*delegateUserID)
+		if err != nil {
+			return err
+		}
+		if !leave.CanReceiveDelegation(delegate.Role) {
+			delegateUserID = nil
+		}
+	}
+	if delegateUserID == nil {
+		errors.LogInfo(fmt.Sprintf("No delegate for user %d during leave request %d", requester.ID, leaveRequest.ID))
+		return nil
+	}
+
+	leaveRequestID := leaveRequest.ID
+	delegation := &leave.ApprovalDelegation{
+		DelegatorUserID: requester.ID,
+		DelegateUserID:  *delegateUserID,
+		StartDate:       leaveRequest.StartDate,
+		EndDate:         leaveRequest.EndDate,
+		Reason:          fmt.Sprintf("On leave (request #%d)", leaveRequest.ID),
+		Source:          leave.DelegationLeave,
+		LeaveRequestID:  &leaveRequestID,
+	}
+
+	if _, err := s.approvalRepository.CreateDelegation(delegation); err != nil {
+		return err
+	}
+
+	errors.LogInfo(fmt.Sprintf("User %d delegated approvals to user %d during leave request %d",
+		requester.ID, *delegateUserID, leaveRequest.ID))
+	return nil
+}
+
+// describeOnBehalfOf formats the delegator of a decision for log messages
+func describeOnBehalfOf(onBehalfOf *int) string {
+	if onBehalfOf == nil {
+		return ""
+	}
+	return fmt.Sprintf(" on behalf of user %d", *onBehalfOf)
+}
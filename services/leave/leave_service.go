@@ -1,16 +1,22 @@
 package leave
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"employee-service/errors"
 	"employee-service/models/employee"
 	"employee-service/models/leave"
 	"employee-service/models/notification"
+	"employee-service/models/payroll"
 	"employee-service/models/user"
+	"employee-service/repositories"
 	"employee-service/repositories/postgres"
 	"employee-service/services/email"
+	"employee-service/utils/ical"
+	"employee-service/utils/storage"
 )
 
 // Service handles business logic for leave requests
@@ -19,7 +25,20 @@ type Service struct {
 	employeeRepository *postgres.EmployeeRepository
 	userRepository     *postgres.UserRepository
 	notificationRepo   *postgres.NotificationRepository
+	holidayRepository  *postgres.HolidayRepository
+	policyRepository   *postgres.LeavePolicyRepository
+	accrualRepository  *postgres.LeaveAccrualRepository
+	rolloverRepository *postgres.LeaveRolloverRepository
+	approvalRepository *postgres.LeaveApprovalRepository
+	payrollRepository  *postgres.PayrollRepository
+	txManager          *repositories.TransactionManager
 	emailQueue         *email.EmailQueue
+	ratePolicy         payroll.RatePolicy // Daily rate used to price paid and unpaid leave
+	attachmentStorage  storage.Storage    // Where leave attachments are kept
+	attachmentLimits   leave.AttachmentLimits
+	auditLogger        *repositories.AuditLogger
+	feedSecret         string // Signs calendar feed tokens
+	compOffPolicy      leave.CompOffPolicy
 }
 
 // NewService creates a new leave service
@@ -28,23 +47,52 @@ func NewService(
 	employeeRepository *postgres.EmployeeRepository,
 	userRepository *postgres.UserRepository,
 	notificationRepo *postgres.NotificationRepository,
+	holidayRepository *postgres.HolidayRepository,
+	policyRepository *postgres.LeavePolicyRepository,
+	accrualRepository *postgres.LeaveAccrualRepository,
+	rolloverRepository *postgres.LeaveRolloverRepository,
+	approvalRepository *postgres.LeaveApprovalRepository,
+	payrollRepository *postgres.PayrollRepository,
+	txManager *repositories.TransactionManager,
 	emailQueue *email.EmailQueue,
+	ratePolicy payroll.RatePolicy,
+	attachmentStorage storage.Storage,
+	attachmentLimits leave.AttachmentLimits,
+	auditLogger *repositories.AuditLogger,
+	feedSecret string,
+	compOffPolicy leave.CompOffPolicy,
 ) *Service {
 	return &Service{
 		repository:         repository,
 		employeeRepository: employeeRepository,
 		userRepository:     userRepository,
 		notificationRepo:   notificationRepo,
+		holidayRepository:  holidayRepository,
+		policyRepository:   policyRepository,
+		accrualRepository:  accrualRepository,
+		rolloverRepository: rolloverRepository,
+		approvalRepository: approvalRepository,
+		payrollRepository:  payrollRepository,
+		txManager:          txManager,
 		emailQueue:         emailQueue,
+		ratePolicy:         ratePolicy,
+		attachmentStorage:  attachmentStorage,
+		attachmentLimits:   attachmentLimits,
+		auditLogger:        auditLogger,
+		feedSecret:         feedSecret,
+		compOffPolicy:      compOffPolicy,
 	}
 }
 
-// ApplyLeave handles leave application
-func (s *Service) ApplyLeave(userID int, req *leave.ApplyLeaveRequest) (*leave.LeaveRequest, error) {
+// ApplyLeave handles leave application, storing any supporting documents uploaded with it
+func (s *Service) ApplyLeave(userID int, req *leave.ApplyLeaveRequest, uploads []leave.AttachmentUpload) (*leave.LeaveRequest, error) {
 	// Validate request
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	if err := s.attachmentLimits.Check(uploads, 0); err != nil {
+		return nil, err
+	}
 
 	// Get employee by user_id
 	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
@@ -52,32 +100,67 @@ func (s *Service) ApplyLeave(userID int, req *leave.ApplyLeaveRequest) (*leave.L
 		return nil, errors.NotFoundError("employee record")
 	}
 
-	// Validate maternity leave eligibility (only females and married)
-	if req.LeaveType == leave.TypeMaternity {
-		if emp.Gender != "Female" {
-			validationErr := errors.NewValidationError()
-			validationErr.AddField("leave_type", "maternity leave is only available for female employees")
-			return nil, validationErr
+	leaveRequest, err := s.buildLeaveRequest(emp, req, len(uploads), false)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.createLeaveRequest(leaveRequest, userID, uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	// Queue email notifications asynchronously (don't block on email errors)
+	errors.LogInfo(fmt.Sprintf("🚀 LEAVE APPLIED: Leave request %d created | Employee: %d | Type: %s", result.ID, emp.ID, req.LeaveType))
+	errors.LogInfo(fmt.Sprintf("📧 Queuing email notifications in background goroutine..."))
+	go s.queueLeaveAppliedNotifications(result, emp)
+
+	return result, nil
+}
+
+// createLeaveRequest saves a built leave request with the documents uploaded with it, unless it
+// overlaps leave of the employee already pending or approved
+func (s *Service) createLeaveRequest(leaveRequest *leave.LeaveRequest, uploadedBy int, uploads []leave.AttachmentUpload) (*leave.LeaveRequest, error) {
+	var result *leave.LeaveRequest
+	var stored []string
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		// Serialise applications of the same employee so two overlapping requests can't both pass the check
+		if err := s.repository.LockEmployeeLeave(tx, leaveRequest.EmployeeID); err != nil {
+			return err
 		}
-		if !emp.MaritalStatus {
-			validationErr := errors.NewValidationError()
-			validationErr.AddField("leave_type", "maternity leave is only available for married employees")
-			return nil, validationErr
+
+		// Reject requests that overlap leave already pending or approved
+		if err := s.checkOverlaps(tx, leaveRequest, []leave.LeaveStatus{leave.StatusPending, leave.StatusApproved}); err != nil {
+			return err
 		}
-	}
 
-	// Validate paternity leave eligibility (only males and married)
-	if req.LeaveType == leave.TypePaternity {
-		if emp.Gender != "Male" {
-			validationErr := errors.NewValidationError()
-			validationErr.AddField("leave_type", "paternity leave is only available for male employees")
-			return nil, validationErr
+		var err error
+		result, err = s.repository.CreateLeaveRequest(tx, leaveRequest)
+		if err != nil {
+			return err
 		}
-		if !emp.MaritalStatus {
-			validationErr := errors.NewValidationError()
-			validationErr.AddField("leave_type", "paternity leave is only available for married employees")
-			return nil, validationErr
+
+		result.Attachments, stored, err = s.storeAttachments(tx, result.ID, uploadedBy, uploads)
+		return err
+	})
+	if err != nil {
+		s.deleteStoredFiles(stored)
+		return nil, err
+	}
+	return result, nil
+}
+
+// buildLeaveRequest checks an application against the leave policy and balance and builds the
+// leave request with its days, salary deduction and approval chain. With override the policy's
+// eligibility and document rules are waived; the balance is still checked.
+func (s *Service) buildLeaveRequest(emp *employee.Employee, req *leave.ApplyLeaveRequest, attachments int, override bool) (*leave.LeaveRequest, error) {
+	// Look up the policy that governs this leave type
+	policy, err := s.policyRepository.GetPolicy(req.LeaveType)
+	if err != nil {
+		if _, ok := err.(*errors.NotFoundErrorType); ok {
+			return nil, errors.NewValidationError().AddField("leave_type", fmt.Sprintf("unsupported leave type: %s", req.LeaveType))
 		}
+		return nil, err
 	}
 
 	// Parse dates
@@ -90,34 +173,48 @@ func (s *Service) ApplyLeave(userID int, req *leave.ApplyLeaveRequest) (*leave.L
 		return nil, errors.NewValidationError().AddField("end_date", "invalid date format")
 	}
 
-	// Calculate days
-	daysCount := leave.CalculateDays(startDate, endDate)
-	if daysCount == 0 {
+	// Calculate days, skipping weekends and holidays for the employee's location
+	holidays, err := s.holidayRepository.GetHolidaysInRange(startDate, endDate, emp.Location)
+	if err != nil {
+		return nil, errors.WrapError("failed to load holiday calendar", err)
+	}
+	workingDays, skippedDays := leave.CalculateDays(startDate, endDate, holidays)
+	if workingDays == 0 {
 		return nil, errors.NewValidationError().AddField("dates", "leave period must include at least one working day")
 	}
+	dayPart := req.GetDayPart()
+	daysCount := leave.LeaveDays(workingDays, dayPart, req.Hours)
+
+	if !override {
+		// Validate eligibility rules (gender, marital status, tenure, duration, notice)
+		if validationErr := policy.CheckEligibility(emp.Gender, emp.MaritalStatus, emp.Hired, startDate, daysCount, time.Now()); validationErr.HasErrors() {
+			return nil, validationErr
+		}
+
+		// Require a supporting document where the policy asks for one
+		if err := policy.CheckAttachment(daysCount, attachments); err != nil {
+			return nil, err
+		}
+	}
 
-	// Check leave balance - Auto-initialize if not found for managed leave types
+	// Check leave balance - Auto-initialize if not found
 	balance, err := s.repository.GetLeaveBalance(emp.ID, req.LeaveType)
 	if err != nil {
-		// If balance doesn't exist, try to initialize it for managed leave types
-		if req.LeaveType == leave.TypeAnnual || req.LeaveType == leave.TypeSick || req.LeaveType == leave.TypeCasual ||
-			req.LeaveType == leave.TypeMaternity || req.LeaveType == leave.TypePaternity || req.LeaveType == leave.TypeUnpaid || req.LeaveType == leave.TypePersonal {
-			// Auto-initialize leave balances for this employee
-			errors.LogInfo(fmt.Sprintf("Auto-initializing leave balances for employee %d", emp.ID))
-			if err := s.repository.InitializeLeaveBalances(emp.ID); err != nil {
-				errors.LogError(fmt.Sprintf("Failed to initialize leave balances for employee %d", emp.ID), err)
-				validationErr := errors.NewValidationError()
-				validationErr.AddField("leave_balance", fmt.Sprintf("failed to initialize leave balance: %v", err))
-				return nil, validationErr
-			}
-			// Retry getting the balance after initialization
-			balance, err = s.repository.GetLeaveBalance(emp.ID, req.LeaveType)
-			if err != nil {
-				errors.LogError(fmt.Sprintf("Failed to get leave balance after initialization for employee %d, type %s", emp.ID, req.LeaveType), err)
-				validationErr := errors.NewValidationError()
-				validationErr.AddField("leave_balance", fmt.Sprintf("leave balance not found for this leave type: %v", err))
-				return nil, validationErr
-			}
+		// Auto-initialize leave balances for this employee
+		errors.LogInfo(fmt.Sprintf("Auto-initializing leave balances for employee %d", emp.ID))
+		if err := s.InitializeLeaveBalances(emp.ID); err != nil {
+			errors.LogError(fmt.Sprintf("Failed to initialize leave balances for employee %d", emp.ID), err)
+			validationErr := errors.NewValidationError()
+			validationErr.AddField("leave_balance", fmt.Sprintf("failed to initialize leave balance: %v", err))
+			return nil, validationErr
+		}
+		// Retry getting the balance after initialization
+		balance, err = s.repository.GetLeaveBalance(emp.ID, req.LeaveType)
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to get leave balance after initialization for employee %d, type %s", emp.ID, req.LeaveType), err)
+			validationErr := errors.NewValidationError()
+			validationErr.AddField("leave_balance", fmt.Sprintf("leave balance not found for this leave type: %v", err))
+			return nil, validationErr
 		}
 	}
 	
@@ -125,7 +222,7 @@ func (s *Service) ApplyLeave(userID int, req *leave.ApplyLeaveRequest) (*leave.L
 	if balance != nil && balance.Balance < daysCount {
 		// Auto-reject if insufficient balance
 		validationErr := errors.NewValidationError()
-		validationErr.AddField("leave_balance", fmt.Sprintf("insufficient leave balance. Required: %d, Available: %d", daysCount, balance.Balance))
+		validationErr.AddField("leave_balance", fmt.Sprintf("insufficient leave balance. Required: %s, Available: %s", leave.FormatDays(daysCount), leave.FormatDays(balance.Balance)))
 		return nil, validationErr
 	}
 
@@ -142,31 +239,55 @@ func (s *Service) ApplyLeave(userID int, req *leave.ApplyLeaveRequest) (*leave.L
 		Reason:     req.Reason,
 		Notes:      notes,
 		DaysCount:  daysCount,
+		SkippedDays: skippedDays,
+		DayPart:    dayPart,
 	}
-
-	// Calculate salary deduction for paid leaves (500 per day)
-	if leave.IsPaidLeave(req.LeaveType) {
-		leaveRequest.SalaryDeduction = float64(daysCount) * 500.0
+	if dayPart == leave.DayPartHours {
+		leaveRequest.Hours = req.Hours
 	}
 
-	result, err := s.repository.CreateLeaveRequest(leaveRequest)
-	if err != nil {
+	if err := s.buildApprovalChain(leaveRequest); err != nil {
 		return nil, err
 	}
 
-	// Queue email notifications asynchronously (don't block on email errors)
-	errors.LogInfo(fmt.Sprintf("🚀 LEAVE APPLIED: Leave request %d created | Employee: %d | Type: %s", result.ID, emp.ID, req.LeaveType))
-	errors.LogInfo(fmt.Sprintf("📧 Queuing email notifications in background goroutine..."))
-	go s.queueLeaveAppliedNotifications(result, emp)
+	// Estimate the salary deduction for paid and unpaid leave; approval prices it again at the rates then
+	if policy.DeductsSalary() {
+		history, err := s.employeeRepository.GetCompensationHistory(emp.ID)
+		if err != nil {
+			return nil, err
+		}
+		leaveRequest.SalaryDeduction = -payroll.TotalAmount(payroll.LeaveDeductions(leaveRequest, emp, history, s.ratePolicy))
+	}
 
-	return result, nil
+	return leaveRequest, nil
+}
+
+// checkOverlaps rejects a leave request that overlaps requests of the same employee in the given statuses.
+// The caller must hold the employee's leave lock in tx
+func (s *Service) checkOverlaps(tx *repositories.Transaction, leaveRequest *leave.LeaveRequest, statuses []leave.LeaveStatus) error {
+	existing, err := s.repository.GetLeaveRequestsInRange(tx, leaveRequest.EmployeeID, leaveRequest.StartDate, leaveRequest.EndDate, statuses)
+	if err != nil {
+		return err
+	}
+
+	if conflicting := leaveRequest.FindOverlaps(existing); len(conflicting) > 0 {
+		return leave.NewOverlapError(conflicting)
+	}
+	return nil
 }
 
 // queueLeaveAppliedNotifications queues email notifications for leave application
 func (s *Service) queueLeaveAppliedNotifications(leaveReq *leave.LeaveRequest, emp *employee.Employee) {
 	errors.LogInfo(fmt.Sprintf("\n📧 ========== QUEUEING LEAVE APPLIED NOTIFICATIONS =========="))
 	errors.LogInfo(fmt.Sprintf("Leave Request ID: %d | Employee: %s (%d)\n", leaveReq.ID, emp.FirstName+" "+emp.LastName, emp.ID))
-	
+
+	// The templates differ for paid leave, so don't guess when the policy can't be read
+	isPaid, err := s.isPaidLeave(leaveReq.LeaveType)
+	if err != nil {
+		errors.LogError("Failed to queue leave applied notifications", err)
+		return
+	}
+
 	// Prepare template data
 	templateData := notification.TemplateData{
 		EmployeeName:    fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
@@ -176,13 +297,14 @@ func (s *Service) queueLeaveAppliedNotifications(leaveReq *leave.LeaveRequest, e
 		StartDate:       leaveReq.StartDate.Format("2006-01-02"),
 		EndDate:         leaveReq.EndDate.Format("2006-01-02"),
 		TotalDays:       leaveReq.DaysCount,
+		DayPart:         leave.DayPartLabel(leaveReq.DayPart, leaveReq.Hours),
 		Reason:          leaveReq.Reason,
-		IsPaidLeave:     leave.IsPaidLeave(leaveReq.LeaveType),
+		IsPaidLeave:     isPaid,
 	}
 
 	// 1. Send email to employee
 	errors.LogInfo(fmt.Sprintf("1️⃣  Sending notification to EMPLOYEE"))
-	empTemplate := notification.GetTemplate(notification.EventLeaveApplied, leave.IsPaidLeave(leaveReq.LeaveType))
+	empTemplate := notification.GetTemplate(notification.EventLeaveApplied, templateData.IsPaidLeave)
 	empSubject, empBody, err := email.RenderTemplate(empTemplate, templateData)
 	if err != nil {
 		errors.LogError("Failed to render employee notification template", err)
@@ -296,10 +418,25 @@ func (s *Service) GetEmployeeLeaveRequests(userID int) ([]leave.LeaveRequest, er
 
 // GetLeaveRequest retrieves a single leave request
 func (s *Service) GetLeaveRequest(id int) (*leave.LeaveRequest, error) {
-	return s.repository.GetLeaveRequest(id)
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveRequest.Approvals, err = s.approvalRepository.GetApprovals(id)
+	if err != nil {
+		return nil, err
+	}
+	leaveRequest.NeedsInfo = leave.AwaitingInfo(leaveRequest.Approvals)
+
+	leaveRequest.Attachments, err = s.repository.GetAttachments(id)
+	if err != nil {
+		return nil, err
+	}
+	return leaveRequest, nil
 }
 
-// CancelLeave cancels a pending leave request
+// CancelLeave cancels a pending leave request. Approved leave is cancelled with RequestCancellation.
 func (s *Service) CancelLeave(id int, userID int) error {
 	// Get employee by user_id
 	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
@@ -307,94 +444,246 @@ func (s *Service) CancelLeave(id int, userID int) error {
 		return errors.NotFoundError("employee record")
 	}
 
-	// Verify the leave request belongs to the employee
-	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	return s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		// Verify the leave request belongs to the employee
+		leaveRequest, err := s.repository.LockLeaveRequest(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if leaveRequest.EmployeeID != emp.ID {
+			return errors.NewForbiddenError("you don't have permission to cancel this leave request")
+		}
+
+		if leaveRequest.Status == leave.StatusApproved {
+			return errors.NewValidationError().AddField("status", "approved leave must be cancelled through a cancellation request")
+		}
+		if leaveRequest.Status != leave.StatusPending {
+			return errors.NewValidationError().AddField("status", "only pending leave requests can be cancelled")
+		}
+
+		if err := s.repository.CancelLeaveRequest(tx, id, leave.StatusPending); err != nil {
+			return err
+		}
+
+		return s.approvalRepository.SkipPendingApprovals(tx, id)
+	})
+}
+
+// GetAllLeaveRequests retrieves the leave requests a user may review. Admins see every request,
+// managers those of their direct and indirect reports, and delegates those of their delegators.
+func (s *Service) GetAllLeaveRequests(userID int, role string, status string) ([]leave.LeaveRequestDetail, error) {
+	leaveRequests, err := s.repository.GetAllLeaveRequests(status)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if leaveRequest.EmployeeID != emp.ID {
-		return errors.NewForbiddenError("you don't have permission to cancel this leave request")
+	if role != user.RoleAdmin {
+		leaveRequests, err = s.filterReviewableRequests(userID, role, leaveRequests)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if leaveRequest.Status != leave.StatusPending {
-		return errors.NewValidationError().AddField("status", "only pending leave requests can be cancelled")
+	// Show reviewers which step each pending request is waiting for
+	currentApprovals, err := s.approvalRepository.GetCurrentApprovals()
+	if err != nil {
+		return nil, err
+	}
+	for i := range leaveRequests {
+		if approval, ok := currentApprovals[leaveRequests[i].ID]; ok {
+			leaveRequests[i].CurrentApproval = &approval
+			leaveRequests[i].NeedsInfo = approval.InfoRequestedAt != nil
+		}
 	}
 
-	return s.repository.CancelLeaveRequest(id)
-}
+	// Show reviewers which requests would take too many of a team or department out at once
+	if err := s.flagCoverageConflicts(leaveRequests); err != nil {
+		return nil, err
+	}
 
-// GetAllLeaveRequests retrieves all leave requests (admin only)
-func (s *Service) GetAllLeaveRequests(status string) ([]leave.LeaveRequestDetail, error) {
-	return s.repository.GetAllLeaveRequests(status)
+	return leaveRequests, nil
 }
 
-// ApproveLeave approves a leave request (admin only)
-func (s *Service) ApproveLeave(id int, approvedByUserID int, notes string) error {
-	leaveRequest, err := s.repository.GetLeaveRequest(id)
+// ApproveLeave approves the current step of a leave request's approval chain (admin or manager).
+// The request stays PENDING until the final step is approved; only then is the balance deducted.
+// The request and balance rows are locked for the whole decision, so concurrent approvals of the
+// same request cannot deduct twice and a failure leaves nothing half-applied.
+func (s *Service) ApproveLeave(id int, approvedByUserID int, role string, notes string) (*leave.LeaveRequest, error) {
+	leaveRequest, err := s.approveLeave(id, approvedByUserID, role, notes)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	if leaveRequest.Status != leave.StatusPending {
-		return errors.NewValidationError().AddField("status", "only pending leave requests can be approved")
+	if leaveRequest.Status != leave.StatusApproved {
+		return leaveRequest, nil
 	}
 
-	// Deduct from leave balance for all managed leave types
-	if leave.IsManagedLeave(leaveRequest.LeaveType) {
-		err := s.repository.DeductLeaveBalance(leaveRequest.EmployeeID, leaveRequest.LeaveType, leaveRequest.DaysCount)
+	// Queue approval and low-balance notifications asynchronously
+	go s.queueLeaveApprovedNotification(leaveRequest, approvedByUserID)
+	go s.checkLowBalance(leaveRequest.EmployeeID, leaveRequest.LeaveType, time.Now())
+
+	return leaveRequest, nil
+}
+
+// approveLeave records an approval as ApproveLeave does without notifying anyone. The returned
+// request is APPROVED once its final step is approved and PENDING otherwise.
+func (s *Service) approveLeave(id int, approvedByUserID int, role string, notes string) (*leave.LeaveRequest, error) {
+	var leaveRequest *leave.LeaveRequest
+	approved := false
+
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		leaveRequest, err = s.repository.LockLeaveRequest(tx, id)
 		if err != nil {
 			return err
 		}
-	}
 
-	// Deduct salary for paid leaves
-	if leave.IsPaidLeave(leaveRequest.LeaveType) {
-		emp, err := s.employeeRepository.GetEmployeeByID(leaveRequest.EmployeeID)
-		if err != nil {
-			return errors.WrapError("failed to get employee for salary deduction", err)
+		if leaveRequest.Status != leave.StatusPending {
+			return errors.NewValidationError().AddField("status", "only pending leave requests can be approved")
 		}
 
-		salaryDeduction := float64(leaveRequest.DaysCount) * 500
-		newSalary := emp.Salary - salaryDeduction
+		approvals, err := s.approvalRepository.GetApprovals(id)
+		if err != nil {
+			return err
+		}
 
-		// Update employee salary
-		err = s.employeeRepository.UpdateEmployeeSalary(emp.ID, newSalary)
+		step := leave.CurrentApproval(approvals)
+		onBehalfOf, err := s.authorizeDecision(leaveRequest, approvals, step, approvedByUserID, role)
 		if err != nil {
-			return errors.WrapError("failed to deduct salary for paid leave", err)
+			return err
+		}
+
+		// Intermediate steps only record the decision
+		if step != nil && !leave.IsFinalApproval(approvals, step) {
+			if err := s.approvalRepository.DecideApproval(tx, step.ID, approvedByUserID, onBehalfOf, leave.DecisionApproved, notes); err != nil {
+				return err
+			}
+			errors.LogInfo(fmt.Sprintf("Leave request %d: approval step %d (%s) approved by user %d%s", id, step.Step, step.ApproverRole, approvedByUserID, describeOnBehalfOf(onBehalfOf)))
+			return nil
 		}
 
-		// Add deduction note to leave request
-		deductionNote := fmt.Sprintf("Your paid leave for %d days is approved. An amount of 500 per day (total: %.2f) from %s to %s of your leave has been deducted from your salary.",
-			leaveRequest.DaysCount, salaryDeduction, leaveRequest.StartDate.Format("2006-01-02"), leaveRequest.EndDate.Format("2006-01-02"))
-		
-		// Append admin notes if provided
-		if notes != "" {
-			deductionNote = deductionNote + " Admin notes: " + notes
+		// Lock the employee's leave before the overlap check and the balance lock, so concurrent
+		// approvals of overlapping requests of the same employee run one at a time
+		if err := s.repository.LockEmployeeLeave(tx, leaveRequest.EmployeeID); err != nil {
+			return err
 		}
-		
-		err = s.repository.UpdateLeaveRequestNotes(leaveRequest.ID, deductionNote)
+
+		// Another overlapping request may have been approved since this one was applied
+		if err := s.checkOverlaps(tx, leaveRequest, []leave.LeaveStatus{leave.StatusApproved}); err != nil {
+			return err
+		}
+
+		// Blocking coverage rules refuse the approval; the others are reported with the result
+		conflicts, err := s.checkCoverage(tx, leaveRequest)
 		if err != nil {
-			// Log but don't fail the approval
-			errors.LogError("Failed to update leave notes with deduction info", err)
+			return err
 		}
-	} else if notes != "" {
-		// For non-paid leaves, just add the admin notes
-		err := s.repository.UpdateLeaveRequestNotes(leaveRequest.ID, notes)
+		if err := leave.CoverageError(conflicts); err != nil {
+			return err
+		}
+		leaveRequest.CoverageConflicts = conflicts
+
+		policy, err := s.policyRepository.GetPolicy(leaveRequest.LeaveType)
 		if err != nil {
-			errors.LogError("Failed to update leave notes", err)
+			return errors.WrapError("failed to get leave policy", err)
 		}
+
+		// Deduct from leave balance
+		if err := s.repository.DeductLeaveBalance(tx, leaveRequest, approvedByUserID); err != nil {
+			return err
+		}
+
+		// Deduct salary for paid leaves, and for unpaid leave as leave without pay, through payroll
+		// adjustments in the pay periods the leave falls in; the employee's base salary is left alone
+		if policy.DeductsSalary() {
+			emp, err := s.employeeRepository.GetEmployeeByID(leaveRequest.EmployeeID)
+			if err != nil {
+				return err
+			}
+			history, err := s.employeeRepository.GetCompensationHistory(emp.ID)
+			if err != nil {
+				return err
+			}
+
+			// Each pay period is priced at the salary in effect on the leave dates in it
+			deductions := payroll.LeaveDeductions(leaveRequest, emp, history, s.ratePolicy)
+			for i := range deductions {
+				deductions[i].CreatedBy = &approvedByUserID
+			}
+			if err := s.payrollRepository.CreateAdjustments(tx, deductions); err != nil {
+				return errors.WrapError("failed to record salary deduction for leave", err)
+			}
+
+			leaveRequest.SalaryDeduction = -payroll.TotalAmount(deductions)
+			if err := s.repository.UpdateLeaveRequestDeduction(tx, leaveRequest.ID, leaveRequest.SalaryDeduction); err != nil {
+				return err
+			}
+
+			// Add deduction note to leave request
+			kind := "paid leave"
+			if leaveRequest.LeaveType == leave.TypeUnpaid {
+				kind = "leave without pay"
+			}
+			deductionNote := fmt.Sprintf("Your %s for %s days from %s to %s is approved. A total of %.2f will be deducted from your pay (%s).",
+				kind, leave.FormatDays(leaveRequest.DaysCount), leaveRequest.StartDate.Format("2006-01-02"), leaveRequest.EndDate.Format("2006-01-02"),
+				leaveRequest.SalaryDeduction, describeDeductions(deductions))
+
+			// Append admin notes if provided
+			if notes != "" {
+				deductionNote = deductionNote + " Admin notes: " + notes
+			}
+
+			if err := s.repository.UpdateLeaveRequestNotes(tx, leaveRequest.ID, deductionNote); err != nil {
+				return err
+			}
+		} else if notes != "" {
+			// For leave that keeps full pay, just add the admin notes
+			if err := s.repository.UpdateLeaveRequestNotes(tx, leaveRequest.ID, notes); err != nil {
+				return err
+			}
+		}
+
+		if step != nil {
+			if err := s.approvalRepository.DecideApproval(tx, step.ID, approvedByUserID, onBehalfOf, leave.DecisionApproved, notes); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repository.UpdateLeaveRequestStatus(tx, id, string(leave.StatusApproved), &approvedByUserID); err != nil {
+			return err
+		}
+
+		approved = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.repository.UpdateLeaveRequestStatus(id, string(leave.StatusApproved), &approvedByUserID)
+	leaveRequest.Approvals, err = s.approvalRepository.GetApprovals(id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if !approved {
+		return leaveRequest, nil
 	}
 
-	// Queue approval notification asynchronously
-	go s.queueLeaveApprovedNotification(leaveRequest, approvedByUserID)
+	// Approvers hand their approval rights over while they are away
+	if err := s.delegateDuringLeave(leaveRequest); err != nil {
+		errors.LogError("Failed to delegate approvals during leave", err)
+	}
 
-	return nil
+	leaveRequest.Status = leave.StatusApproved
+	return leaveRequest, nil
+}
+
+// describeDeductions lists the days and amount deducted in each pay period, e.g. "2026-03: 2 days, 2000.00"
+func describeDeductions(deductions []payroll.PayrollAdjustment) string {
+	parts := make([]string, 0, len(deductions))
+	for _, d := range deductions {
+		parts = append(parts, fmt.Sprintf("%s: %s days, %.2f", d.PayPeriod, leave.FormatDays(d.Days), -d.Amount))
+	}
+	return strings.Join(parts, "; ")
 }
 
 // queueLeaveApprovedNotification queues email notification for leave approval
@@ -412,6 +701,12 @@ func (s *Service) queueLeaveApprovedNotification(leaveReq *leave.LeaveRequest, a
 		return
 	}
 
+	isPaid, err := s.isPaidLeave(leaveReq.LeaveType)
+	if err != nil {
+		errors.LogError("Failed to queue approval notification", err)
+		return
+	}
+
 	// Prepare template data
 	templateData := notification.TemplateData{
 		EmployeeName:   fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
@@ -423,12 +718,13 @@ func (s *Service) queueLeaveApprovedNotification(leaveReq *leave.LeaveRequest, a
 		StartDate:      leaveReq.StartDate.Format("2006-01-02"),
 		EndDate:        leaveReq.EndDate.Format("2006-01-02"),
 		TotalDays:      leaveReq.DaysCount,
+		DayPart:        leave.DayPartLabel(leaveReq.DayPart, leaveReq.Hours),
 		TotalDeduction: leaveReq.SalaryDeduction,
-		IsPaidLeave:    leave.IsPaidLeave(leaveReq.LeaveType),
+		IsPaidLeave:    isPaid,
 	}
 
 	// Send email to employee
-	tmpl := notification.GetTemplate(notification.EventLeaveApproved, leave.IsPaidLeave(leaveReq.LeaveType))
+	tmpl := notification.GetTemplate(notification.EventLeaveApproved, templateData.IsPaidLeave)
 	subject, body, err := email.RenderTemplate(tmpl, templateData)
 	if err != nil {
 		errors.LogError("Failed to render approval notification template", err)
@@ -447,6 +743,8 @@ func (s *Service) queueLeaveApprovedNotification(leaveReq *leave.LeaveRequest, a
 		Body:            body,
 		MaxRetries:      3,
 	}
+	// Add the leave to the employee's calendar
+	s.attachLeaveInvite(notif, leaveReq, emp, ical.MethodRequest)
 
 	_, err = s.notificationRepo.CreateNotification(notif)
 	if err != nil {
@@ -461,34 +759,69 @@ func (s *Service) queueLeaveApprovedNotification(leaveReq *leave.LeaveRequest, a
 	}
 }
 
-// RejectLeave rejects a leave request (admin only)
-func (s *Service) RejectLeave(id int, approvedByUserID int, reason string) error {
-	leaveRequest, err := s.repository.GetLeaveRequest(id)
+// RejectLeave rejects a leave request (admin or manager). The request row is locked for the
+// whole decision so it cannot race an approval.
+func (s *Service) RejectLeave(id int, approvedByUserID int, role string, reason string) error {
+	leaveRequest, err := s.rejectLeave(id, approvedByUserID, role, reason)
 	if err != nil {
 		return err
 	}
 
-	if leaveRequest.Status != leave.StatusPending {
-		return errors.NewValidationError().AddField("status", "only pending leave requests can be rejected")
-	}
+	// Queue rejection notification asynchronously
+	go s.queueLeaveRejectedNotification(leaveRequest, reason)
+
+	return nil
+}
+
+// rejectLeave records a rejection as RejectLeave does without notifying anyone
+func (s *Service) rejectLeave(id int, approvedByUserID int, role string, reason string) (*leave.LeaveRequest, error) {
+	var leaveRequest *leave.LeaveRequest
 
-	// Add rejection reason to notes if provided
-	if reason != "" {
-		err := s.repository.UpdateLeaveRequestNotes(leaveRequest.ID, "Rejection reason: "+reason)
+	err := s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		var err error
+		leaveRequest, err = s.repository.LockLeaveRequest(tx, id)
 		if err != nil {
-			errors.LogError("Failed to update leave notes with rejection reason", err)
+			return err
 		}
-	}
 
-	err = s.repository.UpdateLeaveRequestStatus(id, string(leave.StatusRejected), &approvedByUserID)
+		if leaveRequest.Status != leave.StatusPending {
+			return errors.NewValidationError().AddField("status", "only pending leave requests can be rejected")
+		}
+
+		// Record the rejection on the current step; later steps are never reached
+		approvals, err := s.approvalRepository.GetApprovals(id)
+		if err != nil {
+			return err
+		}
+		step := leave.CurrentApproval(approvals)
+		onBehalfOf, err := s.authorizeDecision(leaveRequest, approvals, step, approvedByUserID, role)
+		if err != nil {
+			return err
+		}
+		if step != nil {
+			if err := s.approvalRepository.DecideApproval(tx, step.ID, approvedByUserID, onBehalfOf, leave.DecisionRejected, reason); err != nil {
+				return err
+			}
+			if err := s.approvalRepository.SkipPendingApprovals(tx, id); err != nil {
+				return err
+			}
+		}
+
+		// Add rejection reason to notes if provided
+		if reason != "" {
+			if err := s.repository.UpdateLeaveRequestNotes(tx, leaveRequest.ID, "Rejection reason: "+reason); err != nil {
+				return err
+			}
+		}
+
+		return s.repository.UpdateLeaveRequestStatus(tx, id, string(leave.StatusRejected), &approvedByUserID)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Queue rejection notification asynchronously
-	go s.queueLeaveRejectedNotification(leaveRequest, reason)
-
-	return nil
+	leaveRequest.Status = leave.StatusRejected
+	return leaveRequest, nil
 }
 
 // queueLeaveRejectedNotification queues email notification for leave rejection
@@ -509,6 +842,7 @@ func (s *Service) queueLeaveRejectedNotification(leaveReq *leave.LeaveRequest, r
 		StartDate:       leaveReq.StartDate.Format("2006-01-02"),
 		EndDate:         leaveReq.EndDate.Format("2006-01-02"),
 		TotalDays:       leaveReq.DaysCount,
+		DayPart:         leave.DayPartLabel(leaveReq.DayPart, leaveReq.Hours),
 		RejectionReason: reason,
 	}
 
@@ -555,11 +889,9 @@ func (s *Service) GetEmployeeLeaveBalance(userID int, leaveType leave.LeaveType)
 
 	balance, err := s.repository.GetLeaveBalance(emp.ID, leaveType)
 	if err != nil {
-		// Auto-initialize leave balances for managed leave types
-		if leaveType == leave.TypeAnnual || leaveType == leave.TypeSick || leaveType == leave.TypeCasual ||
-			leaveType == leave.TypeMaternity || leaveType == leave.TypePaternity || leaveType == leave.TypeUnpaid || leaveType == leave.TypePersonal {
-			// Auto-initialize leave balances for this employee
-			if err := s.repository.InitializeLeaveBalances(emp.ID); err != nil {
+		// Auto-initialize leave balances for leave types that have a policy
+		if _, policyErr := s.policyRepository.GetPolicy(leaveType); policyErr == nil {
+			if err := s.InitializeLeaveBalances(emp.ID); err != nil {
 				errors.LogError("Failed to initialize leave balances", err)
 				return nil, errors.WrapError("failed to initialize leave balance", err)
 			}
@@ -585,7 +917,35 @@ func (s *Service) GetEmployeeLeaveBalances(userID int) ([]leave.LeaveBalance, er
 	return s.repository.GetEmployeeLeaveBalances(emp.ID)
 }
 
-// InitializeLeaveBalances initializes default leave balances for a new employee
+// InitializeLeaveBalances initializes leave balances for a new employee from the active leave policies
+// and credits the accruals due so far this year
 func (s *Service) InitializeLeaveBalances(employeeID int) error {
-	return s.repository.InitializeLeaveBalances(employeeID)
-}
\ No newline at end of file
+	policies, err := s.policyRepository.ListPolicies(true)
+	if err != nil {
+		return errors.WrapError("failed to load leave policies", err)
+	}
+	if err := s.repository.InitializeLeaveBalances(employeeID, policies); err != nil {
+		return err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByID(employeeID)
+	if err != nil {
+		return errors.WrapError("failed to get employee", err)
+	}
+
+	result := &leave.AccrualRunResult{}
+	s.accrueEmployee(emp, policies, time.Now(), result)
+	if result.Errors > 0 {
+		return fmt.Errorf("failed to credit %d leave accruals for employee %d", result.Errors, employeeID)
+	}
+	return nil
+}
+
+// isPaidLeave reports whether the policy for the leave type deducts salary
+func (s *Service) isPaidLeave(leaveType leave.LeaveType) (bool, error) {
+	policy, err := s.policyRepository.GetPolicy(leaveType)
+	if err != nil {
+		return false, errors.WrapError(fmt.Sprintf("failed to get leave policy for %s", leaveType), err)
+	}
+	return policy.DeductsSalary(), nil
+}
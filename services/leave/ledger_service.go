@@ -0,0 +1,54 @@
+package leave
+
+import (
+	"strings"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// GetLeaveBalanceHistory retrieves the ledger behind the caller's balance for a leave type
+func (s *Service) GetLeaveBalanceHistory(userID int, leaveType leave.LeaveType) ([]leave.LeaveLedgerEntry, error) {
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+
+	return s.repository.GetLedgerEntries(emp.ID, leaveType)
+}
+
+// GetEmployeeLeaveBalanceHistory retrieves the ledger behind an employee's balance for a leave type (admin only)
+func (s *Service) GetEmployeeLeaveBalanceHistory(employeeID int, leaveType leave.LeaveType) ([]leave.LeaveLedgerEntry, error) {
+	if _, err := s.employeeRepository.GetEmployeeByID(employeeID); err != nil {
+		return nil, errors.NotFoundError("employee")
+	}
+
+	return s.repository.GetLedgerEntries(employeeID, leaveType)
+}
+
+// AdjustLeaveBalance adds or removes days from an employee's balance with an audited ledger entry (admin only)
+func (s *Service) AdjustLeaveBalance(employeeID int, adminUserID int, req *leave.AdjustLeaveBalanceRequest) (*leave.LeaveBalance, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	req.LeaveType = leave.LeaveType(strings.ToUpper(string(req.LeaveType)))
+
+	if _, err := s.employeeRepository.GetEmployeeByID(employeeID); err != nil {
+		return nil, errors.NotFoundError("employee")
+	}
+
+	if _, err := s.repository.GetLeaveBalance(employeeID, req.LeaveType); err != nil {
+		if _, policyErr := s.policyRepository.GetPolicy(req.LeaveType); policyErr != nil {
+			return nil, errors.NewValidationError().AddField("leave_type", "unsupported leave type: "+string(req.LeaveType))
+		}
+		if err := s.InitializeLeaveBalances(employeeID); err != nil {
+			return nil, errors.WrapError("failed to initialize leave balance", err)
+		}
+	}
+
+	if err := s.repository.AdjustLeaveBalance(employeeID, req.LeaveType, req.Days, adminUserID, req.Reason); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetLeaveBalance(employeeID, req.LeaveType)
+}
@@ -0,0 +1,92 @@
+package leave
+
+import (
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/leave"
+	"employee-service/models/notification"
+	"employee-service/services/email"
+)
+
+// checkLowBalance warns an employee whose balance of a leave type has dropped to the policy's
+// low-balance threshold. It runs after an approval, so failures are only logged.
+func (s *Service) checkLowBalance(employeeID int, leaveType leave.LeaveType, at time.Time) {
+	policy, err := s.policyRepository.GetPolicy(leaveType)
+	if err != nil {
+		errors.LogError("Failed to get leave policy for low balance check", err)
+		return
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByID(employeeID)
+	if err != nil {
+		errors.LogError("Failed to get employee for low balance check", err)
+		return
+	}
+
+	if _, err := s.notifyLowBalance(emp, policy, at); err != nil {
+		errors.LogError(fmt.Sprintf("Failed to check %s balance of employee %d", leaveType, employeeID), err)
+	}
+}
+
+// notifyLowBalance queues a low-balance warning if the employee's balance is at or below the
+// policy's threshold. Employees are warned at most once per leave type and accrual period.
+// It returns whether a warning was queued.
+func (s *Service) notifyLowBalance(emp *employee.Employee, policy *leave.LeavePolicy, at time.Time) (bool, error) {
+	if policy.LowBalanceThreshold <= 0 {
+		return false, nil
+	}
+
+	balance, err := s.repository.GetLeaveBalance(emp.ID, policy.LeaveType)
+	if err != nil {
+		return false, err
+	}
+	if !policy.IsLowBalance(balance.Balance) {
+		return false, nil
+	}
+
+	claimed, err := s.notificationRepo.ClaimLowBalanceNotice(emp.ID, string(policy.LeaveType), policy.PeriodOf(at), balance.Balance)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	templateData := notification.TemplateData{
+		EmployeeName:        fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		EmployeeEmail:       emp.Email,
+		EmployeeID:          emp.ID,
+		LeaveType:           string(policy.LeaveType),
+		CurrentBalance:      balance.Balance,
+		LowBalanceThreshold: policy.LowBalanceThreshold,
+	}
+
+	tmpl := notification.GetTemplate(notification.EventLowBalance, false)
+	subject, body, err := email.RenderTemplate(tmpl, templateData)
+	if err != nil {
+		return false, err
+	}
+
+	notif, err := s.notificationRepo.CreateNotification(&notification.Notification{
+		RecipientEmail:  emp.Email,
+		RecipientName:   templateData.EmployeeName,
+		EventType:       notification.EventLowBalance,
+		TemplateName:    tmpl.Name,
+		DeliveryChannel: notification.ChannelSMTP,
+		Status:          notification.StatusPending,
+		Subject:         subject,
+		Body:            body,
+		MaxRetries:      3,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.emailQueue.Enqueue(notif); err != nil {
+		errors.LogError("Failed to enqueue low balance notification", err)
+	}
+
+	errors.LogInfo(fmt.Sprintf("Low %s balance warning queued for employee %d (balance %s, threshold %s)",
+		policy.LeaveType, emp.ID, leave.FormatDays(balance.Balance), leave.FormatDays(policy.LowBalanceThreshold)))
+	return true, nil
+}
@@ -0,0 +1,126 @@
+package leave
+
+import (
+	"strings"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+)
+
+// ListLeavePolicies retrieves all leave policies
+func (s *Service) ListLeavePolicies() ([]leave.LeavePolicy, error) {
+	return s.policyRepository.ListPolicies(false)
+}
+
+// GetLeavePolicy retrieves the policy for a leave type
+func (s *Service) GetLeavePolicy(leaveType leave.LeaveType) (*leave.LeavePolicy, error) {
+	return s.policyRepository.GetPolicy(leaveType)
+}
+
+// CreateLeavePolicy creates the policy for a new leave type (admin only)
+func (s *Service) CreateLeavePolicy(req *leave.CreateLeavePolicyRequest) (*leave.LeavePolicy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	accrualFrequency := leave.AccrualMonthly
+	if req.AccrualFrequency != "" {
+		accrualFrequency = strings.ToUpper(req.AccrualFrequency)
+	}
+
+	policy := &leave.LeavePolicy{
+		LeaveType:           leave.LeaveType(strings.ToUpper(strings.TrimSpace(string(req.LeaveType)))),
+		AnnualEntitlement:   req.AnnualEntitlement,
+		IsPaid:              req.IsPaid,
+		EligibleGender:      req.EligibleGender,
+		RequiresMarried:     req.RequiresMarried,
+		MinTenureDays:       req.MinTenureDays,
+		MaxConsecutiveDays:  req.MaxConsecutiveDays,
+		NoticeDays:          req.NoticeDays,
+		AccrualFrequency:    accrualFrequency,
+		CarryForwardMax:     req.CarryForwardMax,
+		EncashExcess:        req.EncashExcess,
+		LowBalanceThreshold: req.LowBalanceThreshold,
+		AttachmentAfterDays: req.AttachmentAfterDays,
+		IsActive:            isActive,
+	}
+
+	result, err := s.policyRepository.CreatePolicy(policy)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+			return nil, errors.ConflictError("leave policy for " + string(policy.LeaveType))
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateLeavePolicy updates the policy for a leave type (admin only)
+func (s *Service) UpdateLeavePolicy(leaveType leave.LeaveType, req *leave.UpdateLeavePolicyRequest) (*leave.LeavePolicy, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyRepository.GetPolicy(leaveType)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AnnualEntitlement != nil {
+		policy.AnnualEntitlement = *req.AnnualEntitlement
+	}
+	if req.IsPaid != nil {
+		policy.IsPaid = *req.IsPaid
+	}
+	if req.EligibleGender != nil {
+		policy.EligibleGender = *req.EligibleGender
+	}
+	if req.RequiresMarried != nil {
+		policy.RequiresMarried = *req.RequiresMarried
+	}
+	if req.MinTenureDays != nil {
+		policy.MinTenureDays = *req.MinTenureDays
+	}
+	if req.MaxConsecutiveDays != nil {
+		policy.MaxConsecutiveDays = *req.MaxConsecutiveDays
+	}
+	if req.NoticeDays != nil {
+		policy.NoticeDays = *req.NoticeDays
+	}
+	if req.AccrualFrequency != nil {
+		policy.AccrualFrequency = strings.ToUpper(*req.AccrualFrequency)
+	}
+	if req.CarryForwardMax != nil {
+		policy.CarryForwardMax = *req.CarryForwardMax
+	}
+	if req.EncashExcess != nil {
+		policy.EncashExcess = *req.EncashExcess
+	}
+	if req.LowBalanceThreshold != nil {
+		policy.LowBalanceThreshold = *req.LowBalanceThreshold
+	}
+	if req.AttachmentAfterDays != nil {
+		policy.AttachmentAfterDays = *req.AttachmentAfterDays
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	if err := s.policyRepository.UpdatePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// DeleteLeavePolicy deletes the policy for a leave type (admin only).
+// Existing balances are kept, but new applications for the type are rejected.
+func (s *Service) DeleteLeavePolicy(leaveType leave.LeaveType) error {
+	return s.policyRepository.DeletePolicy(leaveType)
+}
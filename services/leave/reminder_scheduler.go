@@ -0,0 +1,20 @@
+package leave
+
+import (
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/utils/scheduler"
+)
+
+// ScheduleApprovalReminders registers the approval reminder job, which reminds and escalates
+// pending approvals according to the policy once every interval
+func (s *Service) ScheduleApprovalReminders(jobs *scheduler.Scheduler, interval time.Duration, policy leave.ReminderPolicy) error {
+	errors.LogInfo(fmt.Sprintf("Approval reminders: remind after %s, escalate after %s", policy.RemindAfter, policy.EscalateAfter))
+	return jobs.Register("approval reminders", interval, func() error {
+		_, err := s.RunApprovalReminders(time.Now(), policy)
+		return err
+	})
+}
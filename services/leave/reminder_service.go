@@ -0,0 +1,260 @@
+package leave
+
+import (
+	"fmt"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/notification"
+	"employee-service/models/user"
+	"employee-service/services/email"
+)
+
+// reminderRecipient is a user who is emailed about a waiting approval step
+type reminderRecipient struct {
+	userID int
+	name   string
+	email  string
+}
+
+// RunApprovalReminders reminds the approvers of steps that have waited longer than the policy allows
+// and escalates steps that keep waiting: MANAGER steps to the line manager's own manager, otherwise
+// to all admins. Each step is reminded and escalated at most once, so repeated runs never send duplicates.
+func (s *Service) RunApprovalReminders(now time.Time, policy leave.ReminderPolicy) (*leave.ReminderRunResult, error) {
+	result := &leave.ReminderRunResult{RunAt: now.Format(time.RFC3339)}
+
+	pending, err := s.repository.GetAllLeaveRequests(string(leave.StatusPending))
+	if err != nil {
+		return nil, errors.WrapError("failed to load pending leave requests", err)
+	}
+
+	for i := range pending {
+		leaveRequest := &pending[i]
+
+		approvals, err := s.approvalRepository.GetApprovals(leaveRequest.ID)
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to load approvals of leave request %d", leaveRequest.ID), err)
+			result.Errors++
+			continue
+		}
+
+		step := leave.CurrentApproval(approvals)
+		if step == nil {
+			continue
+		}
+		result.StepsChecked++
+
+		pendingSince := leave.PendingSince(approvals, step, leaveRequest.CreatedAt)
+		action := policy.Due(step, step.Waiting(pendingSince, now))
+		if action == leave.ReminderNone {
+			continue
+		}
+
+		sent, err := s.sendApprovalReminder(leaveRequest, step, action, pendingSince, now)
+		if err != nil {
+			errors.LogError(fmt.Sprintf("Failed to send approval reminder for leave request %d", leaveRequest.ID), err)
+			result.Errors++
+			continue
+		}
+		if !sent {
+			continue
+		}
+
+		if action == leave.ReminderEscalate {
+			result.Escalated++
+		} else {
+			result.Reminded++
+		}
+	}
+
+	errors.LogInfo(fmt.Sprintf("Approval reminders: %d steps checked, %d reminded, %d escalated, %d errors",
+		result.StepsChecked, result.Reminded, result.Escalated, result.Errors))
+	return result, nil
+}
+
+// sendApprovalReminder claims a reminder or escalation for a step and queues its emails.
+// It returns false if another run already claimed it.
+func (s *Service) sendApprovalReminder(leaveRequest *leave.LeaveRequestDetail, step *leave.LeaveApproval, action leave.ReminderAction, pendingSince, now time.Time) (bool, error) {
+	var recipients []reminderRecipient
+	var err error
+	eventType := notification.EventApprovalReminder
+	if action == leave.ReminderEscalate {
+		eventType = notification.EventApprovalEscalation
+		recipients, err = s.escalationRecipients(leaveRequest.EmployeeID, step)
+	} else {
+		recipients, err = s.stepRecipients(leaveRequest.EmployeeID, step)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	recipients, err = s.withDelegates(recipients, now)
+	if err != nil {
+		return false, err
+	}
+
+	// Claim the notice before queueing so a concurrent run cannot send it again
+	var claimed bool
+	if action == leave.ReminderEscalate {
+		claimed, err = s.approvalRepository.MarkEscalated(step.ID, now)
+	} else {
+		claimed, err = s.approvalRepository.MarkReminded(step.ID, now)
+	}
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	templateData := notification.TemplateData{
+		EmployeeName: leaveRequest.EmployeeName,
+		EmployeeID:   leaveRequest.EmployeeID,
+		LeaveType:    string(leaveRequest.LeaveType),
+		StartDate:    leaveRequest.StartDate.Format("2006-01-02"),
+		EndDate:      leaveRequest.EndDate.Format("2006-01-02"),
+		TotalDays:    leaveRequest.DaysCount,
+		DayPart:      leave.DayPartLabel(leaveRequest.DayPart, leaveRequest.Hours),
+		Reason:       leaveRequest.Reason,
+		PendingSince: pendingSince.Format("2006-01-02 15:04"),
+	}
+	tmpl := notification.GetTemplate(eventType, false)
+
+	for _, recipient := range recipients {
+		templateData.AdminName = recipient.name
+		templateData.AdminEmail = recipient.email
+
+		subject, body, err := email.RenderTemplate(tmpl, templateData)
+		if err != nil {
+			errors.LogError("Failed to render approval reminder template", err)
+			continue
+		}
+
+		notif, err := s.notificationRepo.CreateNotification(&notification.Notification{
+			LeaveRequestID:  &leaveRequest.ID,
+			RecipientEmail:  recipient.email,
+			RecipientName:   recipient.name,
+			EventType:       eventType,
+			TemplateName:    tmpl.Name,
+			DeliveryChannel: notification.ChannelSMTP,
+			Status:          notification.StatusPending,
+			Subject:         subject,
+			Body:            body,
+			MaxRetries:      3,
+		})
+		if err != nil {
+			errors.LogError("Failed to create approval reminder notification", err)
+			continue
+		}
+
+		if err := s.emailQueue.Enqueue(notif); err != nil {
+			errors.LogError("Failed to enqueue approval reminder notification", err)
+		}
+	}
+
+	errors.LogInfo(fmt.Sprintf("Leave request %d: %s for approval step %d sent to %d recipient(s)",
+		leaveRequest.ID, eventType, step.Step, len(recipients)))
+	return true, nil
+}
+
+// stepRecipients returns who decides an approval step: the requester's line manager for MANAGER
+// steps, otherwise (or when there is no line manager) all admins
+func (s *Service) stepRecipients(employeeID int, step *leave.LeaveApproval) ([]reminderRecipient, error) {
+	if step.ApproverRole == leave.ApproverManager {
+		manager, err := s.managerRecipient(employeeID)
+		if err != nil {
+			return nil, err
+		}
+		if manager != nil {
+			return []reminderRecipient{*manager}, nil
+		}
+	}
+	return s.adminRecipients()
+}
+
+// escalationRecipients returns the next approver level for a step: the line manager's own manager
+// for MANAGER steps, otherwise (or when there is none) all admins
+func (s *Service) escalationRecipients(employeeID int, step *leave.LeaveApproval) ([]reminderRecipient, error) {
+	if step.ApproverRole == leave.ApproverManager {
+		emp, err := s.employeeRepository.GetEmployeeByID(employeeID)
+		if err != nil {
+			return nil, err
+		}
+		if emp.ManagerID != nil {
+			skipLevel, err := s.managerRecipient(*emp.ManagerID)
+			if err != nil {
+				return nil, err
+			}
+			if skipLevel != nil {
+				return []reminderRecipient{*skipLevel}, nil
+			}
+		}
+	}
+	return s.adminRecipients()
+}
+
+// managerRecipient returns the line manager of an employee, or nil if they have none with a login
+func (s *Service) managerRecipient(employeeID int) (*reminderRecipient, error) {
+	emp, err := s.employeeRepository.GetEmployeeByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if emp.ManagerID == nil {
+		return nil, nil
+	}
+
+	manager, err := s.employeeRepository.GetEmployeeByID(*emp.ManagerID)
+	if err != nil {
+		return nil, err
+	}
+	if manager.UserID == nil {
+		return nil, nil
+	}
+
+	return &reminderRecipient{
+		userID: *manager.UserID,
+		name:   fmt.Sprintf("%s %s", manager.FirstName, manager.LastName),
+		email:  manager.Email,
+	}, nil
+}
+
+// adminRecipients returns all admins
+func (s *Service) adminRecipients() ([]reminderRecipient, error) {
+	admins, err := s.userRepository.GetUsersByRole(user.RoleAdmin)
+	if err != nil {
+		return nil, errors.WrapError("failed to get admin users", err)
+	}
+
+	recipients := make([]reminderRecipient, 0, len(admins))
+	for _, admin := range admins {
+		recipients = append(recipients, reminderRecipient{userID: admin.ID, name: admin.Username, email: admin.Email})
+	}
+	return recipients, nil
+}
+
+// withDelegates adds the users standing in for the recipients today
+func (s *Service) withDelegates(recipients []reminderRecipient, now time.Time) ([]reminderRecipient, error) {
+	seen := map[int]bool{}
+	for _, r := range recipients {
+		seen[r.userID] = true
+	}
+
+	result := recipients
+	for _, r := range recipients {
+		delegations, err := s.approvalRepository.GetActiveDelegates(r.userID, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range delegations {
+			if seen[d.DelegateUserID] {
+				continue
+			}
+			seen[d.DelegateUserID] = true
+
+			delegate, err := s.userRepository.GetUserByID(d.DelegateUserID)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, reminderRecipient{userID: delegate.ID, name: delegate.Username, email: delegate.Email})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package leave
+
+import (
+	"context"
+	"fmt"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/repositories"
+)
+
+// UpdateLeave edits a pending leave request of the caller. The request is checked again against
+// the policy, balance and other leave as if newly applied, its days and salary deduction are
+// recalculated, and the change is kept as a revision. Changes to what approvers agreed to restart
+// the approval chain.
+func (s *Service) UpdateLeave(id int, userID int, req *leave.ApplyLeaveRequest) (*leave.LeaveRequest, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+
+	attachments, err := s.repository.GetAttachments(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.buildLeaveRequest(emp, req, len(attachments), false)
+	if err != nil {
+		return nil, err
+	}
+	updated.ID = id
+
+	var changes []leave.FieldChange
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		// Lock the request before the employee, in the same order as approvals
+		current, err := s.repository.LockLeaveRequest(tx, id)
+		if err != nil {
+			return err
+		}
+		if current.EmployeeID != emp.ID {
+			return errors.NewForbiddenError("you don't have permission to edit this leave request")
+		}
+		if current.Status != leave.StatusPending {
+			return errors.NewValidationError().AddField("status", "only pending leave requests can be edited")
+		}
+
+		if err := s.repository.LockEmployeeLeave(tx, emp.ID); err != nil {
+			return err
+		}
+		if err := s.checkOverlaps(tx, updated, []leave.LeaveStatus{leave.StatusPending, leave.StatusApproved}); err != nil {
+			return err
+		}
+
+		changes = leave.DiffLeaveRequests(current, updated)
+		if len(changes) == 0 {
+			return nil
+		}
+
+		updated.Status = current.Status
+		updated.CreatedAt = current.CreatedAt
+		if err := s.repository.UpdateLeaveRequest(tx, updated); err != nil {
+			return err
+		}
+
+		if leave.NeedsReapproval(changes) {
+			if err := s.approvalRepository.ReplaceApprovals(tx, id, updated.Approvals); err != nil {
+				return err
+			}
+		}
+
+		return s.repository.CreateRevision(tx, &leave.LeaveRequestRevision{
+			LeaveRequestID: id,
+			EditedBy:       userID,
+			Changes:        changes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		errors.LogInfo(fmt.Sprintf("Leave request %d edited by user %d: %d field(s) changed", id, userID, len(changes)))
+	}
+
+	return s.GetLeaveRequest(id)
+}
+
+// GetLeaveRevisions retrieves the edits of a leave request. Employees can only see the edits of
+// their own requests; approvers also see those they can review.
+func (s *Service) GetLeaveRevisions(id int, userID int, role string) ([]leave.LeaveRequestRevision, error) {
+	leaveRequest, err := s.repository.GetLeaveRequest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeView(leaveRequest, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetRevisions(id)
+}
@@ -0,0 +1,120 @@
+package leave
+
+import (
+	"fmt"
+	"math"
+
+	"employee-service/errors"
+	"employee-service/models/leave"
+	"employee-service/models/payroll"
+)
+
+// RunYearEnd closes the given leave year: each balance still in that year is carried
+// forward, lapsed or encashed according to its leave policy and moved to the next year.
+// With dryRun the report is computed without changing anything. Balances that were
+// already rolled over are skipped, so the run is safe to repeat.
+func (s *Service) RunYearEnd(year int, dryRun bool) (*leave.RolloverReport, error) {
+	if year < 2000 || year > 9999 {
+		return nil, errors.NewValidationError().AddField("year", "invalid leave year")
+	}
+
+	policies, err := s.policyRepository.ListPolicies(false)
+	if err != nil {
+		return nil, errors.WrapError("failed to load leave policies", err)
+	}
+	policyByType := make(map[leave.LeaveType]*leave.LeavePolicy, len(policies))
+	for i := range policies {
+		policyByType[policies[i].LeaveType] = &policies[i]
+	}
+
+	balances, err := s.rolloverRepository.GetYearEndBalances(year)
+	if err != nil {
+		return nil, err
+	}
+
+	alreadyProcessed, err := s.rolloverRepository.CountRollovers(year)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := s.employeeRepository.GetCompensationHistories()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &leave.RolloverReport{
+		Year:             year,
+		DryRun:           dryRun,
+		Employees:        []leave.EmployeeRolloverEntry{},
+		AlreadyProcessed: alreadyProcessed,
+	}
+
+	for _, balance := range balances {
+		policy, ok := policyByType[balance.LeaveType]
+		if !ok {
+			// No policy left for this type: nothing carries forward
+			policy = &leave.LeavePolicy{LeaveType: balance.LeaveType}
+		}
+
+		rollover := policy.YearEnd(balance.EmployeeID, year, balance.Balance)
+		rollover.EmployeeName = balance.EmployeeName
+
+		// Encashed days are paid at the employee's daily rate through a payroll adjustment
+		var encashment *payroll.PayrollAdjustment
+		if rollover.Encashed > 0 {
+			emp, err := s.employeeRepository.GetEmployeeByID(balance.EmployeeID)
+			if err != nil {
+				errors.LogError(fmt.Sprintf("Failed to price encashed %s leave for employee %d", balance.LeaveType, balance.EmployeeID), err)
+				report.Errors++
+				continue
+			}
+			adjustment := payroll.LeaveEncashment(&rollover, emp, histories[emp.ID], s.ratePolicy)
+			encashment = &adjustment
+		}
+
+		if !dryRun {
+			applied, err := s.rolloverRepository.ApplyRollover(&rollover, encashment)
+			if err != nil {
+				errors.LogError(fmt.Sprintf("Failed to roll over %s leave for employee %d", balance.LeaveType, balance.EmployeeID), err)
+				report.Errors++
+				continue
+			}
+			if !applied {
+				// Rolled over concurrently by another run
+				report.AlreadyProcessed++
+				continue
+			}
+		}
+
+		addRolloverToReport(report, rollover)
+	}
+
+	errors.LogInfo(fmt.Sprintf("Leave year-end %d (dry run: %t): %d balances, %s carried, %s lapsed, %s encashed, %d errors",
+		year, dryRun, report.BalancesProcessed, leave.FormatDays(report.TotalCarriedForward), leave.FormatDays(report.TotalLapsed), leave.FormatDays(report.TotalEncashed), report.Errors))
+
+	return report, nil
+}
+
+// GetYearEndRollovers retrieves the rollovers recorded for a leave year
+func (s *Service) GetYearEndRollovers(year int) ([]leave.LeaveRollover, error) {
+	return s.rolloverRepository.GetRollovers(year)
+}
+
+// addRolloverToReport adds a rollover to the per-employee report and the totals
+func addRolloverToReport(report *leave.RolloverReport, rollover leave.LeaveRollover) {
+	n := len(report.Employees)
+	if n == 0 || report.Employees[n-1].EmployeeID != rollover.EmployeeID {
+		report.Employees = append(report.Employees, leave.EmployeeRolloverEntry{
+			EmployeeID:   rollover.EmployeeID,
+			EmployeeName: rollover.EmployeeName,
+		})
+		n++
+	}
+	report.Employees[n-1].Changes = append(report.Employees[n-1].Changes, rollover)
+
+	report.BalancesProcessed++
+	report.TotalCarriedForward = leave.RoundDays(report.TotalCarriedForward + rollover.CarriedForward)
+	report.TotalLapsed = leave.RoundDays(report.TotalLapsed + rollover.Lapsed)
+	report.TotalEncashed = leave.RoundDays(report.TotalEncashed + rollover.Encashed)
+	report.TotalEncashAmount = math.Round((report.TotalEncashAmount+rollover.EncashAmount)*100) / 100
+}
@@ -0,0 +1,251 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"employee-service/errors"
+	"employee-service/models/employee"
+	"employee-service/models/payroll"
+	"employee-service/repositories"
+	"employee-service/repositories/postgres"
+)
+
+// employeePageSize is the number of employees loaded per page during a payroll run
+const employeePageSize = 100
+
+// Service handles business logic for payroll
+type Service struct {
+	repository         *postgres.PayrollRepository
+	employeeRepository *postgres.EmployeeRepository
+	txManager          *repositories.TransactionManager
+	ratePolicy         payroll.RatePolicy
+}
+
+// NewService creates a new payroll service
+func NewService(
+	repository *postgres.PayrollRepository,
+	employeeRepository *postgres.EmployeeRepository,
+	txManager *repositories.TransactionManager,
+	ratePolicy payroll.RatePolicy,
+) *Service {
+	return &Service{
+		repository:         repository,
+		employeeRepository: employeeRepository,
+		txManager:          txManager,
+		ratePolicy:         ratePolicy,
+	}
+}
+
+// GetDeductions lists the leave deductions and refunds due in a pay period, totalled per employee
+func (s *Service) GetDeductions(period string) (*payroll.DeductionReport, error) {
+	if _, err := payroll.ParsePeriod(period); err != nil {
+		return nil, err
+	}
+
+	adjustments, err := s.repository.ListAdjustments(period)
+	if err != nil {
+		return nil, err
+	}
+
+	leaveAdjustments := []payroll.PayrollAdjustment{}
+	for _, a := range adjustments {
+		switch a.Kind {
+		case payroll.KindLeaveDeduction, payroll.KindLeaveWithoutPay, payroll.KindLeaveRefund:
+			leaveAdjustments = append(leaveAdjustments, a)
+		}
+	}
+
+	return payroll.NewDeductionReport(period, s.ratePolicy, leaveAdjustments), nil
+}
+
+// RunPayroll computes the payslips of a pay period and locks it. Salaries are paid at the rates in
+// effect on each day of the period. Every unpaid adjustment dated in the period or earlier is paid,
+// so corrections to locked periods are settled by the next run. A period can only be run once.
+func (s *Service) RunPayroll(period string, userID int) (*payroll.PayrollRun, error) {
+	if err := payroll.CheckRunnable(period, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repository.GetRun(period); err == nil {
+		return nil, lockedPeriodError(period)
+	} else if _, ok := err.(*errors.NotFoundErrorType); !ok {
+		return nil, err
+	}
+
+	employees, err := s.loadEmployees()
+	if err != nil {
+		return nil, err
+	}
+	histories, err := s.employeeRepository.GetCompensationHistories()
+	if err != nil {
+		return nil, err
+	}
+
+	month, _ := payroll.ParsePeriod(period)
+	run := &payroll.PayrollRun{PayPeriod: period, RunBy: userID}
+
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		if err := s.repository.CreateRun(tx, run); err != nil {
+			if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "UNIQUE") {
+				return lockedPeriodError(period)
+			}
+			return err
+		}
+
+		if err := s.repository.ClaimAdjustments(tx, run.ID, period); err != nil {
+			return err
+		}
+		adjustments, err := s.repository.GetRunAdjustments(tx, run.ID)
+		if err != nil {
+			return err
+		}
+
+		byEmployee := map[int][]payroll.PayrollAdjustment{}
+		for _, a := range adjustments {
+			byEmployee[a.EmployeeID] = append(byEmployee[a.EmployeeID], a)
+		}
+
+		payslips := []payroll.Payslip{}
+		for _, emp := range employees {
+			// Employees hired later are left out unless an adjustment is due to them
+			if !payroll.IsPaidIn(emp, month) && len(byEmployee[emp.ID]) == 0 {
+				continue
+			}
+
+			payslip := payroll.NewPayslip(emp, histories[emp.ID], period, byEmployee[emp.ID])
+			payslip.PayrollRunID = run.ID
+			if err := s.repository.CreatePayslip(tx, &payslip); err != nil {
+				return err
+			}
+			payslips = append(payslips, payslip)
+		}
+
+		run.Totals(payslips)
+		return s.repository.UpdateRunTotals(tx, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Payroll %s run by user %d: %d payslips, gross %.2f, adjustments %.2f, net %.2f",
+		period, userID, run.EmployeeCount, run.TotalGross, run.TotalAdjustments, run.TotalNet))
+
+	return run, nil
+}
+
+// ListRuns retrieves all payroll runs, latest period first
+func (s *Service) ListRuns() ([]payroll.PayrollRun, error) {
+	return s.repository.ListRuns()
+}
+
+// GetPayslips retrieves the payslips of a pay period that has been run
+func (s *Service) GetPayslips(period string) ([]payroll.Payslip, error) {
+	if _, err := payroll.ParsePeriod(period); err != nil {
+		return nil, err
+	}
+
+	run, err := s.repository.GetRun(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetPayslips(run.ID, 0)
+}
+
+// GetPayslip retrieves an employee's payslip for a pay period that has been run
+func (s *Service) GetPayslip(period string, employeeID int) (*payroll.Payslip, error) {
+	if _, err := payroll.ParsePeriod(period); err != nil {
+		return nil, err
+	}
+
+	run, err := s.repository.GetRun(period)
+	if err != nil {
+		return nil, err
+	}
+
+	payslips, err := s.repository.GetPayslips(run.ID, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(payslips) == 0 {
+		return nil, errors.NewNotFoundError("payslip not found")
+	}
+	return &payslips[0], nil
+}
+
+// GetMyPayslips retrieves the payslips of the employee linked to a user, latest period first
+func (s *Service) GetMyPayslips(userID int) ([]payroll.Payslip, error) {
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+	return s.repository.GetEmployeePayslips(emp.ID)
+}
+
+// GetMyPayslip retrieves the payslip of the employee linked to a user for a pay period
+func (s *Service) GetMyPayslip(userID int, period string) (*payroll.Payslip, error) {
+	emp, err := s.employeeRepository.GetEmployeeByUserID(userID)
+	if err != nil {
+		return nil, errors.NotFoundError("employee record")
+	}
+	return s.GetPayslip(period, emp.ID)
+}
+
+// CreateCorrection records a correction to an employee's pay. A correction dated in a locked
+// period is paid by the next payroll run.
+func (s *Service) CreateCorrection(req *payroll.CreateCorrectionRequest, userID int) (*payroll.PayrollAdjustment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	emp, err := s.employeeRepository.GetEmployeeByID(req.EmployeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := []payroll.PayrollAdjustment{{
+		EmployeeID:   emp.ID,
+		EmployeeName: fmt.Sprintf("%s %s", emp.FirstName, emp.LastName),
+		PayPeriod:    req.PayPeriod,
+		Kind:         payroll.KindCorrection,
+		Amount:       req.Amount,
+		Description:  strings.TrimSpace(req.Description),
+		CreatedBy:    &userID,
+	}}
+
+	err = s.txManager.WithTx(context.Background(), func(tx *repositories.Transaction) error {
+		return s.repository.CreateAdjustments(tx, adjustments)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errors.LogInfo(fmt.Sprintf("Payroll correction of %.2f for employee %d in %s recorded by user %d",
+		req.Amount, emp.ID, req.PayPeriod, userID))
+
+	return &adjustments[0], nil
+}
+
+// loadEmployees loads every employee, page by page
+func (s *Service) loadEmployees() ([]*employee.Employee, error) {
+	all := []*employee.Employee{}
+	for offset := 0; ; offset += employeePageSize {
+		employees, err := s.employeeRepository.GetAllEmployees(employeePageSize, offset)
+		if err != nil {
+			return nil, errors.WrapError("failed to load employees", err)
+		}
+		all = append(all, employees...)
+
+		if len(employees) < employeePageSize {
+			return all, nil
+		}
+	}
+}
+
+// lockedPeriodError reports a pay period whose payroll has already been run
+func lockedPeriodError(period string) error {
+	return errors.ConflictError(fmt.Sprintf("payroll run for locked pay period %s", period))
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
 	_ "modernc.org/sqlite"
@@ -117,6 +118,64 @@ func CloseDatabase(db *sql.DB) error {
 	return CloseDB(db)
 }
 
+// defaultLeavePolicySeeds seeds leave_policies on first start; admins manage them via the API afterwards
+var defaultLeavePolicySeeds = []string{
+	"('ANNUAL', 10, TRUE, '', FALSE, 0, 0, 0, 'MONTHLY', 5, FALSE, 2, 0, TRUE, $1, $1)",
+	"('SICK', 15, TRUE, '', FALSE, 0, 0, 0, 'MONTHLY', 0, FALSE, 0, 2, TRUE, $1, $1)",
+	"('CASUAL', 10, FALSE, '', FALSE, 0, 0, 0, 'MONTHLY', 0, FALSE, 0, 0, TRUE, $1, $1)",
+	"('PERSONAL', 10, FALSE, '', FALSE, 0, 0, 0, 'MONTHLY', 0, FALSE, 0, 0, TRUE, $1, $1)",
+	"('UNPAID', 10, FALSE, '', FALSE, 0, 0, 0, 'ANNUAL', 0, FALSE, 0, 0, TRUE, $1, $1)",
+	"('MATERNITY', 90, FALSE, 'Female', TRUE, 0, 0, 0, 'ANNUAL', 0, FALSE, 0, 0, TRUE, $1, $1)",
+	"('PATERNITY', 7, FALSE, 'Male', TRUE, 0, 0, 0, 'ANNUAL', 0, FALSE, 0, 0, TRUE, $1, $1)",
+	"('COMP_OFF', 0, FALSE, '', FALSE, 0, 0, 0, 'ANNUAL', 0, FALSE, 0, 0, TRUE, $1, $1)", // Credited by approved comp-off requests, not accrued
+}
+
+// seedLeavePolicies inserts the default leave policies that do not exist yet
+func seedLeavePolicies(db *sql.DB) error {
+	now := time.Now()
+	for _, values := range defaultLeavePolicySeeds {
+		query := `
+		INSERT INTO leave_policies (leave_type, annual_entitlement, is_paid, eligible_gender, requires_married,
+		                            min_tenure_days, max_consecutive_days, notice_days, accrual_frequency,
+		                            carry_forward_max, encash_excess, low_balance_threshold, attachment_required_after_days,
+		                            is_active, created_at, updated_at)
+		VALUES ` + values + `
+		ON CONFLICT (leave_type) DO NOTHING`
+		if DBType == "sqlite" {
+			query = strings.ReplaceAll(query, "$1", "?1")
+		}
+		if _, err := db.Exec(query, now); err != nil {
+			return errors.WrapError("failed to seed leave policies", err)
+		}
+	}
+	return nil
+}
+
+// defaultApprovalRuleSeeds seeds leave_approval_rules on first start: the line manager
+// approves every request, then HR approves requests over 5 days and maternity leave
+var defaultApprovalRuleSeeds = []string{
+	"(1, 'MANAGER', 0, '', TRUE, $1, $1)",
+	"(2, 'HR', 5, 'MATERNITY', TRUE, $1, $1)",
+}
+
+// seedApprovalRules inserts the default approval rules for steps that do not exist yet
+func seedApprovalRules(db *sql.DB) error {
+	now := time.Now()
+	for _, values := range defaultApprovalRuleSeeds {
+		query := `
+		INSERT INTO leave_approval_rules (step_order, approver_role, over_days, leave_types, is_active, created_at, updated_at)
+		VALUES ` + values + `
+		ON CONFLICT (step_order) DO NOTHING`
+		if DBType == "sqlite" {
+			query = strings.ReplaceAll(query, "$1", "?1")
+		}
+		if _, err := db.Exec(query, now); err != nil {
+			return errors.WrapError("failed to seed approval rules", err)
+		}
+	}
+	return nil
+}
+
 // InitializeSchema initializes the database schema
 func InitializeSchema(db *sql.DB) error {
 	if DBType == "sqlite" {
@@ -150,10 +209,15 @@ func InitializeSchema(db *sql.DB) error {
 			salary REAL NOT NULL,
 			gender VARCHAR(10) NOT NULL DEFAULT 'Male',
 			marital_status BOOLEAN NOT NULL DEFAULT FALSE,
+			location TEXT NOT NULL DEFAULT '',
 			hired_date DATETIME NOT NULL,
 			created_at DATETIME NOT NULL,
 			updated_at DATETIME NOT NULL,
+			manager_id INTEGER,
+			department TEXT NOT NULL DEFAULT '',
+			team TEXT NOT NULL DEFAULT '',
 			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (manager_id) REFERENCES employees(id) ON DELETE SET NULL,
 			CHECK (gender IN ('Male', 'Female'))
 		);`
 
@@ -167,6 +231,7 @@ func InitializeSchema(db *sql.DB) error {
 			"CREATE INDEX IF NOT EXISTS idx_employees_user_id ON employees(user_id);",
 			"CREATE INDEX IF NOT EXISTS idx_employees_email ON employees(email);",
 			"CREATE INDEX IF NOT EXISTS idx_employees_created_at ON employees(created_at);",
+			"CREATE INDEX IF NOT EXISTS idx_employees_manager_id ON employees(manager_id);",
 		}
 
 		for _, query := range indexQueries {
@@ -186,11 +251,15 @@ func InitializeSchema(db *sql.DB) error {
 			start_date DATETIME NOT NULL,
 			end_date DATETIME NOT NULL,
 			reason TEXT,
-			days_count INTEGER NOT NULL,
+			days_count REAL NOT NULL,
+			day_part TEXT NOT NULL DEFAULT 'FULL',
+			hours REAL NOT NULL DEFAULT 0,
 			notes TEXT,
 			approved_by INTEGER,
 			approval_date DATETIME,
 			salary_deduction REAL DEFAULT 0,
+			skipped_days TEXT,
+			event_sequence INTEGER NOT NULL DEFAULT 0,
 			created_at DATETIME NOT NULL,
 			updated_at DATETIME NOT NULL,
 			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
@@ -223,7 +292,7 @@ func InitializeSchema(db *sql.DB) error {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			employee_id INTEGER NOT NULL,
 			leave_type TEXT NOT NULL,
-			balance INTEGER NOT NULL DEFAULT 0,
+			leave_year INTEGER NOT NULL DEFAULT 0,
 			created_at DATETIME NOT NULL,
 			updated_at DATETIME NOT NULL,
 			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
@@ -266,6 +335,9 @@ func InitializeSchema(db *sql.DB) error {
 			error_message TEXT,
 			sent_at DATETIME,
 			next_retry_at DATETIME,
+			attachment_name TEXT NOT NULL DEFAULT '',
+			attachment_type TEXT NOT NULL DEFAULT '',
+			attachment_content TEXT NOT NULL DEFAULT '',
 			created_at DATETIME NOT NULL,
 			updated_at DATETIME NOT NULL,
 			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL
@@ -290,6 +362,562 @@ func InitializeSchema(db *sql.DB) error {
 			}
 		}
 
+		// SQLite holidays table
+		holidaysSchema := `
+		CREATE TABLE IF NOT EXISTS holidays (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			holiday_date DATETIME NOT NULL,
+			year INTEGER NOT NULL,
+			location TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE(holiday_date, location)
+		);`
+
+		_, err = db.Exec(holidaysSchema)
+		if err != nil {
+			return errors.WrapError("failed to create holidays table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_holidays_year_location ON holidays(year, location);")
+		if err != nil {
+			return errors.WrapError("failed to create holidays index (sqlite)", err)
+		}
+
+		// SQLite leave_policies table
+		leavePoliciesSchema := `
+		CREATE TABLE IF NOT EXISTS leave_policies (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			leave_type TEXT NOT NULL UNIQUE,
+			annual_entitlement INTEGER NOT NULL DEFAULT 0,
+			is_paid BOOLEAN NOT NULL DEFAULT FALSE,
+			eligible_gender TEXT NOT NULL DEFAULT '',
+			requires_married BOOLEAN NOT NULL DEFAULT FALSE,
+			min_tenure_days INTEGER NOT NULL DEFAULT 0,
+			max_consecutive_days INTEGER NOT NULL DEFAULT 0,
+			notice_days INTEGER NOT NULL DEFAULT 0,
+			accrual_frequency TEXT NOT NULL DEFAULT 'MONTHLY',
+			carry_forward_max INTEGER NOT NULL DEFAULT 0,
+			encash_excess BOOLEAN NOT NULL DEFAULT FALSE,
+			low_balance_threshold REAL NOT NULL DEFAULT 0,
+			attachment_required_after_days REAL NOT NULL DEFAULT 0,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);`
+
+		_, err = db.Exec(leavePoliciesSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_policies table (sqlite)", err)
+		}
+
+		if err := seedLeavePolicies(db); err != nil {
+			return err
+		}
+
+		// SQLite leave_accruals table (accrual ledger)
+		leaveAccrualsSchema := `
+		CREATE TABLE IF NOT EXISTS leave_accruals (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			leave_type TEXT NOT NULL,
+			period TEXT NOT NULL,
+			days REAL NOT NULL,
+			credited_days REAL NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			UNIQUE(employee_id, leave_type, period)
+		);`
+
+		_, err = db.Exec(leaveAccrualsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_accruals table (sqlite)", err)
+		}
+
+		// SQLite leave_rollovers table (year-end carry-forward, lapse and encashment)
+		leaveRolloversSchema := `
+		CREATE TABLE IF NOT EXISTS leave_rollovers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			leave_type TEXT NOT NULL,
+			year INTEGER NOT NULL,
+			closing_balance REAL NOT NULL,
+			carried_forward REAL NOT NULL DEFAULT 0,
+			lapsed REAL NOT NULL DEFAULT 0,
+			encashed REAL NOT NULL DEFAULT 0,
+			encash_amount REAL NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			UNIQUE(employee_id, leave_type, year)
+		);`
+
+		_, err = db.Exec(leaveRolloversSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_rollovers table (sqlite)", err)
+		}
+
+		// SQLite leave_ledger table (append-only; balances are the sum of days)
+		leaveLedgerSchema := `
+		CREATE TABLE IF NOT EXISTS leave_ledger (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			leave_type TEXT NOT NULL,
+			entry_type TEXT NOT NULL,
+			days REAL NOT NULL,
+			leave_request_id INTEGER,
+			actor_user_id INTEGER,
+			reference TEXT NOT NULL DEFAULT '',
+			description TEXT NOT NULL DEFAULT '',
+			effective_date DATE NOT NULL,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(leaveLedgerSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_ledger table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_ledger_employee_type ON leave_ledger(employee_id, leave_type);")
+		if err != nil {
+			return errors.WrapError("failed to create leave_ledger index (sqlite)", err)
+		}
+
+		// SQLite leave_approval_rules table (configurable approval chain steps)
+		leaveApprovalRulesSchema := `
+		CREATE TABLE IF NOT EXISTS leave_approval_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			step_order INTEGER NOT NULL UNIQUE,
+			approver_role TEXT NOT NULL,
+			over_days REAL NOT NULL DEFAULT 0,
+			leave_types TEXT NOT NULL DEFAULT '',
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		);`
+
+		_, err = db.Exec(leaveApprovalRulesSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_approval_rules table (sqlite)", err)
+		}
+
+		if err := seedApprovalRules(db); err != nil {
+			return err
+		}
+
+		// SQLite leave_approvals table (approval chain of each leave request)
+		leaveApprovalsSchema := `
+		CREATE TABLE IF NOT EXISTS leave_approvals (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			leave_request_id INTEGER NOT NULL,
+			step INTEGER NOT NULL,
+			approver_role TEXT NOT NULL,
+			approver_user_id INTEGER,
+			on_behalf_of_user_id INTEGER,
+			decision TEXT NOT NULL DEFAULT 'PENDING',
+			comment TEXT NOT NULL DEFAULT '',
+			decided_at DATETIME,
+			reminded_at DATETIME,
+			escalated_at DATETIME,
+			info_requested_at DATETIME,
+			paused_seconds INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+			FOREIGN KEY (approver_user_id) REFERENCES users(id) ON DELETE SET NULL,
+			FOREIGN KEY (on_behalf_of_user_id) REFERENCES users(id) ON DELETE SET NULL,
+			UNIQUE(leave_request_id, step)
+		);`
+
+		_, err = db.Exec(leaveApprovalsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_approvals table (sqlite)", err)
+		}
+
+		// Approval delegations
+		approvalDelegationsSchema := `
+		CREATE TABLE IF NOT EXISTS approval_delegations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			delegator_user_id INTEGER NOT NULL,
+			delegate_user_id INTEGER NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			source TEXT NOT NULL DEFAULT 'MANUAL',
+			leave_request_id INTEGER,
+			revoked_at DATETIME,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (delegator_user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (delegate_user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE
+		);`
+
+		_, err = db.Exec(approvalDelegationsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create approval_delegations table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegate ON approval_delegations(delegate_user_id, start_date, end_date);")
+		if err != nil {
+			return errors.WrapError("failed to create approval_delegations index (sqlite)", err)
+		}
+
+		approvalDelegateDefaultsSchema := `
+		CREATE TABLE IF NOT EXISTS approval_delegate_defaults (
+			user_id INTEGER PRIMARY KEY,
+			delegate_user_id INTEGER NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (delegate_user_id) REFERENCES users(id) ON DELETE CASCADE
+		);`
+
+		_, err = db.Exec(approvalDelegateDefaultsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create approval_delegate_defaults table (sqlite)", err)
+		}
+
+		// Low-balance warnings sent, at most one per employee, leave type and accrual period
+		lowBalanceNoticesSchema := `
+		CREATE TABLE IF NOT EXISTS low_balance_notices (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			leave_type TEXT NOT NULL,
+			period TEXT NOT NULL,
+			balance REAL NOT NULL,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			UNIQUE(employee_id, leave_type, period)
+		);`
+
+		_, err = db.Exec(lowBalanceNoticesSchema)
+		if err != nil {
+			return errors.WrapError("failed to create low_balance_notices table (sqlite)", err)
+		}
+
+		// Requests to cancel all or the rest of approved leave
+		leaveCancellationsSchema := `
+		CREATE TABLE IF NOT EXISTS leave_cancellations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			leave_request_id INTEGER NOT NULL,
+			requested_by INTEGER NOT NULL,
+			from_date DATE NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'PENDING',
+			days_refunded REAL NOT NULL DEFAULT 0,
+			salary_refunded REAL NOT NULL DEFAULT 0,
+			decided_by INTEGER,
+			decision_comment TEXT NOT NULL DEFAULT '',
+			decided_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+			FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(leaveCancellationsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_cancellations table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_cancellations_request ON leave_cancellations(leave_request_id, status);")
+		if err != nil {
+			return errors.WrapError("failed to create leave_cancellations index (sqlite)", err)
+		}
+
+		// Edits of pending leave requests, for approvers
+		leaveRequestRevisionsSchema := `
+		CREATE TABLE IF NOT EXISTS leave_request_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			leave_request_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			edited_by INTEGER NOT NULL,
+			changes TEXT NOT NULL DEFAULT '[]',
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+			FOREIGN KEY (edited_by) REFERENCES users(id) ON DELETE CASCADE,
+			UNIQUE(leave_request_id, revision)
+		);`
+
+		_, err = db.Exec(leaveRequestRevisionsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_request_revisions table (sqlite)", err)
+		}
+
+		// Payroll runs, one per pay period; a run locks its period
+		payrollRunsSchema := `
+		CREATE TABLE IF NOT EXISTS payroll_runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			pay_period TEXT NOT NULL UNIQUE,
+			run_by INTEGER,
+			employee_count INTEGER NOT NULL DEFAULT 0,
+			total_gross REAL NOT NULL DEFAULT 0,
+			total_adjustments REAL NOT NULL DEFAULT 0,
+			total_net REAL NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (run_by) REFERENCES users(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(payrollRunsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create payroll_runs table (sqlite)", err)
+		}
+
+		// Amounts added to or taken from pay per pay period
+		payrollAdjustmentsSchema := `
+		CREATE TABLE IF NOT EXISTS payroll_adjustments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			pay_period TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			days REAL NOT NULL DEFAULT 0,
+			daily_rate REAL NOT NULL DEFAULT 0,
+			amount REAL NOT NULL,
+			leave_request_id INTEGER,
+			description TEXT NOT NULL DEFAULT '',
+			created_by INTEGER,
+			payroll_run_id INTEGER,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL,
+			FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+			FOREIGN KEY (payroll_run_id) REFERENCES payroll_runs(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(payrollAdjustmentsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create payroll_adjustments table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_period ON payroll_adjustments(pay_period, employee_id);")
+		if err != nil {
+			return errors.WrapError("failed to create payroll_adjustments index (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_leave_request ON payroll_adjustments(leave_request_id);")
+		if err != nil {
+			return errors.WrapError("failed to create payroll_adjustments index (sqlite)", err)
+		}
+
+		// An employee's pay in a payroll run
+		payslipsSchema := `
+		CREATE TABLE IF NOT EXISTS payslips (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			payroll_run_id INTEGER NOT NULL,
+			employee_id INTEGER NOT NULL,
+			employee_name TEXT NOT NULL,
+			position TEXT NOT NULL DEFAULT '',
+			pay_period TEXT NOT NULL,
+			gross_pay REAL NOT NULL,
+			total_adjustments REAL NOT NULL DEFAULT 0,
+			net_pay REAL NOT NULL,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (payroll_run_id) REFERENCES payroll_runs(id) ON DELETE CASCADE,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			UNIQUE(payroll_run_id, employee_id)
+		);`
+
+		_, err = db.Exec(payslipsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create payslips table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payslips_employee ON payslips(employee_id, pay_period);")
+		if err != nil {
+			return errors.WrapError("failed to create payslips index (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_run ON payroll_adjustments(payroll_run_id);")
+		if err != nil {
+			return errors.WrapError("failed to create payroll_adjustments index (sqlite)", err)
+		}
+
+		// Effective-dated salary and position changes
+		employeeCompensationSchema := `
+		CREATE TABLE IF NOT EXISTS employee_compensation (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			effective_date DATETIME NOT NULL,
+			salary REAL,
+			position TEXT,
+			reason TEXT NOT NULL DEFAULT '',
+			created_by INTEGER,
+			applied_at DATETIME,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(employeeCompensationSchema)
+		if err != nil {
+			return errors.WrapError("failed to create employee_compensation table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_employee_compensation_employee ON employee_compensation(employee_id, effective_date);")
+		if err != nil {
+			return errors.WrapError("failed to create employee_compensation index (sqlite)", err)
+		}
+
+		// Supporting documents of leave requests; the files are kept in attachment storage
+		leaveAttachmentsSchema := `
+		CREATE TABLE IF NOT EXISTS leave_attachments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			leave_request_id INTEGER NOT NULL,
+			file_name TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size INTEGER NOT NULL,
+			storage_key TEXT NOT NULL UNIQUE,
+			uploaded_by INTEGER,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+			FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(leaveAttachmentsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_attachments table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_attachments_request ON leave_attachments(leave_request_id);")
+		if err != nil {
+			return errors.WrapError("failed to create leave_attachments index (sqlite)", err)
+		}
+
+		// Comment threads on leave requests
+		leaveCommentsSchema := `
+		CREATE TABLE IF NOT EXISTS leave_comments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			leave_request_id INTEGER NOT NULL,
+			author_user_id INTEGER,
+			author_name TEXT NOT NULL,
+			body TEXT NOT NULL,
+			requests_info INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+			FOREIGN KEY (author_user_id) REFERENCES users(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(leaveCommentsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_comments table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_comments_request ON leave_comments(leave_request_id);")
+		if err != nil {
+			return errors.WrapError("failed to create leave_comments index (sqlite)", err)
+		}
+
+		auditLogsSchema := `
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			table_name TEXT NOT NULL,
+			operation TEXT NOT NULL,
+			record_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			old_values TEXT,
+			new_values TEXT,
+			ip_address TEXT NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT '',
+			reason TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL
+		);`
+
+		_, err = db.Exec(auditLogsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create audit_logs table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_record ON audit_logs(table_name, record_id);")
+		if err != nil {
+			return errors.WrapError("failed to create audit_logs record index (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs(user_id);")
+		if err != nil {
+			return errors.WrapError("failed to create audit_logs user index (sqlite)", err)
+		}
+
+		// SQLite leave_coverage_rules table (most members of a team or department out at once)
+		leaveCoverageRulesSchema := `
+		CREATE TABLE IF NOT EXISTS leave_coverage_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL DEFAULT '',
+			scope TEXT NOT NULL,
+			group_name TEXT NOT NULL,
+			max_absent INTEGER NOT NULL,
+			action TEXT NOT NULL DEFAULT 'WARN',
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE (scope, group_name)
+		);`
+
+		_, err = db.Exec(leaveCoverageRulesSchema)
+		if err != nil {
+			return errors.WrapError("failed to create leave_coverage_rules table (sqlite)", err)
+		}
+
+		// SQLite calendar_feeds table (signed links to a user's leave calendar)
+		calendarFeedsSchema := `
+		CREATE TABLE IF NOT EXISTS calendar_feeds (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL,
+			nonce TEXT NOT NULL,
+			created_at DATETIME NOT NULL,
+			revoked_at DATETIME,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);`
+
+		_, err = db.Exec(calendarFeedsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create calendar_feeds table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user ON calendar_feeds(user_id);")
+		if err != nil {
+			return errors.WrapError("failed to create calendar_feeds index (sqlite)", err)
+		}
+
+		// SQLite comp_off_credits table (comp-off earned by working on weekends and holidays)
+		compOffCreditsSchema := `
+		CREATE TABLE IF NOT EXISTS comp_off_credits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			employee_id INTEGER NOT NULL,
+			requested_by INTEGER NOT NULL,
+			worked_date DATE NOT NULL,
+			day_part TEXT NOT NULL DEFAULT 'FULL',
+			days REAL NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'PENDING',
+			decided_by INTEGER,
+			decision_comment TEXT NOT NULL DEFAULT '',
+			decided_at DATETIME,
+			expires_on DATE,
+			days_expired REAL NOT NULL DEFAULT 0,
+			expired_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+			FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL
+		);`
+
+		_, err = db.Exec(compOffCreditsSchema)
+		if err != nil {
+			return errors.WrapError("failed to create comp_off_credits table (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_comp_off_credits_employee ON comp_off_credits(employee_id, worked_date);")
+		if err != nil {
+			return errors.WrapError("failed to create comp_off_credits employee index (sqlite)", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_comp_off_credits_expiry ON comp_off_credits(status, expires_on);")
+		if err != nil {
+			return errors.WrapError("failed to create comp_off_credits expiry index (sqlite)", err)
+		}
+
 		errors.LogInfo("SQLite database schema initialized successfully")
 		return nil
 	}
@@ -323,10 +951,15 @@ func InitializeSchema(db *sql.DB) error {
 		salary DECIMAL(10, 2) NOT NULL,
 		gender VARCHAR(10),
 		marital_status BOOLEAN DEFAULT FALSE,
+		location VARCHAR(100) NOT NULL DEFAULT '',
 		hired_date TIMESTAMP NOT NULL,
 		created_at TIMESTAMP NOT NULL,
 		updated_at TIMESTAMP NOT NULL,
-		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		manager_id INTEGER,
+		department VARCHAR(100) NOT NULL DEFAULT '',
+		team VARCHAR(100) NOT NULL DEFAULT '',
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (manager_id) REFERENCES employees(id) ON DELETE SET NULL
 	);`
 
 	_, err = db.Exec(tableSchema)
@@ -339,6 +972,7 @@ func InitializeSchema(db *sql.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_employees_user_id ON employees(user_id);",
 		"CREATE INDEX IF NOT EXISTS idx_employees_email ON employees(email);",
 		"CREATE INDEX IF NOT EXISTS idx_employees_created_at ON employees(created_at);",
+		"CREATE INDEX IF NOT EXISTS idx_employees_manager_id ON employees(manager_id);",
 	}
 
 	for _, query := range indexQueries {
@@ -363,11 +997,15 @@ func InitializeSchema(db *sql.DB) error {
 		start_date TIMESTAMP NOT NULL,
 		end_date TIMESTAMP NOT NULL,
 		reason TEXT,
-		days_count INTEGER NOT NULL,
+		days_count DECIMAL(6, 3) NOT NULL,
+		day_part VARCHAR(20) NOT NULL DEFAULT 'FULL',
+		hours DECIMAL(4, 2) NOT NULL DEFAULT 0,
 		notes TEXT,
 		approved_by INTEGER,
 		approval_date TIMESTAMP,
 		salary_deduction DECIMAL(10, 2) DEFAULT 0,
+		skipped_days TEXT,
+		event_sequence INTEGER NOT NULL DEFAULT 0,
 		created_at TIMESTAMP NOT NULL,
 		updated_at TIMESTAMP NOT NULL,
 		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
@@ -400,7 +1038,7 @@ func InitializeSchema(db *sql.DB) error {
 		id SERIAL PRIMARY KEY,
 		employee_id INTEGER NOT NULL,
 		leave_type VARCHAR(50) NOT NULL,
-		balance INTEGER NOT NULL DEFAULT 0,
+		leave_year INTEGER NOT NULL DEFAULT 0,
 		created_at TIMESTAMP NOT NULL,
 		updated_at TIMESTAMP NOT NULL,
 		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
@@ -445,6 +1083,9 @@ func InitializeSchema(db *sql.DB) error {
 		error_message TEXT,
 		sent_at TIMESTAMP,
 		next_retry_at TIMESTAMP,
+		attachment_name VARCHAR(255) NOT NULL DEFAULT '',
+		attachment_type VARCHAR(100) NOT NULL DEFAULT '',
+		attachment_content TEXT NOT NULL DEFAULT '',
 		created_at TIMESTAMP NOT NULL,
 		updated_at TIMESTAMP NOT NULL,
 		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL
@@ -471,6 +1112,590 @@ func InitializeSchema(db *sql.DB) error {
 	}
 	errors.LogInfo("✅ notifications indexes created successfully")
 
+	// Create holidays table
+	holidaysTableSchema := `
+	CREATE TABLE IF NOT EXISTS holidays (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		holiday_date DATE NOT NULL,
+		year INTEGER NOT NULL,
+		location VARCHAR(100) NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		UNIQUE(holiday_date, location)
+	);`
+
+	_, err = db.Exec(holidaysTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create holidays table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_holidays_year_location ON holidays(year, location);")
+	if err != nil {
+		return errors.WrapError("failed to create holidays index", err)
+	}
+	errors.LogInfo("✅ holidays table created successfully")
+
+	// Create leave_policies table
+	leavePoliciesTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_policies (
+		id SERIAL PRIMARY KEY,
+		leave_type VARCHAR(50) NOT NULL UNIQUE,
+		annual_entitlement INTEGER NOT NULL DEFAULT 0,
+		is_paid BOOLEAN NOT NULL DEFAULT FALSE,
+		eligible_gender VARCHAR(10) NOT NULL DEFAULT '',
+		requires_married BOOLEAN NOT NULL DEFAULT FALSE,
+		min_tenure_days INTEGER NOT NULL DEFAULT 0,
+		max_consecutive_days INTEGER NOT NULL DEFAULT 0,
+		notice_days INTEGER NOT NULL DEFAULT 0,
+		accrual_frequency VARCHAR(10) NOT NULL DEFAULT 'MONTHLY',
+		carry_forward_max INTEGER NOT NULL DEFAULT 0,
+		encash_excess BOOLEAN NOT NULL DEFAULT FALSE,
+		low_balance_threshold DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		attachment_required_after_days DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);`
+
+	_, err = db.Exec(leavePoliciesTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_policies table", err)
+	}
+
+	if err := seedLeavePolicies(db); err != nil {
+		return err
+	}
+	errors.LogInfo("✅ leave_policies table created successfully")
+
+	// Create leave_accruals table (accrual ledger)
+	leaveAccrualsTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_accruals (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		leave_type VARCHAR(50) NOT NULL,
+		period VARCHAR(7) NOT NULL,
+		days DECIMAL(6, 3) NOT NULL,
+		credited_days DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		UNIQUE(employee_id, leave_type, period)
+	);`
+
+	_, err = db.Exec(leaveAccrualsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_accruals table", err)
+	}
+	errors.LogInfo("✅ leave_accruals table created successfully")
+
+	// Create leave_rollovers table (year-end carry-forward, lapse and encashment)
+	leaveRolloversTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_rollovers (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		leave_type VARCHAR(50) NOT NULL,
+		year INTEGER NOT NULL,
+		closing_balance DECIMAL(8, 3) NOT NULL,
+		carried_forward DECIMAL(8, 3) NOT NULL DEFAULT 0,
+		lapsed DECIMAL(8, 3) NOT NULL DEFAULT 0,
+		encashed DECIMAL(8, 3) NOT NULL DEFAULT 0,
+		encash_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		UNIQUE(employee_id, leave_type, year)
+	);`
+
+	_, err = db.Exec(leaveRolloversTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_rollovers table", err)
+	}
+	errors.LogInfo("✅ leave_rollovers table created successfully")
+
+	// Create leave_ledger table (append-only; balances are the sum of days)
+	leaveLedgerTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_ledger (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		leave_type VARCHAR(50) NOT NULL,
+		entry_type VARCHAR(20) NOT NULL,
+		days DECIMAL(8, 3) NOT NULL,
+		leave_request_id INTEGER,
+		actor_user_id INTEGER,
+		reference VARCHAR(100) NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		effective_date DATE NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(leaveLedgerTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_ledger table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_ledger_employee_type ON leave_ledger(employee_id, leave_type);")
+	if err != nil {
+		return errors.WrapError("failed to create leave_ledger index", err)
+	}
+	errors.LogInfo("✅ leave_ledger table created successfully")
+
+	// Create leave_approval_rules table (configurable approval chain steps)
+	leaveApprovalRulesTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_approval_rules (
+		id SERIAL PRIMARY KEY,
+		step_order INTEGER NOT NULL UNIQUE,
+		approver_role VARCHAR(20) NOT NULL,
+		over_days DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		leave_types TEXT NOT NULL DEFAULT '',
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);`
+
+	_, err = db.Exec(leaveApprovalRulesTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_approval_rules table", err)
+	}
+	if err := seedApprovalRules(db); err != nil {
+		return err
+	}
+	errors.LogInfo("✅ leave_approval_rules table created successfully")
+
+	// Create leave_approvals table (approval chain of each leave request)
+	leaveApprovalsTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_approvals (
+		id SERIAL PRIMARY KEY,
+		leave_request_id INTEGER NOT NULL,
+		step INTEGER NOT NULL,
+		approver_role VARCHAR(20) NOT NULL,
+		approver_user_id INTEGER,
+		on_behalf_of_user_id INTEGER,
+		decision VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+		comment TEXT NOT NULL DEFAULT '',
+		decided_at TIMESTAMP,
+		reminded_at TIMESTAMP,
+		escalated_at TIMESTAMP,
+		info_requested_at TIMESTAMP,
+		paused_seconds INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+		FOREIGN KEY (approver_user_id) REFERENCES users(id) ON DELETE SET NULL,
+		FOREIGN KEY (on_behalf_of_user_id) REFERENCES users(id) ON DELETE SET NULL,
+		UNIQUE(leave_request_id, step)
+	);`
+
+	_, err = db.Exec(leaveApprovalsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_approvals table", err)
+	}
+	errors.LogInfo("✅ leave_approvals table created successfully")
+
+	// Create approval_delegations table
+	approvalDelegationsTableSchema := `
+	CREATE TABLE IF NOT EXISTS approval_delegations (
+		id SERIAL PRIMARY KEY,
+		delegator_user_id INTEGER NOT NULL,
+		delegate_user_id INTEGER NOT NULL,
+		start_date DATE NOT NULL,
+		end_date DATE NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		source VARCHAR(20) NOT NULL DEFAULT 'MANUAL',
+		leave_request_id INTEGER,
+		revoked_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (delegator_user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (delegate_user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE
+	);`
+
+	_, err = db.Exec(approvalDelegationsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create approval_delegations table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegate ON approval_delegations(delegate_user_id, start_date, end_date);")
+	if err != nil {
+		return errors.WrapError("failed to create approval_delegations index", err)
+	}
+	errors.LogInfo("✅ approval_delegations table created successfully")
+
+	// Create approval_delegate_defaults table
+	approvalDelegateDefaultsTableSchema := `
+	CREATE TABLE IF NOT EXISTS approval_delegate_defaults (
+		user_id INTEGER PRIMARY KEY,
+		delegate_user_id INTEGER NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (delegate_user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`
+
+	_, err = db.Exec(approvalDelegateDefaultsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create approval_delegate_defaults table", err)
+	}
+	errors.LogInfo("✅ approval_delegate_defaults table created successfully")
+
+	// Create low_balance_notices table (one low-balance warning per employee, leave type and accrual period)
+	lowBalanceNoticesTableSchema := `
+	CREATE TABLE IF NOT EXISTS low_balance_notices (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		leave_type VARCHAR(50) NOT NULL,
+		period VARCHAR(7) NOT NULL,
+		balance DECIMAL(6, 3) NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		UNIQUE(employee_id, leave_type, period)
+	);`
+
+	_, err = db.Exec(lowBalanceNoticesTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create low_balance_notices table", err)
+	}
+	errors.LogInfo("✅ low_balance_notices table created successfully")
+
+	// Create leave_cancellations table (requests to cancel all or the rest of approved leave)
+	leaveCancellationsTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_cancellations (
+		id SERIAL PRIMARY KEY,
+		leave_request_id INTEGER NOT NULL,
+		requested_by INTEGER NOT NULL,
+		from_date DATE NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+		days_refunded DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		salary_refunded DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		decided_by INTEGER,
+		decision_comment TEXT NOT NULL DEFAULT '',
+		decided_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+		FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(leaveCancellationsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_cancellations table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_cancellations_request ON leave_cancellations(leave_request_id, status);")
+	if err != nil {
+		return errors.WrapError("failed to create leave_cancellations index", err)
+	}
+	errors.LogInfo("✅ leave_cancellations table created successfully")
+
+	// Create leave_request_revisions table (edits of pending leave requests, for approvers)
+	leaveRequestRevisionsTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_request_revisions (
+		id SERIAL PRIMARY KEY,
+		leave_request_id INTEGER NOT NULL,
+		revision INTEGER NOT NULL,
+		edited_by INTEGER NOT NULL,
+		changes TEXT NOT NULL DEFAULT '[]',
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+		FOREIGN KEY (edited_by) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(leave_request_id, revision)
+	);`
+
+	_, err = db.Exec(leaveRequestRevisionsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_request_revisions table", err)
+	}
+	errors.LogInfo("✅ leave_request_revisions table created successfully")
+
+	// Create payroll_runs table (one per pay period; a run locks its period)
+	payrollRunsTableSchema := `
+	CREATE TABLE IF NOT EXISTS payroll_runs (
+		id SERIAL PRIMARY KEY,
+		pay_period VARCHAR(7) NOT NULL UNIQUE,
+		run_by INTEGER,
+		employee_count INTEGER NOT NULL DEFAULT 0,
+		total_gross DECIMAL(12, 2) NOT NULL DEFAULT 0,
+		total_adjustments DECIMAL(12, 2) NOT NULL DEFAULT 0,
+		total_net DECIMAL(12, 2) NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (run_by) REFERENCES users(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(payrollRunsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create payroll_runs table", err)
+	}
+	errors.LogInfo("✅ payroll_runs table created successfully")
+
+	// Create payroll_adjustments table (amounts added to or taken from pay per pay period)
+	payrollAdjustmentsTableSchema := `
+	CREATE TABLE IF NOT EXISTS payroll_adjustments (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		pay_period VARCHAR(7) NOT NULL,
+		kind VARCHAR(30) NOT NULL,
+		days DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		daily_rate DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		amount DECIMAL(10, 2) NOT NULL,
+		leave_request_id INTEGER,
+		description TEXT NOT NULL DEFAULT '',
+		created_by INTEGER,
+		payroll_run_id INTEGER,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE SET NULL,
+		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+		FOREIGN KEY (payroll_run_id) REFERENCES payroll_runs(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(payrollAdjustmentsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create payroll_adjustments table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_period ON payroll_adjustments(pay_period, employee_id);")
+	if err != nil {
+		return errors.WrapError("failed to create payroll_adjustments index", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_leave_request ON payroll_adjustments(leave_request_id);")
+	if err != nil {
+		return errors.WrapError("failed to create payroll_adjustments index", err)
+	}
+	errors.LogInfo("✅ payroll_adjustments table created successfully")
+
+	// Create payslips table (an employee's pay in a payroll run)
+	payslipsTableSchema := `
+	CREATE TABLE IF NOT EXISTS payslips (
+		id SERIAL PRIMARY KEY,
+		payroll_run_id INTEGER NOT NULL,
+		employee_id INTEGER NOT NULL,
+		employee_name VARCHAR(200) NOT NULL,
+		position VARCHAR(100) NOT NULL DEFAULT '',
+		pay_period VARCHAR(7) NOT NULL,
+		gross_pay DECIMAL(10, 2) NOT NULL,
+		total_adjustments DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		net_pay DECIMAL(10, 2) NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (payroll_run_id) REFERENCES payroll_runs(id) ON DELETE CASCADE,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		UNIQUE(payroll_run_id, employee_id)
+	);`
+
+	_, err = db.Exec(payslipsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create payslips table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payslips_employee ON payslips(employee_id, pay_period);")
+	if err != nil {
+		return errors.WrapError("failed to create payslips index", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_payroll_adjustments_run ON payroll_adjustments(payroll_run_id);")
+	if err != nil {
+		return errors.WrapError("failed to create payroll_adjustments index", err)
+	}
+	errors.LogInfo("✅ payslips table created successfully")
+
+	// Create employee_compensation table (effective-dated salary and position changes)
+	employeeCompensationTableSchema := `
+	CREATE TABLE IF NOT EXISTS employee_compensation (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		effective_date DATE NOT NULL,
+		salary DECIMAL(10, 2),
+		position VARCHAR(100),
+		reason TEXT NOT NULL DEFAULT '',
+		created_by INTEGER,
+		applied_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(employeeCompensationTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create employee_compensation table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_employee_compensation_employee ON employee_compensation(employee_id, effective_date);")
+	if err != nil {
+		return errors.WrapError("failed to create employee_compensation index", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_employee_compensation_pending ON employee_compensation(effective_date) WHERE applied_at IS NULL;")
+	if err != nil {
+		return errors.WrapError("failed to create employee_compensation index", err)
+	}
+	errors.LogInfo("✅ employee_compensation table created successfully")
+
+	// Create leave_attachments table (supporting documents of leave requests)
+	leaveAttachmentsTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_attachments (
+		id SERIAL PRIMARY KEY,
+		leave_request_id INTEGER NOT NULL,
+		file_name VARCHAR(255) NOT NULL,
+		content_type VARCHAR(100) NOT NULL,
+		size BIGINT NOT NULL,
+		storage_key VARCHAR(255) NOT NULL UNIQUE,
+		uploaded_by INTEGER,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+		FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(leaveAttachmentsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_attachments table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_attachments_request ON leave_attachments(leave_request_id);")
+	if err != nil {
+		return errors.WrapError("failed to create leave_attachments index", err)
+	}
+	errors.LogInfo("✅ leave_attachments table created successfully")
+
+	// Create leave_comments table (comment thread of each leave request)
+	leaveCommentsTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_comments (
+		id SERIAL PRIMARY KEY,
+		leave_request_id INTEGER NOT NULL,
+		author_user_id INTEGER,
+		author_name VARCHAR(255) NOT NULL,
+		body TEXT NOT NULL,
+		requests_info BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (leave_request_id) REFERENCES leave_requests(id) ON DELETE CASCADE,
+		FOREIGN KEY (author_user_id) REFERENCES users(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(leaveCommentsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_comments table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_leave_comments_request ON leave_comments(leave_request_id);")
+	if err != nil {
+		return errors.WrapError("failed to create leave_comments index", err)
+	}
+	errors.LogInfo("✅ leave_comments table created successfully")
+
+	// Create audit_logs table (who changed which record, e.g. leave entered on behalf of an employee)
+	auditLogsTableSchema := `
+	CREATE TABLE IF NOT EXISTS audit_logs (
+		id SERIAL PRIMARY KEY,
+		table_name VARCHAR(100) NOT NULL,
+		operation VARCHAR(10) NOT NULL,
+		record_id BIGINT NOT NULL,
+		user_id BIGINT NOT NULL,
+		old_values JSONB,
+		new_values JSONB,
+		ip_address VARCHAR(45) NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL
+	);`
+
+	_, err = db.Exec(auditLogsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create audit_logs table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_record ON audit_logs(table_name, record_id);")
+	if err != nil {
+		return errors.WrapError("failed to create audit_logs record index", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_user ON audit_logs(user_id);")
+	if err != nil {
+		return errors.WrapError("failed to create audit_logs user index", err)
+	}
+	errors.LogInfo("✅ audit_logs table created successfully")
+
+	// Create leave_coverage_rules table (most members of a team or department out at once)
+	leaveCoverageRulesTableSchema := `
+	CREATE TABLE IF NOT EXISTS leave_coverage_rules (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL DEFAULT '',
+		scope VARCHAR(20) NOT NULL,
+		group_name VARCHAR(100) NOT NULL,
+		max_absent INTEGER NOT NULL,
+		action VARCHAR(20) NOT NULL DEFAULT 'WARN',
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		UNIQUE (scope, group_name)
+	);`
+
+	_, err = db.Exec(leaveCoverageRulesTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create leave_coverage_rules table", err)
+	}
+	errors.LogInfo("✅ leave_coverage_rules table created successfully")
+
+	// Create calendar_feeds table (signed links to a user's leave calendar)
+	calendarFeedsTableSchema := `
+	CREATE TABLE IF NOT EXISTS calendar_feeds (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		nonce VARCHAR(64) NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		revoked_at TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);`
+
+	_, err = db.Exec(calendarFeedsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create calendar_feeds table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user ON calendar_feeds(user_id);")
+	if err != nil {
+		return errors.WrapError("failed to create calendar_feeds index", err)
+	}
+	errors.LogInfo("✅ calendar_feeds table created successfully")
+
+	// Create comp_off_credits table (comp-off earned by working on weekends and holidays)
+	compOffCreditsTableSchema := `
+	CREATE TABLE IF NOT EXISTS comp_off_credits (
+		id SERIAL PRIMARY KEY,
+		employee_id INTEGER NOT NULL,
+		requested_by INTEGER NOT NULL,
+		worked_date DATE NOT NULL,
+		day_part VARCHAR(20) NOT NULL DEFAULT 'FULL',
+		days DECIMAL(6, 3) NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+		decided_by INTEGER,
+		decision_comment TEXT NOT NULL DEFAULT '',
+		decided_at TIMESTAMP,
+		expires_on DATE,
+		days_expired DECIMAL(6, 3) NOT NULL DEFAULT 0,
+		expired_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE,
+		FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL
+	);`
+
+	_, err = db.Exec(compOffCreditsTableSchema)
+	if err != nil {
+		return errors.WrapError("failed to create comp_off_credits table", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_comp_off_credits_employee ON comp_off_credits(employee_id, worked_date);")
+	if err != nil {
+		return errors.WrapError("failed to create comp_off_credits employee index", err)
+	}
+
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_comp_off_credits_expiry ON comp_off_credits(status, expires_on);")
+	if err != nil {
+		return errors.WrapError("failed to create comp_off_credits expiry index", err)
+	}
+	errors.LogInfo("✅ comp_off_credits table created successfully")
+
 	errors.LogInfo("Database schema initialized successfully")
 	return nil
 }
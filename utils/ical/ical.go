@@ -0,0 +1,147 @@
+package ical
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Methods of a calendar (RFC 5546): a feed publishes events, an email invites to or cancels one
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineOctets is the longest a content line may be before it is folded
+const maxLineOctets = 75
+
+// Event is an all-day event spanning Start to End inclusive
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time // Last day of the event
+	Sequence    int       // Raised each time the event changes, so clients replace their copy
+	Status      string
+	Stamp       time.Time    // When the event was last changed
+	Organizer   mail.Address // Who sends an invite or cancellation; left out of a published feed
+	Attendee    mail.Address // Who an invite or cancellation is for; left out of a published feed
+}
+
+// Calendar is an iCalendar object holding events
+type Calendar struct {
+	Name   string
+	Method string
+	Events []Event
+}
+
+// Render encodes the calendar as RFC 5545 text with CRLF line endings and long lines folded
+func (c *Calendar) Render() []byte {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//HR Management System//Leave Calendar//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	if c.Method != "" {
+		lines = append(lines, "METHOD:"+c.Method)
+	}
+	if c.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		status := e.Status
+		if status == "" {
+			status = StatusConfirmed
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+escape(e.UID),
+			"DTSTAMP:"+e.Stamp.UTC().Format("20060102T150405Z"),
+			"DTSTART;VALUE=DATE:"+e.Start.Format("20060102"),
+			// The end of an all-day event is the day after it
+			"DTEND;VALUE=DATE:"+e.End.AddDate(0, 0, 1).Format("20060102"),
+			fmt.Sprintf("SEQUENCE:%d", e.Sequence),
+			"STATUS:"+status,
+			"SUMMARY:"+escape(e.Summary),
+		)
+		if e.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escape(e.Description))
+		}
+		// Scheduling messages must say who sent the event and who it is for (RFC 5546)
+		if c.Method != "" && c.Method != MethodPublish {
+			if e.Organizer.Address != "" {
+				lines = append(lines, "ORGANIZER"+commonName(e.Organizer.Name)+":mailto:"+e.Organizer.Address)
+			}
+			if e.Attendee.Address != "" {
+				// The employee asked for the leave, so there is nothing for them to reply to
+				lines = append(lines, "ATTENDEE"+commonName(e.Attendee.Name)+";ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;RSVP=FALSE:mailto:"+e.Attendee.Address)
+			}
+		}
+		// A feed may hold other people's leave, which must not mark the subscriber as busy
+		lines = append(lines, "TRANSP:TRANSPARENT", "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(fold(line))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// commonName encodes a name as the CN parameter of a calendar user, or nothing without a name.
+// Parameter values are quoted and cannot contain quotes or line breaks themselves.
+func commonName(name string) string {
+	name = strings.TrimSpace(strings.NewReplacer(`"`, "", "\r", "", "\n", "").Replace(name))
+	if name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
+
+// escape encodes text as an iCalendar TEXT value
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+// fold splits a content line longer than 75 octets into continuation lines starting with a
+// space, never inside a UTF-8 character
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	limit := maxLineOctets
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 0
+			limit = maxLineOctets - 1 // The leading space counts
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"employee-service/errors"
+)
+
+// Job is a task the scheduler runs periodically
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler runs background jobs, each once on start and then once every interval, until stopped
+type Scheduler struct {
+	jobs    []Job
+	stop    chan struct{}
+	mu      sync.Mutex
+	running bool
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler with no jobs
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job to the scheduler. Jobs must be registered before the scheduler starts.
+func (s *Scheduler) Register(name string, interval time.Duration, run func() error) error {
+	if interval <= 0 {
+		return fmt.Errorf("%s job needs a positive interval", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return fmt.Errorf("scheduler already running, cannot register %s job", name)
+	}
+	s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: run})
+	return nil
+}
+
+// Start runs every registered job immediately and then once every interval
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduler already running")
+	}
+	s.running = true
+	s.stop = make(chan struct{})
+	jobs := s.jobs
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+		errors.LogInfo(fmt.Sprintf("Scheduled %s job started (interval: %s)", job.Name, job.Interval))
+	}
+	return nil
+}
+
+// Stop stops the scheduler and waits for running jobs to finish
+func (s *Scheduler) Stop() error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduler not running")
+	}
+	s.running = false
+	s.mu.Unlock()
+
+	close(s.stop)
+	s.wg.Wait()
+
+	errors.LogInfo("Scheduler stopped")
+	return nil
+}
+
+// loop runs a job on every tick until stopped
+func (s *Scheduler) loop(job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.run(job)
+	for {
+		select {
+		case <-ticker.C:
+			s.run(job)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// run executes a job once, logging a failure instead of stopping the schedule
+func (s *Scheduler) run(job Job) {
+	if err := job.Run(); err != nil {
+		errors.LogError(fmt.Sprintf("Scheduled %s failed", job.Name), err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package storage keeps uploaded files. Callers choose the keys; implementations decide where the
+// bytes live, so the local filesystem can be swapped for an object store.
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"employee-service/errors"
+)
+
+// Storage saves, reads and deletes files by key. Keys are slash-separated relative paths such as
+// "leave-requests/12/3f9c".
+type Storage interface {
+	Save(key string, content io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// LocalStorage keeps files under a directory on the local filesystem
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage creates a local storage rooted at dir, creating the directory if needed
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.WrapError("failed to resolve storage directory", err)
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, errors.WrapError("failed to create storage directory", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// Save writes a file, replacing any file with the same key. The content is written to a temporary
+// file first so readers never see a partial file.
+func (s *LocalStorage) Save(key string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return errors.WrapError("failed to create storage directory", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return errors.WrapError("failed to create file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return errors.WrapError("failed to write file", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WrapError("failed to write file", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.WrapError("failed to save file", err)
+	}
+	return nil
+}
+
+// Open opens a file for reading
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, errors.NewNotFoundError("file not found")
+	}
+	if err != nil {
+		return nil, errors.WrapError("failed to open file", err)
+	}
+	return f, nil
+}
+
+// Delete removes a file; deleting a missing file is not an error
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.WrapError("failed to delete file", err)
+	}
+	return nil
+}
+
+// path maps a key to a file under the root, refusing keys that would escape it
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." ||
+		strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	return filepath.Join(s.root, cleaned), nil
+}